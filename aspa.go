@@ -0,0 +1,255 @@
+package clidecode
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+)
+
+const (
+	// ASPAUnknown = ASPA Unknown, as some hop has no attestation
+	ASPAUnknown = iota
+	// ASPAValid = ASPA Valid
+	ASPAValid
+	// ASPAInvalid = ASPA Invalid, a route leak or forged path
+	ASPAInvalid
+)
+
+// hop results of the provider authorization function
+const (
+	hopNoAttestation = iota
+	hopProvider
+	hopNotProvider
+)
+
+// ASPASet verifies AS paths against a set of ASPAs, as described in
+// draft-ietf-sidrops-aspa-verification. A nil ASPASet knows no ASPAs, so it
+// finds every path with more than one AS unknown.
+//
+// ASPAs can be read from an rpki-client or Routinator JSON export with
+// LoadASPASet, or taken from an RTRClient speaking version 2.
+type ASPASet struct {
+	providers map[uint32][]uint32
+}
+
+// NewASPASet builds an ASPASet. If a customer is listed more than once, the
+// providers are merged.
+func NewASPASet(aspas []ASPA) *ASPASet {
+	s := &ASPASet{providers: make(map[uint32][]uint32)}
+	for _, a := range aspas {
+		s.providers[a.Customer] = append(s.providers[a.Customer], a.Providers...)
+	}
+	for c, p := range s.providers {
+		slices.Sort(p)
+		s.providers[c] = slices.Compact(p)
+	}
+	return s
+}
+
+// LoadASPASet builds an ASPASet from the aspas of a JSON export file
+func LoadASPASet(path string) (*ASPASet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	aspas, err := ReadASPAs(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return NewASPASet(aspas), nil
+}
+
+// aspaExport is the aspas part of an rpki-client or Routinator JSON export.
+// rpki-client names the customer customer_asid, Routinator just customer.
+type aspaExport struct {
+	ASPAs []struct {
+		CustomerASID vrpASN   `json:"customer_asid"`
+		Customer     vrpASN   `json:"customer"`
+		Providers    []vrpASN `json:"providers"`
+	} `json:"aspas"`
+}
+
+// ReadASPAs reads the aspas from a JSON export, such as written by rpki-client
+func ReadASPAs(r io.Reader) ([]ASPA, error) {
+	var export aspaExport
+	if err := json.NewDecoder(bufio.NewReader(r)).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid ASPA JSON: %w", err)
+	}
+	aspas := make([]ASPA, 0, len(export.ASPAs))
+	for _, e := range export.ASPAs {
+		a := ASPA{Customer: uint32(e.CustomerASID)}
+		if a.Customer == 0 {
+			a.Customer = uint32(e.Customer)
+		}
+		for _, p := range e.Providers {
+			a.Providers = append(a.Providers, uint32(p))
+		}
+		aspas = append(aspas, a)
+	}
+	return aspas, nil
+}
+
+// Len returns the number of customer ASNs with an ASPA.
+func (s *ASPASet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.providers)
+}
+
+// Providers returns the authorised providers of customer, if it has an ASPA.
+func (s *ASPASet) Providers(customer uint32) ([]uint32, bool) {
+	if s == nil {
+		return nil, false
+	}
+	p, ok := s.providers[customer]
+	return slices.Clone(p), ok
+}
+
+// hop is the provider authorization function: is provider an authorised provider of customer?
+func (s *ASPASet) hop(customer, provider uint32) int {
+	if s == nil {
+		return hopNoAttestation
+	}
+	p, ok := s.providers[customer]
+	if !ok {
+		return hopNoAttestation
+	}
+	if _, found := slices.BinarySearch(p, provider); found {
+		return hopProvider
+	}
+	return hopNotProvider
+}
+
+// Verify returns the ASPA state of an AS path.
+// Upstream verification applies to routes received from a customer, lateral
+// peer or route server client, downstream verification to routes received
+// from a provider. Any AS set makes a path invalid. An empty path, as for
+// locally originated routes, has nothing to verify and is unknown.
+func (s *ASPASet) Verify(path ASPath, upstream bool) int {
+	if len(path.Set) > 0 {
+		return ASPAInvalid
+	}
+	if len(path.Path) == 0 {
+		return ASPAUnknown
+	}
+
+	// Collapse prepends, and number the ASNs from the origin as AS(1)
+	as := slices.Compact(slices.Clone(path.Path))
+	slices.Reverse(as)
+	n := len(as)
+	if n == 1 || (!upstream && n == 2) {
+		return ASPAValid
+	}
+
+	// Up-ramp: from the origin, while each AS is a customer of the next
+	maxUp, minUp := n, n
+	for i := 0; i < n-1; i++ {
+		h := s.hop(as[i], as[i+1])
+		if h != hopProvider && minUp == n {
+			minUp = i + 1
+		}
+		if h == hopNotProvider {
+			maxUp = i + 1
+			break
+		}
+	}
+	if upstream {
+		switch {
+		case maxUp < n:
+			return ASPAInvalid
+		case minUp < n:
+			return ASPAUnknown
+		}
+		return ASPAValid
+	}
+
+	// Down-ramp: from the neighbor, while each AS is a customer of the previous
+	maxDown, minDown := n, n
+	for j := n - 1; j > 0; j-- {
+		h := s.hop(as[j], as[j-1])
+		if h != hopProvider && minDown == n {
+			minDown = n - j
+		}
+		if h == hopNotProvider {
+			maxDown = n - j
+			break
+		}
+	}
+	switch {
+	case maxUp+maxDown < n:
+		return ASPAInvalid
+	case minUp+minDown < n:
+		return ASPAUnknown
+	}
+	return ASPAValid
+}
+
+// VerifyRoutes verifies the AS path of each route, which needs its BGP attributes.
+// upstream reports whether a route is verified upstream; if nil, every route is
+// verified downstream, which only finds leaks no relationship could explain.
+// It returns the invalid networks keyed by origin ASN, like GetInvalids, leaving
+// out routes ending in an AS set, which have no origin ASN.
+func (s *ASPASet) VerifyRoutes(routes iter.Seq2[Route, error], upstream func(Route) bool) (map[string][]string, error) {
+	inv := make(map[string][]string)
+	for r, err := range routes {
+		if err != nil {
+			return inv, err
+		}
+		if r.BGP == nil || r.Prefix == nil || r.SourceASN == 0 {
+			continue
+		}
+		up := upstream != nil && upstream(r)
+		if s.Verify(r.BGP.ASPath, up) == ASPAInvalid {
+			asn := strconv.FormatUint(uint64(r.SourceASN), 10)
+			inv[asn] = append(inv[asn], r.Prefix.String())
+		}
+	}
+	return inv, nil
+}
+
+// GetASPAInvalids returns a map of origin ASNs whose routes fail ASPA verification
+// against b.ASPA, with the networks affected.
+// Routes are verified upstream if b.ASPAUpstream says so, and downstream otherwise.
+func (b *BirdClient) GetASPAInvalids() (map[string][]string, error) {
+	return b.GetASPAInvalidsContext(context.Background())
+}
+
+// GetASPAInvalidsContext is like GetASPAInvalids but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetASPAInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	tables := b.tables()
+	routes := func(yield func(Route, error) bool) {
+		for _, table := range []string{tables.IPv4, tables.IPv6} {
+			for r, err := range b.routes(ctx, fmt.Sprintf("show route primary all table %s where bgp_path.len > 0", table)) {
+				if !yield(r, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+	return b.ASPA.VerifyRoutes(routes, b.ASPAUpstream)
+}
+
+// GetASPAFromIP returns the ASPA state of the primary route used to reach an IP,
+// verified against b.ASPA.
+func (b *BirdClient) GetASPAFromIP(ip net.IP) (int, bool, error) {
+	return b.GetASPAFromIPContext(context.Background(), ip)
+}
+
+// GetASPAFromIPContext is like GetASPAFromIP but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetASPAFromIPContext(ctx context.Context, ip net.IP) (int, bool, error) {
+	r, found, err := b.GetRouteDetailContext(ctx, ip)
+	if err != nil || !found || r.BGP == nil {
+		return 0, false, err
+	}
+	up := b.ASPAUpstream != nil && b.ASPAUpstream(r)
+	return b.ASPA.Verify(r.BGP.ASPath, up), true, nil
+}
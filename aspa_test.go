@@ -0,0 +1,102 @@
+package clidecode
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestASPAVerify(t *testing.T) {
+	s := NewASPASet([]ASPA{
+		{Customer: 65001, Providers: []uint32{65010}},
+		{Customer: 65010, Providers: []uint32{65020}},
+		{Customer: 65020, Providers: []uint32{0}},
+		{Customer: 65030, Providers: []uint32{0}},
+	})
+
+	tests := []struct {
+		name     string
+		path     ASPath
+		upstream bool
+		want     int
+	}{
+		{"customer", ASPath{Path: []uint32{65010, 65001}}, true, ASPAValid},
+		{"customer's customer", ASPath{Path: []uint32{65020, 65010, 65001}}, true, ASPAValid},
+		{"leaked by a customer", ASPath{Path: []uint32{65030, 65020, 65010, 65001}}, true, ASPAInvalid},
+		{"not a provider", ASPath{Path: []uint32{65040, 65001}}, true, ASPAInvalid},
+		{"no attestation", ASPath{Path: []uint32{65010, 65040}}, true, ASPAUnknown},
+		{"over a peering", ASPath{Path: []uint32{65030, 65020, 65010, 65001}}, false, ASPAValid},
+		{"valley", ASPath{Path: []uint32{65020, 65030, 65001}}, false, ASPAInvalid},
+		{"prepended", ASPath{Path: []uint32{65020, 65010, 65001, 65001}}, false, ASPAValid},
+		{"two hops from a provider", ASPath{Path: []uint32{65040, 65001}}, false, ASPAValid},
+		{"unattested downstream", ASPath{Path: []uint32{65040, 65041, 65001}}, false, ASPAUnknown},
+		{"AS set", ASPath{Path: []uint32{65010}, Set: []uint32{65001, 65002}}, true, ASPAInvalid},
+		{"origin only", ASPath{Path: []uint32{65001}}, true, ASPAValid},
+		{"empty", ASPath{}, false, ASPAUnknown},
+	}
+	for _, test := range tests {
+		if got := s.Verify(test.path, test.upstream); got != test.want {
+			t.Errorf("%s: Verify(%v, %v) = %d, want %d", test.name, test.path.Path, test.upstream, got, test.want)
+		}
+	}
+}
+
+func TestLoadASPASet(t *testing.T) {
+	for _, path := range []string{"testdata/vrps/rpki-client.json", "testdata/vrps/routinator.json"} {
+		s, err := LoadASPASet(path)
+		if err != nil {
+			t.Errorf("LoadASPASet(%s) failed: %v", path, err)
+			continue
+		}
+		if s.Len() != 2 {
+			t.Errorf("Expected 2 ASPAs from %s, got %d", path, s.Len())
+		}
+		if p, ok := s.Providers(64500); !ok || !reflect.DeepEqual(p, []uint32{64510, 64511}) {
+			t.Errorf("Unexpected providers %v from %s", p, path)
+		}
+	}
+}
+
+func TestBirdClientASPA(t *testing.T) {
+	s, err := LoadASPASet("testdata/vrps/rpki-client.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &BirdClient{
+		Querier: mockQuerier(map[string]string{
+			"show route primary all table master4 where bgp_path.len > 0": `Table master4:
+192.0.2.0/24         unicast [bgp1 2025-11-19 from 198.51.100.1] * (100) [AS64500i]
+	BGP.as_path: 64511 64500
+198.51.100.0/24      unicast [bgp1 2025-11-19 from 198.51.100.1] * (100) [AS64500i]
+	BGP.as_path: 64496 64499 64500`,
+			"show route primary all table master6 where bgp_path.len > 0": `Table master6:
+2001:db8::/32        unicast [bgp2 2025-11-19 from 2001:db8::1] * (100) [AS64500i]
+	BGP.as_path: 64510 64500
+2001:db8:1::/48      unicast [bgp2 2025-11-19 from 2001:db8::1] * (100) [i]
+	BGP.as_path: 64510 {64500 64501}`,
+			"show route for 203.0.113.1 table master4 primary all": `Table master4:
+203.0.113.0/24       unicast [bgp3 2025-11-19 from 203.0.113.254] * (100) [AS64500i]
+	BGP.as_path: 64496 64500`,
+		}),
+		ASPA: s,
+	}
+
+	// The AS set makes 2001:db8:1::/48 invalid, but it has no origin to list it under
+	invalids, err := client.GetASPAInvalids()
+	if err != nil {
+		t.Fatalf("GetASPAInvalids failed: %v", err)
+	}
+	if !reflect.DeepEqual(invalids, map[string][]string{"64500": {"198.51.100.0/24"}}) {
+		t.Errorf("Unexpected invalids %v", invalids)
+	}
+
+	// Fine from a provider, but 64496 is not a provider of 64500
+	ip := net.ParseIP("203.0.113.1")
+	if state, found, err := client.GetASPAFromIP(ip); err != nil || !found || state != ASPAValid {
+		t.Errorf("GetASPAFromIP returned %d, %v, %v", state, found, err)
+	}
+	client.ASPAUpstream = func(r Route) bool { return r.Protocol == "bgp3" }
+	if state, found, err := client.GetASPAFromIP(ip); err != nil || !found || state != ASPAInvalid {
+		t.Errorf("GetASPAFromIP returned %d, %v, %v", state, found, err)
+	}
+}
@@ -1,35 +1,107 @@
 package clidecode
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// BirdClient represents a connection to a BIRD daemon via Unix socket
+// BirdClient represents a connection to a BIRD daemon via Unix socket.
+// If Session is set, commands are sent over that persistent connection,
+// otherwise every command dials the socket afresh.
+// Timeout overrides DefaultTimeout for calls whose context has no deadline.
+// PeerClassifier and PeerGrouper control how GetPeers and GetPeerGroups count sessions.
+// Tables names the routing and ROA tables to query, defaulting to master4, master6, roa_v4 and roa_v6.
+// If Validator is set, ROA queries validate routes against it instead of BIRD's ROA tables.
+// ASPA is the set of ASPAs used by GetASPAInvalids and GetASPAFromIP, and ASPAUpstream
+// picks the routes verified upstream, such as those learned from customers.
 type BirdClient struct {
-	SocketPath string
-	Querier    func(socketPath, command string) (string, error)
+	SocketPath     string
+	Session        *Session
+	Timeout        time.Duration
+	Querier        func(socketPath, command string) (string, error)
+	PeerClassifier PeerClassifier
+	PeerGrouper    PeerGrouper
+	Tables         Tables
+	Validator      *Validator
+	ASPA           *ASPASet
+	ASPAUpstream   func(Route) bool
 }
 
-// query sends a command to the BIRD socket and returns the output
-func (b *BirdClient) query(command string) (string, error) {
+var _ DecoderContext = (*BirdClient)(nil)
+
+// query sends a command to the BIRD socket and returns the output.
+// If ctx has no deadline, Timeout (or DefaultTimeout) bounds the command.
+func (b *BirdClient) query(ctx context.Context, command string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	if b.Querier != nil {
 		return b.Querier(b.SocketPath, command)
 	}
-	return querySocket(b.SocketPath, command)
+	if _, ok := ctx.Deadline(); !ok && b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+	if b.Session != nil {
+		return b.Session.QueryContext(ctx, command)
+	}
+	return querySocket(ctx, b.SocketPath, command)
+}
+
+// replies sends a command to the BIRD socket and hands each line of the reply
+// to emit as it arrives. With a Querier the output is split into lines after
+// the fact, with CodeUnknown as the codes have already been stripped.
+func (b *BirdClient) replies(ctx context.Context, command string, emit func(ReplyLine) error) error {
+	if b.Querier != nil {
+		out, err := b.query(ctx, command)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if err := emit(ReplyLine{Code: CodeUnknown, Text: line}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok && b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+	if b.Session != nil {
+		return b.Session.StreamContext(ctx, command, emit)
+	}
+	return streamSocket(ctx, b.SocketPath, command, emit)
 }
 
 // RunCommand executes an arbitrary command on the BIRD socket
 func (b *BirdClient) RunCommand(command string) (string, error) {
-	return b.query(command)
+	return b.RunCommandContext(context.Background(), command)
+}
+
+// RunCommandContext is like RunCommand but honours the cancellation and deadline of ctx
+func (b *BirdClient) RunCommandContext(ctx context.Context, command string) (string, error) {
+	return b.query(ctx, command)
 }
 
 // GetVersion returns the BIRD version string
 func (b *BirdClient) GetVersion() (string, error) {
-	out, err := b.query("show status")
+	return b.GetVersionContext(context.Background())
+}
+
+// GetVersionContext is like GetVersion but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetVersionContext(ctx context.Context) (string, error) {
+	out, err := b.query(ctx, "show status")
 	if err != nil {
 		return "", err
 	}
@@ -46,37 +118,21 @@ func (b *BirdClient) GetVersion() (string, error) {
 
 // GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
 func (b *BirdClient) GetBGPTotal() (Totals, error) {
+	return b.GetBGPTotalContext(context.Background())
+}
+
+// GetBGPTotalContext is like GetBGPTotal but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetBGPTotalContext(ctx context.Context) (Totals, error) {
 	var t Totals
 
-	out, err := b.query("show route count")
+	totals, err := b.GetTableTotalsContext(ctx)
 	if err != nil {
 		return t, err
 	}
 
-	// Parse output like:
-	// 1024 of 1024 routes for 1024 networks in table master4
-	// 2048 of 2048 routes for 2048 networks in table master6
-	lines := strings.Split(out, "\n")
-
-	// Regex to capture:
-	// 1. Total routes (RIB) - the number before "routes" (or "of X routes")
-	// 2. Networks (FIB) - the number before "networks"
-	// Example: "2076414 of 2076414 routes for 1038207 networks"
-	// We want 2076414 (RIB) and 1038207 (FIB)
-	re := regexp.MustCompile(`(\d+)\s+of\s+\d+\s+routes\s+for\s+(\d+)\s+networks`)
-
-	for _, line := range lines {
-		matches := re.FindStringSubmatch(line)
-		if len(matches) >= 3 {
-			if strings.Contains(line, "master4") {
-				t.V4Rib = stringToUint32(matches[1])
-				t.V4Fib = stringToUint32(matches[2])
-			} else if strings.Contains(line, "master6") {
-				t.V6Rib = stringToUint32(matches[1])
-				t.V6Fib = stringToUint32(matches[2])
-			}
-		}
-	}
+	// The IPv4 and IPv6 tables always come first
+	t.V4Rib, t.V4Fib = totals[0].Rib, totals[0].Fib
+	t.V6Rib, t.V6Fib = totals[1].Rib, totals[1].Fib
 
 	return t, nil
 }
@@ -111,165 +167,110 @@ func decodeASPaths(in string) ([]uint32, []uint32) {
 
 // GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
 func (b *BirdClient) GetPeers() (Peers, error) {
-	var p Peers
+	return b.GetPeersContext(context.Background())
+}
 
-	out, err := b.query("show protocols")
+// GetPeersContext is like GetPeers but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetPeersContext(ctx context.Context) (Peers, error) {
+	sessions, err := b.GetBGPSessionsContext(ctx)
 	if err != nil {
-		return p, err
-	}
-
-	lines := strings.Split(out, "\n")
-
-	v4Configured := uint32(0)
-	v4Established := uint32(0)
-	v6Configured := uint32(0)
-	v6Established := uint32(0)
-
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 6 {
-			continue
-		}
-
-		protocolName := fields[0]
-		status := fields[5]
-
-		// Skip non-BGP protocols
-		if !strings.Contains(protocolName, "_v4") && !strings.Contains(protocolName, "_v6") {
-			continue
-		}
-
-		// Skip system protocols
-		if strings.Contains(line, "device1") || strings.Contains(line, "kernel1") {
-			continue
-		}
-
-		if strings.Contains(protocolName, "_v4") {
-			v4Configured++
-			if status == "Established" {
-				v4Established++
-			}
-		} else if strings.Contains(protocolName, "_v6") {
-			v6Configured++
-			if status == "Established" {
-				v6Established++
-			}
-		}
+		return Peers{}, err
 	}
 
-	p.V4c = v4Configured
-	p.V4e = v4Established
-	p.V6c = v6Configured
-	p.V6e = v6Established
-
-	return p, nil
+	return countPeers(sessions, b.classifier()), nil
 }
 
 // GetTotalSourceASNs returns total amount of unique ASNs
 func (b *BirdClient) GetTotalSourceASNs() (ASNs, error) {
-	var s ASNs
+	return b.GetTotalSourceASNsContext(context.Background())
+}
 
+// GetTotalSourceASNsContext is like GetTotalSourceASNs but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetTotalSourceASNsContext(ctx context.Context) (ASNs, error) {
 	// Get IPv4 source ASNs
-	out4, err := b.query("show route primary table master4")
+	as4Set, err := b.sourceASNs(ctx, b.tables().IPv4)
 	if err != nil {
-		return s, err
+		return ASNs{}, err
 	}
 
 	// Get IPv6 source ASNs
-	out6, err := b.query("show route primary table master6")
+	as6Set, err := b.sourceASNs(ctx, b.tables().IPv6)
 	if err != nil {
-		return s, err
+		return ASNs{}, err
 	}
 
-	// Extract source ASNs from output
-	as4Set := extractSourceASNs(out4)
-	as6Set := extractSourceASNs(out6)
-
-	// Calculate total unique ASNs
-	var as10 []string
-	as10 = append(as10, as4Set...)
-	as10 = append(as10, as6Set...)
-	as10Set := stringSliceToSet(as10)
-
-	// Calculate ASNs only in one address family
-	as4Only := stringSliceDifference(as4Set, as6Set)
-	as6Only := stringSliceDifference(as6Set, as4Set)
-	asBoth := stringSliceIntersection(as4Set, as6Set)
+	return countASNs(as4Set, as6Set), nil
+}
 
+// countASNs calculates ASNs only in one address family, and those in both
+func countASNs(as4Set, as6Set map[uint32]struct{}) ASNs {
+	var s ASNs
+	for asn := range as4Set {
+		if _, ok := as6Set[asn]; ok {
+			s.AsBoth++
+		} else {
+			s.As4Only++
+		}
+	}
 	s.As4 = uint32(len(as4Set))
 	s.As6 = uint32(len(as6Set))
-	s.As10 = uint32(len(as10Set))
-	s.As4Only = uint32(len(as4Only))
-	s.As6Only = uint32(len(as6Only))
-	s.AsBoth = uint32(len(asBoth))
+	s.As6Only = s.As6 - s.AsBoth
+	s.As10 = s.As4 + s.As6Only
 
-	return s, nil
+	return s
 }
 
-// extractSourceASNs extracts unique source ASNs from BIRD route output
-func extractSourceASNs(output string) []string {
-	re := regexp.MustCompile(`\[AS(\d+)[ie]?\]`)
-	lines := strings.Split(output, "\n")
-
-	asnMap := make(map[string]bool)
-
-	for _, line := range lines {
-		matches := re.FindStringSubmatch(line)
-		if len(matches) >= 2 {
-			asnMap[matches[1]] = true
+// sourceASNs collects the unique source ASNs of all primary routes in a table
+func (b *BirdClient) sourceASNs(ctx context.Context, table string) (map[uint32]struct{}, error) {
+	asns := make(map[uint32]struct{})
+	for r, err := range b.Routes(ctx, table, "") {
+		if err != nil {
+			return nil, err
+		}
+		if r.SourceASN != 0 {
+			asns[r.SourceASN] = struct{}{}
 		}
 	}
-
-	// Convert map to slice
-	asns := make([]string, 0, len(asnMap))
-	for asn := range asnMap {
-		asns = append(asns, asn)
-	}
-
-	return asns
+	return asns, nil
 }
 
 // GetROAs returns total amount of all ROA states
 func (b *BirdClient) GetROAs() (Roas, error) {
-	var r Roas
-
-	// IPv4 ROA counts
-	v4Valid, err := b.query("show route primary table master4 where roa_check(roa_v4) = ROA_VALID count")
-	if err != nil {
-		return r, err
-	}
-	r.V4v = extractRouteCount(v4Valid)
-
-	v4Invalid, err := b.query("show route primary table master4 where roa_check(roa_v4) = ROA_INVALID count")
-	if err != nil {
-		return r, err
-	}
-	r.V4i = extractRouteCount(v4Invalid)
-
-	v4Unknown, err := b.query("show route primary table master4 where roa_check(roa_v4) = ROA_UNKNOWN count")
-	if err != nil {
-		return r, err
-	}
-	r.V4u = extractRouteCount(v4Unknown)
-
-	// IPv6 ROA counts
-	v6Valid, err := b.query("show route primary table master6 where roa_check(roa_v6) = ROA_VALID count")
-	if err != nil {
-		return r, err
-	}
-	r.V6v = extractRouteCount(v6Valid)
+	return b.GetROAsContext(context.Background())
+}
 
-	v6Invalid, err := b.query("show route primary table master6 where roa_check(roa_v6) = ROA_INVALID count")
-	if err != nil {
+// GetROAsContext is like GetROAs but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetROAsContext(ctx context.Context) (Roas, error) {
+	if b.Validator != nil {
+		r, _, err := b.validateRoutes(ctx)
 		return r, err
 	}
-	r.V6i = extractRouteCount(v6Invalid)
 
-	v6Unknown, err := b.query("show route primary table master6 where roa_check(roa_v6) = ROA_UNKNOWN count")
-	if err != nil {
-		return r, err
+	var r Roas
+	t := b.tables()
+
+	counts := []struct {
+		table, roa, state string
+		count             *uint32
+	}{
+		// IPv4 ROA counts
+		{t.IPv4, t.ROAv4, "ROA_VALID", &r.V4v},
+		{t.IPv4, t.ROAv4, "ROA_INVALID", &r.V4i},
+		{t.IPv4, t.ROAv4, "ROA_UNKNOWN", &r.V4u},
+		// IPv6 ROA counts
+		{t.IPv6, t.ROAv6, "ROA_VALID", &r.V6v},
+		{t.IPv6, t.ROAv6, "ROA_INVALID", &r.V6i},
+		{t.IPv6, t.ROAv6, "ROA_UNKNOWN", &r.V6u},
+	}
+
+	for _, c := range counts {
+		cmd := fmt.Sprintf("show route primary table %s where roa_check(%s) = %s count", c.table, c.roa, c.state)
+		out, err := b.query(ctx, cmd)
+		if err != nil {
+			return r, err
+		}
+		*c.count = extractRouteCount(out)
 	}
-	r.V6u = extractRouteCount(v6Unknown)
 
 	return r, nil
 }
@@ -286,145 +287,134 @@ func extractRouteCount(output string) uint32 {
 
 // GetInvalids returns a map of ASNs that are advertising RPKI invalid prefixes
 func (b *BirdClient) GetInvalids() (map[string][]string, error) {
-	inv := make(map[string][]string)
-	num := regexp.MustCompile(`[\d]+`)
-
-	// Get IPv4 invalids
-	out4, err := b.query("show route primary table master4 where roa_check(roa_v4) = ROA_INVALID")
-	if err != nil {
-		return inv, err
-	}
-
-	lines := strings.Split(out4, "\n")
-	for _, line := range lines {
-		if prefix, asn := parseInvalidLine(line, num); asn != "" {
-			inv[asn] = append(inv[asn], prefix)
-		}
-	}
+	return b.GetInvalidsContext(context.Background())
+}
 
-	// Get IPv6 invalids
-	out6, err := b.query("show route primary table master6 where roa_check(roa_v6) = ROA_INVALID")
-	if err != nil {
+// GetInvalidsContext is like GetInvalids but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	if b.Validator != nil {
+		_, inv, err := b.validateRoutes(ctx)
 		return inv, err
 	}
 
-	lines = strings.Split(out6, "\n")
-	for _, line := range lines {
-		if prefix, asn := parseInvalidLine(line, num); asn != "" {
-			inv[asn] = append(inv[asn], prefix)
+	inv := make(map[string][]string)
+	tables := b.tables()
+
+	for _, t := range []struct{ table, roa string }{
+		{tables.IPv4, tables.ROAv4},
+		{tables.IPv6, tables.ROAv6},
+	} {
+		filter := fmt.Sprintf("roa_check(%s) = ROA_INVALID", t.roa)
+		for r, err := range b.Routes(ctx, t.table, filter) {
+			if err != nil {
+				return inv, err
+			}
+			if r.SourceASN == 0 {
+				continue
+			}
+			asn := strconv.FormatUint(uint64(r.SourceASN), 10)
+			inv[asn] = append(inv[asn], r.Prefix.String())
 		}
 	}
 
 	return inv, nil
 }
 
-// parseInvalidLine extracts prefix and ASN from an invalid route line
-func parseInvalidLine(line string, numRe *regexp.Regexp) (prefix, asn string) {
-	// Example line: "192.0.2.0/24 via ... [AS64496i]"
-	fields := strings.Fields(line)
-	if len(fields) < 2 {
-		return "", ""
-	}
-
-	// First field is the prefix
-	prefix = fields[0]
-
-	// Find ASN in brackets
-	idx := strings.Index(line, "[")
-	if idx == -1 {
-		return "", ""
-	}
-	asnMatch := numRe.FindString(line[idx:])
-	if asnMatch != "" {
-		asn = asnMatch
+// validateRoutes validates the primary routes of both routing tables against b.Validator.
+func (b *BirdClient) validateRoutes(ctx context.Context) (Roas, map[string][]string, error) {
+	tables := b.tables()
+	routes := func(yield func(Route, error) bool) {
+		for _, table := range []string{tables.IPv4, tables.IPv6} {
+			for r, err := range b.Routes(ctx, table, "") {
+				if !yield(r, err) || err != nil {
+					return
+				}
+			}
+		}
 	}
-
-	return prefix, asn
+	return b.Validator.ValidateRoutes(routes)
 }
 
 // GetMasks returns the total count of each mask value
 func (b *BirdClient) GetMasks() ([]map[string]uint32, error) {
-	v4 := make(map[string]uint32)
-	v6 := make(map[string]uint32)
+	return b.GetMasksContext(context.Background())
+}
 
-	// Get IPv4 routes
-	out4, err := b.query("show route primary table master4")
+// GetMasksContext is like GetMasks but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	v4, err := b.masks(ctx, b.tables().IPv4)
 	if err != nil {
 		return nil, err
 	}
 
-	lines := strings.Split(out4, "\n")
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) > 0 && strings.Contains(fields[0], "/") {
-			parts := strings.Split(fields[0], "/")
-			if len(parts) == 2 {
-				v4[parts[1]]++
-			}
-		}
-	}
-
-	// Get IPv6 routes
-	out6, err := b.query("show route primary table master6")
+	v6, err := b.masks(ctx, b.tables().IPv6)
 	if err != nil {
 		return nil, err
 	}
 
-	lines = strings.Split(out6, "\n")
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) > 0 && strings.Contains(fields[0], "/") {
-			parts := strings.Split(fields[0], "/")
-			if len(parts) == 2 {
-				v6[parts[1]]++
-			}
+	return []map[string]uint32{v4, v6}, nil
+}
+
+// masks counts the primary routes in a table by prefix length
+func (b *BirdClient) masks(ctx context.Context, table string) (map[string]uint32, error) {
+	m := make(map[string]uint32)
+	for r, err := range b.Routes(ctx, table, "") {
+		if err != nil {
+			return nil, err
 		}
+		ones, _ := r.Prefix.Mask.Size()
+		m[strconv.Itoa(ones)]++
 	}
-
-	return []map[string]uint32{v4, v6}, nil
+	return m, nil
 }
 
 // GetLargeCommunities returns the amount of prefixes that have large communities attached
 func (b *BirdClient) GetLargeCommunities() (Large, error) {
+	return b.GetLargeCommunitiesContext(context.Background())
+}
+
+// GetLargeCommunitiesContext is like GetLargeCommunities but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetLargeCommunitiesContext(ctx context.Context) (Large, error) {
 	var l Large
 
 	// IPv4 large communities
-	out4, err := b.query("show route primary table master4 where bgp_large_community ~ [(*,*,*)]")
+	v4, err := b.countRoutes(ctx, b.tables().IPv4, "bgp_large_community ~ [(*,*,*)]")
 	if err != nil {
 		return l, err
 	}
-	l.V4 = uint32(countRouteLines(out4))
+	l.V4 = v4
 
 	// IPv6 large communities
-	out6, err := b.query("show route primary table master6 where bgp_large_community ~ [(*,*,*)]")
+	v6, err := b.countRoutes(ctx, b.tables().IPv6, "bgp_large_community ~ [(*,*,*)]")
 	if err != nil {
 		return l, err
 	}
-	l.V6 = uint32(countRouteLines(out6))
+	l.V6 = v6
 
 	return l, nil
 }
 
-// countRouteLines counts the number of route lines in output
-func countRouteLines(output string) int {
-	if output == "" {
-		return 0
-	}
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	count := 0
-	for _, line := range lines {
-		// Count lines that start with an IP address
-		if len(line) > 0 && (line[0] >= '0' && line[0] <= '9' || line[0] == ':') {
-			count++
+// countRoutes counts the primary routes in a table matching a filter
+func (b *BirdClient) countRoutes(ctx context.Context, table, filter string) (uint32, error) {
+	var count uint32
+	for _, err := range b.Routes(ctx, table, filter) {
+		if err != nil {
+			return 0, err
 		}
+		count++
 	}
-	return count
+	return count, nil
 }
 
 // GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN
 func (b *BirdClient) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
-	cmd := fmt.Sprintf("show route primary table master4 where bgp_path ~ [= * %d =]", asn)
-	out, err := b.query(cmd)
+	return b.GetIPv4FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv4FromSourceContext is like GetIPv4FromSource but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	cmd := fmt.Sprintf("show route primary table %s where bgp_path ~ [= * %d =]", b.tables().IPv4, asn)
+	out, err := b.query(ctx, cmd)
 	if err != nil {
 		return []*net.IPNet{}, err
 	}
@@ -434,8 +424,13 @@ func (b *BirdClient) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
 
 // GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN
 func (b *BirdClient) GetIPv6FromSource(asn uint32) ([]*net.IPNet, error) {
-	cmd := fmt.Sprintf("show route primary table master6 where bgp_path ~ [= * %d =]", asn)
-	out, err := b.query(cmd)
+	return b.GetIPv6FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv6FromSourceContext is like GetIPv6FromSource but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	cmd := fmt.Sprintf("show route primary table %s where bgp_path ~ [= * %d =]", b.tables().IPv6, asn)
+	out, err := b.query(ctx, cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -463,10 +458,18 @@ func extractPrefixes(output string) []*net.IPNet {
 
 // GetASPathFromIP will return the AS path, as well as as-set if any from a source IP
 func (b *BirdClient) GetASPathFromIP(ip net.IP) (ASPath, bool, error) {
+	return b.GetASPathFromIPContext(context.Background(), ip)
+}
+
+// GetASPathFromIPContext is like GetASPathFromIP but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetASPathFromIPContext(ctx context.Context, ip net.IP) (ASPath, bool, error) {
 	var aspath ASPath
 
-	cmd := fmt.Sprintf("show route primary all for %s", ip.String())
-	out, err := b.query(cmd)
+	cmd := fmt.Sprintf("show route primary all for %s table %s", ip.String(), b.tables().ribFor(ip))
+	out, err := b.query(ctx, cmd)
+	if isNotFound(err) {
+		return aspath, false, nil
+	}
 	if err != nil {
 		return aspath, false, err
 	}
@@ -496,8 +499,16 @@ func (b *BirdClient) GetASPathFromIP(ip net.IP) (ASPath, bool, error) {
 
 // GetRoute will return the current FIB entry, if any, from a source IP
 func (b *BirdClient) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
-	cmd := fmt.Sprintf("show route primary for %s", ip.String())
-	out, err := b.query(cmd)
+	return b.GetRouteContext(context.Background(), ip)
+}
+
+// GetRouteContext is like GetRoute but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	cmd := fmt.Sprintf("show route primary for %s table %s", ip.String(), b.tables().ribFor(ip))
+	out, err := b.query(ctx, cmd)
+	if isNotFound(err) {
+		return nil, false, nil
+	}
 	if err != nil {
 		return nil, false, err
 	}
@@ -523,8 +534,16 @@ func (b *BirdClient) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
 
 // GetOriginFromIP will return the origin ASN from a source IP
 func (b *BirdClient) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
-	cmd := fmt.Sprintf("show route primary all for %s", ip.String())
-	out, err := b.query(cmd)
+	return b.GetOriginFromIPContext(context.Background(), ip)
+}
+
+// GetOriginFromIPContext is like GetOriginFromIP but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	cmd := fmt.Sprintf("show route primary all for %s table %s", ip.String(), b.tables().ribFor(ip))
+	out, err := b.query(ctx, cmd)
+	if isNotFound(err) {
+		return 0, false, nil
+	}
 	if err != nil {
 		return 0, false, err
 	}
@@ -565,44 +584,56 @@ func (b *BirdClient) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
 
 // GetROA will return the ROA status from a prefix and ASN
 func (b *BirdClient) GetROA(prefix *net.IPNet, asn uint32) (int, bool, error) {
-	var table string
-	if strings.Contains(prefix.String(), ":") {
-		table = "roa_v6"
-	} else {
-		table = "roa_v4"
+	return b.GetROAContext(context.Background(), prefix, asn)
+}
+
+// GetROAContext is like GetROA but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	p, ok := PrefixFromIPNet(prefix)
+	if !ok {
+		return 0, false, nil
+	}
+	if b.Validator != nil {
+		if err := ctx.Err(); err != nil {
+			return 0, false, err
+		}
+		return b.Validator.Validate(p, asn), true, nil
 	}
 
+	table := b.tables().roaFor(prefix.IP)
+
 	cmd := fmt.Sprintf("eval roa_check(%s, %s, %d)", table, prefix, asn)
-	out, err := b.query(cmd)
+	out, err := b.query(ctx, cmd)
 	if err != nil {
 		return 0, false, err
 	}
 
-	// Parse the enum value from output like "(enum 35)1"
-	if len(out) > 0 {
-		val := out[len(out)-1:]
-
-		statuses := map[string]int{
-			"0": RUnknown,
-			"1": RValid,
-			"2": RInvalid,
-		}
-
-		if status, ok := statuses[val]; ok {
-			return status, true, nil
-		}
+	status, err := parseROACheck(out)
+	if err != nil {
+		return 0, false, err
 	}
-
-	return 0, false, nil
+	return status, true, nil
 }
 
 // GetVRPs will return all Validated ROA Payloads for an ASN
 func (b *BirdClient) GetVRPs(asn uint32) ([]VRP, error) {
+	return b.GetVRPsContext(context.Background(), asn)
+}
+
+// GetVRPsContext is like GetVRPs but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetVRPsContext(ctx context.Context, asn uint32) ([]VRP, error) {
+	if b.Validator != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return vrpsFor(b.Validator, asn), nil
+	}
+
 	var VRPs []VRP
 
 	// Get IPv4 VRPs
-	cmd4 := fmt.Sprintf("show route all table roa_v4 where net.asn=%d", asn)
-	out4, err := b.query(cmd4)
+	cmd4 := fmt.Sprintf("show route all table %s where net.asn=%d", b.tables().ROAv4, asn)
+	out4, err := b.query(ctx, cmd4)
 	if err != nil {
 		return VRPs, err
 	}
@@ -616,8 +647,8 @@ func (b *BirdClient) GetVRPs(asn uint32) ([]VRP, error) {
 	}
 
 	// Get IPv6 VRPs
-	cmd6 := fmt.Sprintf("show route all table roa_v6 where net.asn=%d", asn)
-	out6, err := b.query(cmd6)
+	cmd6 := fmt.Sprintf("show route all table %s where net.asn=%d", b.tables().ROAv6, asn)
+	out6, err := b.query(ctx, cmd6)
 	if err != nil {
 		return VRPs, err
 	}
@@ -662,54 +693,6 @@ func parseVRPs(output string) ([]VRP, error) {
 	return vrps, nil
 }
 
-// Helper functions for string slice operations
-
-// stringSliceToSet returns a deduplicated slice of strings
-func stringSliceToSet(slice []string) []string {
-	set := make(map[string]bool)
-	for _, s := range slice {
-		set[s] = true
-	}
-
-	result := make([]string, 0, len(set))
-	for s := range set {
-		result = append(result, s)
-	}
-	return result
-}
-
-// stringSliceDifference returns elements in first but not in second
-func stringSliceDifference(first, second []string) []string {
-	secondSet := make(map[string]bool)
-	for _, s := range second {
-		secondSet[s] = true
-	}
-
-	var result []string
-	for _, s := range first {
-		if !secondSet[s] {
-			result = append(result, s)
-		}
-	}
-	return result
-}
-
-// stringSliceIntersection returns elements in both slices
-func stringSliceIntersection(first, second []string) []string {
-	secondSet := make(map[string]bool)
-	for _, s := range second {
-		secondSet[s] = true
-	}
-
-	var result []string
-	for _, s := range first {
-		if secondSet[s] {
-			result = append(result, s)
-		}
-	}
-	return result
-}
-
 // stringToUint32 converts a string to uint32
 func stringToUint32(s string) uint32 {
 	s = strings.TrimSpace(s)
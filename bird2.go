@@ -9,6 +9,8 @@ type Bird2Conn struct {
 	BirdClient
 }
 
+var _ DecoderContext = (*Bird2Conn)(nil)
+
 // NewBird2Conn creates a new Bird2Conn with the default socket path
 func NewBird2Conn() *Bird2Conn {
 	return &Bird2Conn{
@@ -27,6 +29,16 @@ func NewBird2ConnWithSocket(socketPath string) *Bird2Conn {
 	}
 }
 
+// NewBird2ConnWithSession creates a new Bird2Conn that sends all commands over a shared Session
+func NewBird2ConnWithSession(s *Session) *Bird2Conn {
+	return &Bird2Conn{
+		BirdClient: BirdClient{
+			SocketPath: s.SocketPath,
+			Session:    s,
+		},
+	}
+}
+
 // GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
 func (b Bird2Conn) GetBGPTotal() (Totals, error) {
 	return b.BirdClient.GetBGPTotal()
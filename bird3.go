@@ -9,6 +9,8 @@ type Bird3Conn struct {
 	BirdClient
 }
 
+var _ DecoderContext = (*Bird3Conn)(nil)
+
 // NewBird3Conn creates a new Bird3Conn with the default socket path
 func NewBird3Conn() *Bird3Conn {
 	return &Bird3Conn{
@@ -27,6 +29,16 @@ func NewBird3ConnWithSocket(socketPath string) *Bird3Conn {
 	}
 }
 
+// NewBird3ConnWithSession creates a new Bird3Conn that sends all commands over a shared Session
+func NewBird3ConnWithSession(s *Session) *Bird3Conn {
+	return &Bird3Conn{
+		BirdClient: BirdClient{
+			SocketPath: s.SocketPath,
+			Session:    s,
+		},
+	}
+}
+
 // GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
 func (b Bird3Conn) GetBGPTotal() (Totals, error) {
 	return b.BirdClient.GetBGPTotal()
@@ -19,7 +19,7 @@ func mockQuerier(responses map[string]string) func(string, string) (string, erro
 
 func TestGetBGPTotal(t *testing.T) {
 	responses := map[string]string{
-		"show route count": `1007-2076414 of 2076414 routes for 1038207 networks in table master4
+		"show route table master4 table master6 count": `1007-2076414 of 2076414 routes for 1038207 networks in table master4
  471160 of 471160 routes for 235580 networks in table master6
  Total: 2547574 of 2547574 routes for 1273787 networks in 2 tables`,
 	}
@@ -47,7 +47,7 @@ func TestGetBGPTotal(t *testing.T) {
 
 func TestGetPeers(t *testing.T) {
 	responses := map[string]string{
-		"show protocols": `BIRD 2.0.8 ready.
+		"show protocols all": `BIRD 2.0.8 ready.
 name     proto    table    state  since       info
 bgp1_v4  BGP      master4  up     10:00:00    Established   
   BGP state:          Established
@@ -84,7 +84,7 @@ bgp3_v6  BGP      master6  up     10:00:00    Established
 
 func TestGetRoute(t *testing.T) {
 	responses := map[string]string{
-		"show route primary for 8.8.8.8": `Table master4:
+		"show route primary for 8.8.8.8 table master4": `Table master4:
 8.8.8.0/24           unreachable [BGP3v4 2025-11-19 from 192.110.255.57] * (100) [AS15169i]
 	Type: BGP univ
 	BGP.origin: IGP`,
@@ -110,7 +110,7 @@ func TestGetRoute(t *testing.T) {
 
 func TestGetRoute_NotFound(t *testing.T) {
 	responses := map[string]string{
-		"show route primary for 1.2.3.4": `Network not in table`,
+		"show route primary for 1.2.3.4 table master4": `Network not in table`,
 	}
 
 	client := &BirdClient{
@@ -0,0 +1,138 @@
+package clidecode
+
+import (
+	"context"
+	"regexp"
+)
+
+// PeerClassifier decides which address families a BGP session counts towards in GetPeers.
+// Only BGP protocols are ever classified, so device, kernel and other protocols never count.
+// Any func with this signature can be used for a custom scheme.
+type PeerClassifier func(BGPSession) (v4, v6 bool)
+
+// PeerGrouper assigns a BGP session to a named group such as transit, ix or customer.
+// Sessions for which it returns "" are left out of every group.
+type PeerGrouper func(BGPSession) string
+
+// ClassifyByChannel counts a session towards the families of its channels,
+// so a multiprotocol session counts as both an IPv4 and an IPv6 peer.
+func ClassifyByChannel(s BGPSession) (v4, v6 bool) {
+	for _, c := range s.Channels {
+		switch c.Family() {
+		case 4:
+			v4 = true
+		case 6:
+			v6 = true
+		}
+	}
+	return v4, v6
+}
+
+// ClassifyByNeighbor counts a session towards the family of its neighbor address.
+func ClassifyByNeighbor(s BGPSession) (v4, v6 bool) {
+	if s.NeighborAddress == nil {
+		return false, false
+	}
+	if s.NeighborAddress.To4() != nil {
+		return true, false
+	}
+	return false, true
+}
+
+// ClassifyByName counts a session towards IPv4 and/or IPv6 if its protocol name
+// matches the respective pattern. A nil pattern never matches.
+// ClassifyByName(regexp.MustCompile(`_v4`), regexp.MustCompile(`_v6`)) reproduces
+// the naming convention GetPeers originally relied on.
+func ClassifyByName(v4, v6 *regexp.Regexp) PeerClassifier {
+	return func(s BGPSession) (bool, bool) {
+		return v4 != nil && v4.MatchString(s.Name), v6 != nil && v6.MatchString(s.Name)
+	}
+}
+
+// PeerGroup names a group of sessions whose protocol names match Pattern.
+type PeerGroup struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// GroupByName returns a PeerGrouper assigning each session to the first group whose
+// pattern matches its protocol name, e.g. ^transit_ or ^ix_.
+func GroupByName(groups ...PeerGroup) PeerGrouper {
+	return func(s BGPSession) string {
+		for _, g := range groups {
+			if g.Pattern.MatchString(s.Name) {
+				return g.Name
+			}
+		}
+		return ""
+	}
+}
+
+// GroupByNeighborAS returns a PeerGrouper assigning sessions to groups by neighbor AS.
+func GroupByNeighborAS(groups map[uint32]string) PeerGrouper {
+	return func(s BGPSession) string {
+		return groups[s.NeighborAS]
+	}
+}
+
+// GetPeerGroups returns the peer counts for each group set by PeerGrouper,
+// along with the overall totals as returned by GetPeers.
+func (b *BirdClient) GetPeerGroups() (Peers, map[string]Peers, error) {
+	return b.GetPeerGroupsContext(context.Background())
+}
+
+// GetPeerGroupsContext is like GetPeerGroups but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetPeerGroupsContext(ctx context.Context) (Peers, map[string]Peers, error) {
+	sessions, err := b.GetBGPSessionsContext(ctx)
+	if err != nil {
+		return Peers{}, nil, err
+	}
+
+	groups := make(map[string]Peers)
+	if b.PeerGrouper != nil {
+		for _, s := range sessions {
+			if name := b.PeerGrouper(s); name != "" {
+				p := groups[name]
+				p.add(s, b.classifier())
+				groups[name] = p
+			}
+		}
+	}
+
+	return countPeers(sessions, b.classifier()), groups, nil
+}
+
+// classifier returns the configured PeerClassifier, defaulting to channels
+// with a fallback to the neighbor address.
+func (b *BirdClient) classifier() PeerClassifier {
+	if b.PeerClassifier != nil {
+		return b.PeerClassifier
+	}
+	return BGPSession.Families
+}
+
+// countPeers totals configured and established sessions per address family.
+func countPeers(sessions []BGPSession, classify PeerClassifier) Peers {
+	var p Peers
+	for _, s := range sessions {
+		p.add(s, classify)
+	}
+	return p
+}
+
+// add counts a single session.
+func (p *Peers) add(s BGPSession, classify PeerClassifier) {
+	v4, v6 := classify(s)
+	if v4 {
+		p.V4c++
+		if s.Established() {
+			p.V4e++
+		}
+	}
+	if v6 {
+		p.V6c++
+		if s.Established() {
+			p.V6e++
+		}
+	}
+}
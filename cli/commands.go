@@ -0,0 +1,563 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mellowdrifter/clidecode"
+)
+
+// errNotFound is returned by commands whose lookup found nothing
+var errNotFound = errors.New("not found")
+
+// usageError is returned for bad command arguments
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+// result is the answer of a command.
+// text is for people and may leave out detail, rows always hold everything.
+type result interface {
+	text(w io.Writer)
+	rows() (header []string, rows [][]string)
+}
+
+func u32(n uint32) string { return strconv.FormatUint(uint64(n), 10) }
+
+// command is a birdtest subcommand
+type command struct {
+	name  string
+	args  string // argument synopsis, e.g. "PREFIX ASN"
+	nargs int    // number of arguments, or -1 for one or more
+	help  string
+	run   func(ctx context.Context, c *clidecode.BirdClient, opts options, args []string) (result, error)
+}
+
+var commands = []command{
+	{"version", "", 0, "BIRD daemon version", runVersion},
+	{"totals", "", 0, "RIB and FIB route counts", runTotals},
+	{"peers", "", 0, "BGP peers configured and established", runPeers},
+	{"asns", "", 0, "unique source ASN counts", runASNs},
+	{"masks", "", 0, "prefix length distribution", runMasks},
+	{"roas", "", 0, "ROA state counts", runROAs},
+	{"large", "", 0, "routes carrying large communities", runLarge},
+	{"prefixes", "ASN", 1, "prefixes originated by an ASN (-4 or -6 for one family)", runPrefixes},
+	{"origin", "IP", 1, "origin ASN of the route to an IP", runOrigin},
+	{"aspath", "IP", 1, "AS path of the route to an IP", runASPath},
+	{"route", "IP", 1, "FIB entry for an IP", runRoute},
+	{"roa", "PREFIX ASN", 2, "ROA status of a prefix announced by an ASN, with the VRPs involved", runROA},
+	{"vrps", "ASN", 1, "VRPs for an ASN", runVRPs},
+	{"invalids", "", 0, "RPKI invalid prefixes by origin ASN", runInvalids},
+	{"run", "COMMAND...", -1, "send a raw command to BIRD", runRaw},
+}
+
+// lookup returns the named command
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func parseASN(s string) (uint32, error) {
+	asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(s), "AS"), 10, 32)
+	if err != nil {
+		return 0, usageError{fmt.Sprintf("invalid ASN %q", s)}
+	}
+	return uint32(asn), nil
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, usageError{fmt.Sprintf("invalid IP address %q", s)}
+	}
+	return ip, nil
+}
+
+type versionResult string
+
+func (v versionResult) text(w io.Writer) { fmt.Fprintln(w, v) }
+
+func (v versionResult) rows() ([]string, [][]string) {
+	return []string{"version"}, [][]string{{string(v)}}
+}
+
+func runVersion(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	v, err := c.GetVersionContext(ctx)
+	return versionResult(v), err
+}
+
+type totalsResult clidecode.Totals
+
+func (t totalsResult) text(w io.Writer) {
+	fmt.Fprintf(w, "IPv4 RIB: %d, FIB: %d\n", t.V4Rib, t.V4Fib)
+	fmt.Fprintf(w, "IPv6 RIB: %d, FIB: %d\n", t.V6Rib, t.V6Fib)
+}
+
+func (t totalsResult) rows() ([]string, [][]string) {
+	return []string{"family", "rib", "fib"}, [][]string{
+		{"ipv4", u32(t.V4Rib), u32(t.V4Fib)},
+		{"ipv6", u32(t.V6Rib), u32(t.V6Fib)},
+	}
+}
+
+func runTotals(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	t, err := c.GetBGPTotalContext(ctx)
+	return totalsResult(t), err
+}
+
+type peersResult clidecode.Peers
+
+func (p peersResult) text(w io.Writer) {
+	fmt.Fprintf(w, "IPv4 Peers: %d configured, %d established\n", p.V4c, p.V4e)
+	fmt.Fprintf(w, "IPv6 Peers: %d configured, %d established\n", p.V6c, p.V6e)
+}
+
+func (p peersResult) rows() ([]string, [][]string) {
+	return []string{"family", "configured", "established"}, [][]string{
+		{"ipv4", u32(p.V4c), u32(p.V4e)},
+		{"ipv6", u32(p.V6c), u32(p.V6e)},
+	}
+}
+
+func runPeers(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	p, err := c.GetPeersContext(ctx)
+	return peersResult(p), err
+}
+
+type asnsResult clidecode.ASNs
+
+func (a asnsResult) text(w io.Writer) {
+	fmt.Fprintf(w, "AS4: %d, AS6: %d, AS10: %d\n", a.As4, a.As6, a.As10)
+	fmt.Fprintf(w, "AS4Only: %d, AS6Only: %d, ASBoth: %d\n", a.As4Only, a.As6Only, a.AsBoth)
+}
+
+func (a asnsResult) rows() ([]string, [][]string) {
+	return []string{"as4", "as6", "as10", "as4_only", "as6_only", "as_both"}, [][]string{
+		{u32(a.As4), u32(a.As6), u32(a.As10), u32(a.As4Only), u32(a.As6Only), u32(a.AsBoth)},
+	}
+}
+
+func runASNs(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	a, err := c.GetTotalSourceASNsContext(ctx)
+	return asnsResult(a), err
+}
+
+type masksResult struct {
+	IPv4 map[string]uint32 `json:"ipv4" yaml:"ipv4"`
+	IPv6 map[string]uint32 `json:"ipv6" yaml:"ipv6"`
+}
+
+func (m masksResult) text(w io.Writer) {
+	printSorted := func(title string, masks map[string]uint32) {
+		fmt.Fprintln(w, title)
+		keys := make([]string, 0, len(masks))
+		for k := range masks {
+			keys = append(keys, k)
+		}
+		// Most common first
+		sort.Slice(keys, func(i, j int) bool {
+			return masks[keys[i]] > masks[keys[j]]
+		})
+		for _, k := range keys {
+			fmt.Fprintf(w, "  /%s: %d\n", k, masks[k])
+		}
+	}
+	printSorted("IPv4 Masks:", m.IPv4)
+	printSorted("IPv6 Masks:", m.IPv6)
+}
+
+func (m masksResult) rows() ([]string, [][]string) {
+	var rows [][]string
+	add := func(family string, masks map[string]uint32) {
+		keys := make([]string, 0, len(masks))
+		for k := range masks {
+			keys = append(keys, k)
+		}
+		// By prefix length
+		sort.Slice(keys, func(i, j int) bool {
+			a, _ := strconv.Atoi(keys[i])
+			b, _ := strconv.Atoi(keys[j])
+			return a < b
+		})
+		for _, k := range keys {
+			rows = append(rows, []string{family, k, u32(masks[k])})
+		}
+	}
+	add("ipv4", m.IPv4)
+	add("ipv6", m.IPv6)
+	return []string{"family", "mask", "prefixes"}, rows
+}
+
+func runMasks(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	m, err := c.GetMasksContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return masksResult{IPv4: m[0], IPv6: m[1]}, nil
+}
+
+type roasResult clidecode.Roas
+
+func (r roasResult) text(w io.Writer) {
+	fmt.Fprintf(w, "IPv4: Valid: %d, Invalid: %d, Unknown: %d\n", r.V4v, r.V4i, r.V4u)
+	fmt.Fprintf(w, "IPv6: Valid: %d, Invalid: %d, Unknown: %d\n", r.V6v, r.V6i, r.V6u)
+}
+
+func (r roasResult) rows() ([]string, [][]string) {
+	return []string{"family", "valid", "invalid", "unknown"}, [][]string{
+		{"ipv4", u32(r.V4v), u32(r.V4i), u32(r.V4u)},
+		{"ipv6", u32(r.V6v), u32(r.V6i), u32(r.V6u)},
+	}
+}
+
+func runROAs(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	r, err := c.GetROAsContext(ctx)
+	return roasResult(r), err
+}
+
+type largeResult clidecode.Large
+
+func (l largeResult) text(w io.Writer) {
+	fmt.Fprintf(w, "IPv4 with Large Communities: %d\n", l.V4)
+	fmt.Fprintf(w, "IPv6 with Large Communities: %d\n", l.V6)
+}
+
+func (l largeResult) rows() ([]string, [][]string) {
+	return []string{"family", "prefixes"}, [][]string{
+		{"ipv4", u32(l.V4)},
+		{"ipv6", u32(l.V6)},
+	}
+}
+
+func runLarge(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	l, err := c.GetLargeCommunitiesContext(ctx)
+	return largeResult(l), err
+}
+
+type prefixesResult struct {
+	ASN  uint32   `json:"asn" yaml:"asn"`
+	IPv4 []string `json:"ipv4,omitempty" yaml:"ipv4,omitempty"`
+	IPv6 []string `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+}
+
+func (p prefixesResult) text(w io.Writer) {
+	list := func(family string, prefixes []string) {
+		fmt.Fprintf(w, "Found %d %s prefixes for AS%d:\n", len(prefixes), family, p.ASN)
+		for i, prefix := range prefixes {
+			if i >= 10 {
+				fmt.Fprintf(w, "... and %d more\n", len(prefixes)-10)
+				break
+			}
+			fmt.Fprintln(w, "  "+prefix)
+		}
+	}
+	if p.IPv4 != nil {
+		list("IPv4", p.IPv4)
+	}
+	if p.IPv6 != nil {
+		list("IPv6", p.IPv6)
+	}
+}
+
+func (p prefixesResult) rows() ([]string, [][]string) {
+	var rows [][]string
+	for _, prefix := range p.IPv4 {
+		rows = append(rows, []string{u32(p.ASN), "ipv4", prefix})
+	}
+	for _, prefix := range p.IPv6 {
+		rows = append(rows, []string{u32(p.ASN), "ipv6", prefix})
+	}
+	return []string{"asn", "family", "prefix"}, rows
+}
+
+func runPrefixes(ctx context.Context, c *clidecode.BirdClient, opts options, args []string) (result, error) {
+	asn, err := parseASN(args[0])
+	if err != nil {
+		return nil, err
+	}
+	strs := func(nets []*net.IPNet) []string {
+		s := make([]string, 0, len(nets))
+		for _, n := range nets {
+			s = append(s, n.String())
+		}
+		return s
+	}
+
+	// Neither -4 nor -6 means both
+	r := prefixesResult{ASN: asn}
+	if opts.v4 || !opts.v6 {
+		nets, err := c.GetIPv4FromSourceContext(ctx, asn)
+		if err != nil {
+			return nil, err
+		}
+		r.IPv4 = strs(nets)
+	}
+	if opts.v6 || !opts.v4 {
+		nets, err := c.GetIPv6FromSourceContext(ctx, asn)
+		if err != nil {
+			return nil, err
+		}
+		r.IPv6 = strs(nets)
+	}
+	return r, nil
+}
+
+type originResult struct {
+	IP  string `json:"ip" yaml:"ip"`
+	ASN uint32 `json:"asn" yaml:"asn"`
+}
+
+func (o originResult) text(w io.Writer) { fmt.Fprintf(w, "Origin ASN: %d\n", o.ASN) }
+
+func (o originResult) rows() ([]string, [][]string) {
+	return []string{"ip", "asn"}, [][]string{{o.IP, u32(o.ASN)}}
+}
+
+func runOrigin(ctx context.Context, c *clidecode.BirdClient, _ options, args []string) (result, error) {
+	ip, err := parseIP(args[0])
+	if err != nil {
+		return nil, err
+	}
+	asn, found, err := c.GetOriginFromIPContext(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("origin of %s %w", ip, errNotFound)
+	}
+	return originResult{IP: ip.String(), ASN: asn}, nil
+}
+
+type asPathResult clidecode.ASPath
+
+func (p asPathResult) text(w io.Writer) {
+	fmt.Fprintf(w, "AS Path: %v\n", p.Path)
+	if len(p.Set) > 0 {
+		fmt.Fprintf(w, "AS Set: %v\n", p.Set)
+	}
+}
+
+func (p asPathResult) rows() ([]string, [][]string) {
+	join := func(asns []uint32) string {
+		s := make([]string, 0, len(asns))
+		for _, asn := range asns {
+			s = append(s, u32(asn))
+		}
+		return strings.Join(s, " ")
+	}
+	return []string{"path", "set"}, [][]string{{join(p.Path), join(p.Set)}}
+}
+
+func runASPath(ctx context.Context, c *clidecode.BirdClient, _ options, args []string) (result, error) {
+	ip, err := parseIP(args[0])
+	if err != nil {
+		return nil, err
+	}
+	path, found, err := c.GetASPathFromIPContext(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("AS path to %s %w", ip, errNotFound)
+	}
+	return asPathResult(path), nil
+}
+
+type routeResult struct {
+	IP    string `json:"ip" yaml:"ip"`
+	Route string `json:"route" yaml:"route"`
+}
+
+func (r routeResult) text(w io.Writer) { fmt.Fprintf(w, "Route: %s\n", r.Route) }
+
+func (r routeResult) rows() ([]string, [][]string) {
+	return []string{"ip", "route"}, [][]string{{r.IP, r.Route}}
+}
+
+func runRoute(ctx context.Context, c *clidecode.BirdClient, _ options, args []string) (result, error) {
+	ip, err := parseIP(args[0])
+	if err != nil {
+		return nil, err
+	}
+	route, found, err := c.GetRouteContext(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("route to %s %w", ip, errNotFound)
+	}
+	return routeResult{IP: ip.String(), Route: route.String()}, nil
+}
+
+type roaResult clidecode.ROAResult
+
+func (r roaResult) text(w io.Writer) {
+	fmt.Fprintln(w, clidecode.ROAResult(r).String())
+}
+
+var roaStates = map[int]string{
+	clidecode.RUnknown: "unknown",
+	clidecode.RValid:   "valid",
+	clidecode.RInvalid: "invalid",
+}
+
+// rows lists each covering VRP with why it did or did not match
+func (r roaResult) rows() ([]string, [][]string) {
+	header := []string{"prefix", "origin", "state", "vrp_prefix", "vrp_max_length", "vrp_asn", "vrp_match"}
+	row := func(v *clidecode.ROA, match string) []string {
+		fields := []string{r.Prefix.String(), u32(r.Origin), roaStates[r.State], "", "", "", ""}
+		if v != nil {
+			fields[3], fields[4], fields[5], fields[6] = v.Prefix.String(), strconv.Itoa(v.MaxLength), u32(v.ASN), match
+		}
+		return fields
+	}
+	var rows [][]string
+	for _, group := range []struct {
+		match string
+		vrps  []clidecode.ROA
+	}{{"matched", r.Matched}, {"too_long", r.TooLong}, {"other_origin", r.OtherOrigin}} {
+		for i := range group.vrps {
+			rows = append(rows, row(&group.vrps[i], group.match))
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, row(nil, ""))
+	}
+	return header, rows
+}
+
+func runROA(ctx context.Context, c *clidecode.BirdClient, _ options, args []string) (result, error) {
+	_, prefix, err := net.ParseCIDR(args[0])
+	if err != nil {
+		return nil, usageError{fmt.Sprintf("invalid prefix %q", args[0])}
+	}
+	asn, err := parseASN(args[1])
+	if err != nil {
+		return nil, err
+	}
+	r, found, err := c.GetROAResultContext(ctx, prefix, asn)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("ROA status of %s %w", prefix, errNotFound)
+	}
+	return roaResult(r), nil
+}
+
+type vrpsResult struct {
+	ASN  uint32          `json:"asn" yaml:"asn"`
+	VRPs []clidecode.VRP `json:"vrps" yaml:"vrps"`
+}
+
+func (v vrpsResult) text(w io.Writer) {
+	fmt.Fprintf(w, "Found %d VRPs for AS%d:\n", len(v.VRPs), v.ASN)
+	for i, vrp := range v.VRPs {
+		if i >= 10 {
+			fmt.Fprintf(w, "... and %d more\n", len(v.VRPs)-10)
+			break
+		}
+		fmt.Fprintf(w, "  %s MaxLen: %d\n", vrp.Prefix, vrp.Max)
+	}
+}
+
+func (v vrpsResult) rows() ([]string, [][]string) {
+	rows := make([][]string, 0, len(v.VRPs))
+	for _, vrp := range v.VRPs {
+		rows = append(rows, []string{u32(v.ASN), vrp.Prefix.String(), strconv.Itoa(vrp.Max)})
+	}
+	return []string{"asn", "prefix", "max_length"}, rows
+}
+
+func runVRPs(ctx context.Context, c *clidecode.BirdClient, _ options, args []string) (result, error) {
+	asn, err := parseASN(args[0])
+	if err != nil {
+		return nil, err
+	}
+	vrps, err := c.GetVRPsContext(ctx, asn)
+	if err != nil {
+		return nil, err
+	}
+	if vrps == nil {
+		vrps = []clidecode.VRP{}
+	}
+	return vrpsResult{ASN: asn, VRPs: vrps}, nil
+}
+
+type invalidsResult map[string][]string
+
+func (inv invalidsResult) text(w io.Writer) {
+	fmt.Fprintf(w, "Found %d ASNs with invalid prefixes\n", len(inv))
+	asns := make([]string, 0, len(inv))
+	for asn := range inv {
+		asns = append(asns, asn)
+	}
+	// Worst offenders first
+	sort.Slice(asns, func(i, j int) bool {
+		if len(inv[asns[i]]) != len(inv[asns[j]]) {
+			return len(inv[asns[i]]) > len(inv[asns[j]])
+		}
+		return asns[i] < asns[j]
+	})
+	for i, asn := range asns {
+		if i >= 5 {
+			fmt.Fprintf(w, "... and %d more ASNs\n", len(asns)-5)
+			break
+		}
+		fmt.Fprintf(w, "  AS%s: %d invalid prefixes\n", asn, len(inv[asn]))
+	}
+}
+
+func (inv invalidsResult) rows() ([]string, [][]string) {
+	asns := make([]string, 0, len(inv))
+	for asn := range inv {
+		asns = append(asns, asn)
+	}
+	sort.Slice(asns, func(i, j int) bool {
+		a, _ := strconv.ParseUint(asns[i], 10, 32)
+		b, _ := strconv.ParseUint(asns[j], 10, 32)
+		return a < b
+	})
+	var rows [][]string
+	for _, asn := range asns {
+		for _, prefix := range inv[asn] {
+			rows = append(rows, []string{asn, prefix})
+		}
+	}
+	return []string{"asn", "prefix"}, rows
+}
+
+func runInvalids(ctx context.Context, c *clidecode.BirdClient, _ options, _ []string) (result, error) {
+	inv, err := c.GetInvalidsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return invalidsResult(inv), nil
+}
+
+type rawResult string
+
+func (r rawResult) text(w io.Writer) { fmt.Fprintln(w, r) }
+
+func (r rawResult) rows() ([]string, [][]string) {
+	var rows [][]string
+	for _, line := range strings.Split(string(r), "\n") {
+		rows = append(rows, []string{line})
+	}
+	return []string{"output"}, rows
+}
+
+func runRaw(ctx context.Context, c *clidecode.BirdClient, _ options, args []string) (result, error) {
+	out, err := c.RunCommandContext(ctx, strings.Join(args, " "))
+	return rawResult(out), err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/mellowdrifter/clidecode/collector"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// exporterHandler serves the metrics of a collector over client on /metrics
+func exporterHandler(client *clidecode.BirdClient, opts options) http.Handler {
+	c := collector.New(client)
+	c.Timeout = opts.timeout
+	c.CacheTTL = opts.cache
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// exporter serves Prometheus metrics until interrupted
+func exporter(client *clidecode.BirdClient, opts options, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              opts.listen,
+		Handler:           exporterHandler(client, opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(stderr, "birdtest: serving metrics of %s on %s/metrics\n", client.SocketPath, opts.listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
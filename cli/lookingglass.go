@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/mellowdrifter/clidecode/lookingglass"
+)
+
+// lookingGlassHandler serves the looking glass over client
+func lookingGlassHandler(client *clidecode.BirdClient, opts options, stderr io.Writer) http.Handler {
+	lg := lookingglass.New(client)
+	lg.Timeout = opts.timeout
+	if opts.queries != "" {
+		lg.Queries = strings.Split(opts.queries, ",")
+	}
+	lg.Logf = log.New(stderr, "birdtest: ", log.LstdFlags).Printf
+	return lg
+}
+
+// lookingGlass serves the looking glass until interrupted
+func lookingGlass(client *clidecode.BirdClient, opts options, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              opts.listen,
+		Handler:           lookingGlassHandler(client, opts, stderr),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(stderr, "birdtest: serving looking glass for %s on %s\n", client.SocketPath, opts.listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
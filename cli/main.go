@@ -1,376 +1,260 @@
 package main
 
 import (
-	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"net"
+	"io"
 	"os"
-	"sort"
-	"strconv"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/mellowdrifter/clidecode"
+	"github.com/mellowdrifter/clidecode/collector"
+	"github.com/mellowdrifter/clidecode/lookingglass"
+	"gopkg.in/yaml.v3"
 )
 
-func main() {
-	fmt.Println("BIRD Socket Connection Test & Interactive Tool")
-	fmt.Println("===============================================")
-
-	// Potential socket paths to check
-	socketPaths := []string{
-		"/run/bird.ctl",
-		"/run/bird/bird.ctl",
-		"/run/bird3.ctl",
-		"/var/run/bird.ctl",
-		"/var/run/bird3.ctl",
-	}
-
-	var client *clidecode.BirdClient
-	var socketPath string
-
-	// Find socket
-	for _, path := range socketPaths {
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			continue
-		}
-		socketPath = path
-		break
-	}
-
-	if socketPath == "" {
-		fmt.Println("❌ No BIRD sockets found in standard locations.")
-		return
-	}
-
-	fmt.Printf("✅ Found socket at: %s\n", socketPath)
-	client = &clidecode.BirdClient{SocketPath: socketPath}
-
-	// Get Version
-	if ver, err := client.GetVersion(); err == nil {
-		fmt.Printf("   Daemon: %s\n", ver)
-	} else {
-		fmt.Printf("   Error getting version: %v\n", err)
-	}
-	fmt.Println()
-
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for {
-		printMenu()
-		fmt.Print("\nSelect an option: ")
-		if !scanner.Scan() {
-			break
-		}
-		choice := strings.TrimSpace(scanner.Text())
-
-		if choice == "0" || choice == "q" {
-			fmt.Println("Exiting...")
-			break
-		}
+// Exit codes
+const (
+	exitOK       = 0
+	exitError    = 1 // the query failed, or no socket was found
+	exitUsage    = 2 // bad flags or arguments
+	exitNotFound = 3 // the lookup found nothing
+)
 
-		err := handleChoice(choice, client, scanner)
-		if err != nil {
-			fmt.Printf("\n❌ Error: %v\n", err)
-		}
+// formats are the output formats. Only text leaves out detail.
+var formats = []string{"text", "json", "yaml", "csv", "table"}
 
-		fmt.Println("\nPress Enter to continue...")
-		scanner.Scan()
-	}
+// socketPaths are the standard socket locations, by BIRD major version.
+// Version 0 means any.
+var socketPaths = map[int][]string{
+	0: {"/run/bird.ctl", "/run/bird/bird.ctl", "/run/bird3.ctl", "/var/run/bird.ctl", "/var/run/bird3.ctl"},
+	2: {"/run/bird.ctl", "/run/bird/bird.ctl", "/var/run/bird.ctl"},
+	3: {"/run/bird3.ctl", "/run/bird/bird.ctl", "/var/run/bird3.ctl"},
 }
 
-func printMenu() {
-	fmt.Println("\nAvailable Commands:")
-	fmt.Println(" 1. GetBGPTotal (RIB/FIB counts)")
-	fmt.Println(" 2. GetPeers (Peer counts)")
-	fmt.Println(" 3. GetTotalSourceASNs (Unique ASN counts)")
-	fmt.Println(" 4. GetMasks (Prefix length distribution)")
-	fmt.Println(" 5. GetROAs (ROA state counts)")
-	fmt.Println(" 6. GetLargeCommunities (Large community counts)")
-	fmt.Println(" 7. GetIPv4FromSource (Prefixes by Origin ASN)")
-	fmt.Println(" 8. GetIPv6FromSource (Prefixes by Origin ASN)")
-	fmt.Println(" 9. GetOriginFromIP (Origin ASN for IP)")
-	fmt.Println("10. GetASPathFromIP (AS Path for IP)")
-	fmt.Println("11. GetRoute (FIB entry for IP)")
-	fmt.Println("12. GetROA (ROA status for IP/ASN)")
-	fmt.Println("13. GetVRPs (VRPs for ASN)")
-	fmt.Println("14. GetInvalids (RPKI Invalid prefixes)")
-	fmt.Println(" 0. Exit")
+// options are the flags shared by every command
+type options struct {
+	socket  string
+	bird    int
+	timeout time.Duration
+	output  string
+	v4, v6  bool
+
+	// exporter and lookingglass only
+	listen  string
+	cache   time.Duration
+	queries string
 }
 
-func handleChoice(choice string, client *clidecode.BirdClient, scanner *bufio.Scanner) error {
-	fmt.Println(strings.Repeat("-", 50))
-
-	switch choice {
-	case "1":
-		totals, err := client.GetBGPTotal()
-		if err != nil {
-			return err
-		}
-		fmt.Printf("IPv4 RIB: %d, FIB: %d\n", totals.V4Rib, totals.V4Fib)
-		fmt.Printf("IPv6 RIB: %d, FIB: %d\n", totals.V6Rib, totals.V6Fib)
-
-	case "2":
-		peers, err := client.GetPeers()
-		if err != nil {
-			return err
-		}
-		fmt.Printf("IPv4 Peers: %d configured, %d established\n", peers.V4c, peers.V4e)
-		fmt.Printf("IPv6 Peers: %d configured, %d established\n", peers.V6c, peers.V6e)
-
-	case "3":
-		asns, err := client.GetTotalSourceASNs()
-		if err != nil {
-			return err
-		}
-		fmt.Printf("AS4: %d, AS6: %d, AS10: %d\n", asns.As4, asns.As6, asns.As10)
-		fmt.Printf("AS4Only: %d, AS6Only: %d, ASBoth: %d\n", asns.As4Only, asns.As6Only, asns.AsBoth)
-
-	case "4":
-		masks, err := client.GetMasks()
-		if err != nil {
-			return err
-		}
+// register adds the flags to fs, defaulting to the current values so flags
+// given before the command carry over.
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.socket, "socket", o.socket, "BIRD control socket `path` (default: first found in the standard locations)")
+	fs.IntVar(&o.bird, "bird", o.bird, "BIRD major `version` (2 or 3), used to find the socket")
+	fs.DurationVar(&o.timeout, "timeout", o.timeout, "give up on a command after this long")
+	fs.StringVar(&o.output, "output", o.output, "output `format`: "+strings.Join(formats, ", "))
+	fs.BoolVar(&o.v4, "4", o.v4, "only IPv4 prefixes")
+	fs.BoolVar(&o.v6, "6", o.v6, "only IPv6 prefixes")
+	fs.StringVar(&o.listen, "listen", o.listen, "`address` to serve on (default :9324 for exporter, :8080 for lookingglass)")
+	fs.DurationVar(&o.cache, "cache", o.cache, "how long the exporter reuses full-table counts")
+	fs.StringVar(&o.queries, "queries", o.queries, "comma-separated `list` of queries the looking glass exposes (default all)")
+}
 
-		// Helper to sort and print masks
-		printSortedMasks := func(title string, m map[string]uint32) {
-			fmt.Println(title)
-			type kv struct {
-				Key   string
-				Value uint32
-			}
-			var ss []kv
-			for k, v := range m {
-				ss = append(ss, kv{k, v})
-			}
-			// Sort by Value descending
-			sort.Slice(ss, func(i, j int) bool {
-				return ss[i].Value > ss[j].Value
-			})
-			for _, kv := range ss {
-				fmt.Printf("  /%s: %d\n", kv.Key, kv.Value)
+func (o options) check() error {
+	if _, ok := socketPaths[o.bird]; !ok {
+		return fmt.Errorf("invalid BIRD version %d, expected 2 or 3", o.bird)
+	}
+	if !slices.Contains(formats, o.output) {
+		return fmt.Errorf("invalid output format %q, expected one of %s", o.output, strings.Join(formats, ", "))
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("invalid timeout %s", o.timeout)
+	}
+	if o.queries != "" {
+		known := []string{lookingglass.QueryRoute, lookingglass.QueryASPath, lookingglass.QueryOrigin,
+			lookingglass.QueryROA, lookingglass.QueryPrefixes, lookingglass.QueryVRPs}
+		for _, q := range strings.Split(o.queries, ",") {
+			if !slices.Contains(known, q) {
+				return fmt.Errorf("unknown looking glass query %q, expected some of %s", q, strings.Join(known, ","))
 			}
 		}
+	}
+	return nil
+}
 
-		printSortedMasks("IPv4 Masks:", masks[0])
-		printSortedMasks("IPv6 Masks:", masks[1])
-
-	case "5":
-		roas, err := client.GetROAs()
-		if err != nil {
-			return err
-		}
-		fmt.Printf("IPv4: Valid: %d, Invalid: %d, Unknown: %d\n", roas.V4v, roas.V4i, roas.V4u)
-		fmt.Printf("IPv6: Valid: %d, Invalid: %d, Unknown: %d\n", roas.V6v, roas.V6i, roas.V6u)
-
-	case "6":
-		large, err := client.GetLargeCommunities()
-		if err != nil {
-			return err
-		}
-		fmt.Printf("IPv4 with Large Communities: %d\n", large.V4)
-		fmt.Printf("IPv6 with Large Communities: %d\n", large.V6)
-
-	case "7":
-		fmt.Print("Enter Source ASN (e.g. 15169): ")
-		if !scanner.Scan() {
-			return nil
-		}
-		asnStr := scanner.Text()
-		asn, err := strconv.ParseUint(asnStr, 10, 32)
-		if err != nil {
-			return fmt.Errorf("invalid ASN: %v", err)
-		}
-		prefixes, err := client.GetIPv4FromSource(uint32(asn))
-		if err != nil {
-			return err
-		}
-		fmt.Printf("Found %d IPv4 prefixes for AS%d:\n", len(prefixes), asn)
-		for i, p := range prefixes {
-			if i >= 10 {
-				fmt.Printf("... and %d more\n", len(prefixes)-10)
+// client connects to the socket given, or the first found in the standard locations
+func (o options) client() (*clidecode.BirdClient, error) {
+	path := o.socket
+	if path == "" {
+		for _, p := range socketPaths[o.bird] {
+			if _, err := os.Stat(p); err == nil {
+				path = p
 				break
 			}
-			fmt.Println("  " + p.String())
 		}
+	}
+	if path == "" {
+		return nil, errors.New("no BIRD socket found in standard locations, use -socket")
+	}
+	return &clidecode.BirdClient{SocketPath: path, Timeout: o.timeout}, nil
+}
 
-	case "8":
-		fmt.Print("Enter Source ASN (e.g. 15169): ")
-		if !scanner.Scan() {
-			return nil
-		}
-		asnStr := scanner.Text()
-		asn, err := strconv.ParseUint(asnStr, 10, 32)
-		if err != nil {
-			return fmt.Errorf("invalid ASN: %v", err)
-		}
-		prefixes, err := client.GetIPv6FromSource(uint32(asn))
-		if err != nil {
-			return err
-		}
-		fmt.Printf("Found %d IPv6 prefixes for AS%d:\n", len(prefixes), asn)
-		for i, p := range prefixes {
-			if i >= 10 {
-				fmt.Printf("... and %d more\n", len(prefixes)-10)
-				break
-			}
-			fmt.Println("  " + p.String())
-		}
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
 
-	case "9":
-		fmt.Print("Enter IP Address: ")
-		if !scanner.Scan() {
-			return nil
-		}
-		ipStr := scanner.Text()
-		ip := net.ParseIP(ipStr)
-		if ip == nil {
-			return fmt.Errorf("invalid IP address")
-		}
-		asn, found, err := client.GetOriginFromIP(ip)
-		if err != nil {
-			return err
-		}
-		if !found {
-			fmt.Println("Origin not found")
-		} else {
-			fmt.Printf("Origin ASN: %d\n", asn)
-		}
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: birdtest [flags] <command> [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-22s %s\n", c.name+" "+c.args, c.help)
+	}
+	fmt.Fprintf(w, "  %-22s %s\n", "shell", "interactive menu")
+	fmt.Fprintf(w, "  %-22s %s\n", "exporter", "serve Prometheus metrics")
+	fmt.Fprintf(w, "  %-22s %s\n", "lookingglass", "serve a looking glass API and page")
+	fmt.Fprintln(w, "\nFlags:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
 
-	case "10":
-		fmt.Print("Enter IP Address: ")
-		if !scanner.Scan() {
-			return nil
-		}
-		ipStr := scanner.Text()
-		ip := net.ParseIP(ipStr)
-		if ip == nil {
-			return fmt.Errorf("invalid IP address")
-		}
-		path, found, err := client.GetASPathFromIP(ip)
-		if err != nil {
-			return err
-		}
-		if !found {
-			fmt.Println("AS Path not found")
-		} else {
-			fmt.Printf("AS Path: %v\n", path.Path)
-			if len(path.Set) > 0 {
-				fmt.Printf("AS Set: %v\n", path.Set)
-			}
-		}
+// run runs birdtest with args, returning the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := options{
+		timeout: clidecode.DefaultTimeout,
+		output:  "text",
+		cache:   collector.DefaultCacheTTL,
+	}
+	fs := flag.NewFlagSet("birdtest", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts.register(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			usage(stdout, fs)
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		usage(stderr, fs)
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		usage(stderr, fs)
+		return exitUsage
+	}
 
-	case "11":
-		fmt.Print("Enter IP Address: ")
-		if !scanner.Scan() {
-			return nil
-		}
-		ipStr := scanner.Text()
-		ip := net.ParseIP(ipStr)
-		if ip == nil {
-			return fmt.Errorf("invalid IP address")
-		}
-		route, found, err := client.GetRoute(ip)
-		if err != nil {
-			return err
-		}
-		if !found {
-			fmt.Println("Route not found")
-		} else {
-			fmt.Printf("Route: %s\n", route.String())
-		}
+	name := fs.Arg(0)
+	if name == "help" {
+		usage(stdout, fs)
+		return exitOK
+	}
+	cmd, ok := lookup(name)
+	daemon := name == "shell" || name == "exporter" || name == "lookingglass"
+	if !ok && !daemon {
+		fmt.Fprintf(stderr, "birdtest: unknown command %q\n", name)
+		usage(stderr, fs)
+		return exitUsage
+	}
 
-	case "12":
-		fmt.Print("Enter IP Prefix (e.g. 1.1.1.0/24): ")
-		if !scanner.Scan() {
-			return nil
-		}
-		prefixStr := scanner.Text()
-		_, prefix, err := net.ParseCIDR(prefixStr)
-		if err != nil {
-			return fmt.Errorf("invalid prefix: %v", err)
+	// Flags may also follow the command
+	sub := flag.NewFlagSet("birdtest "+name, flag.ContinueOnError)
+	sub.SetOutput(stderr)
+	opts.register(sub)
+	if err := sub.Parse(fs.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
 		}
+		return exitUsage
+	}
+	if err := opts.check(); err != nil {
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		return exitUsage
+	}
 
-		fmt.Print("Enter Origin ASN: ")
-		if !scanner.Scan() {
-			return nil
-		}
-		asnStr := scanner.Text()
-		asn, err := strconv.ParseUint(asnStr, 10, 32)
-		if err != nil {
-			return fmt.Errorf("invalid ASN: %v", err)
-		}
+	if n := sub.NArg(); !daemon && ((cmd.nargs >= 0 && n != cmd.nargs) || (cmd.nargs < 0 && n == 0)) {
+		fmt.Fprintf(stderr, "Usage: birdtest %s %s\n", cmd.name, cmd.args)
+		return exitUsage
+	}
 
-		status, found, err := client.GetROA(prefix, uint32(asn))
-		if err != nil {
-			return err
-		}
-		if !found {
-			fmt.Println("ROA status not found (Unknown)")
-		} else {
-			statusStr := "Unknown"
-			switch status {
-			case clidecode.RValid:
-				statusStr = "Valid"
-			case clidecode.RInvalid:
-				statusStr = "Invalid"
-			}
-			fmt.Printf("ROA Status: %s\n", statusStr)
-		}
+	client, err := opts.client()
+	if err != nil {
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		return exitError
+	}
+	switch name {
+	case "shell":
+		return shell(client, opts, stdin, stdout)
+	case "exporter":
+		opts.listen = cmp.Or(opts.listen, ":9324")
+		return exporter(client, opts, stderr)
+	case "lookingglass":
+		opts.listen = cmp.Or(opts.listen, ":8080")
+		return lookingGlass(client, opts, stderr)
+	}
 
-	case "13":
-		fmt.Print("Enter ASN: ")
-		if !scanner.Scan() {
-			return nil
-		}
-		asnStr := scanner.Text()
-		asn, err := strconv.ParseUint(asnStr, 10, 32)
-		if err != nil {
-			return fmt.Errorf("invalid ASN: %v", err)
-		}
-		vrps, err := client.GetVRPs(uint32(asn))
-		if err != nil {
-			return err
-		}
-		fmt.Printf("Found %d VRPs for AS%d:\n", len(vrps), asn)
-		for i, v := range vrps {
-			if i >= 10 {
-				fmt.Printf("... and %d more\n", len(vrps)-10)
-				break
-			}
-			fmt.Printf("  %s MaxLen: %d\n", v.Prefix.String(), v.Max)
-		}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	res, err := cmd.run(ctx, client, opts, sub.Args())
+	var uerr usageError
+	switch {
+	case errors.As(err, &uerr):
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		return exitUsage
+	case errors.Is(err, errNotFound):
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		return exitNotFound
+	case err != nil:
+		fmt.Fprintf(stderr, "birdtest: %s: %v\n", name, err)
+		return exitError
+	}
 
-	case "14":
-		invalids, err := client.GetInvalids()
-		if err != nil {
-			return err
-		}
-		fmt.Printf("Found %d ASNs with invalid prefixes\n", len(invalids))
-		count := 0
-		for asn, prefixes := range invalids {
-			if count >= 5 {
-				fmt.Printf("... and %d more ASNs\n", len(invalids)-5)
-				break
-			}
-			fmt.Printf("  AS%s: %d invalid prefixes\n", asn, len(prefixes))
-			count++
-		}
+	if err := write(stdout, opts.output, res); err != nil {
+		fmt.Fprintf(stderr, "birdtest: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
 
-	case "99":
-		fmt.Print("Enter Command: ")
-		if !scanner.Scan() {
-			return nil
-		}
-		cmd := scanner.Text()
-		out, err := client.RunCommand(cmd)
-		if err != nil {
+// write writes res to w in the given format
+func write(w io.Writer, format string, res result) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(res); err != nil {
 			return err
 		}
-		fmt.Println(out)
-
-	default:
-		fmt.Println("Invalid choice")
+		return enc.Close()
+	case "csv":
+		header, rows := res.rows()
+		cw := csv.NewWriter(w)
+		cw.Write(header)
+		cw.WriteAll(rows)
+		return cw.Error()
+	case "table":
+		header, rows := res.rows()
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(header, "\t")))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
 	}
-
+	res.text(w)
 	return nil
 }
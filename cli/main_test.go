@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/mellowdrifter/clidecode/fakebird"
+)
+
+func TestRun(t *testing.T) {
+	s := fakebird.NewServer(fakebird.Canned(map[string]fakebird.Reply{
+		"show status": fakebird.Lines(1000, "BIRD 2.0.8"),
+		"show route table master4 table master6 count": fakebird.Lines(1007, `2076414 of 2076414 routes for 1038207 networks in table master4
+471160 of 471160 routes for 235580 networks in table master6`),
+		"show route primary all for 192.0.2.1 table master4": fakebird.Error(8001, "Network not in table"),
+	}))
+	defer s.Close()
+
+	tests := []struct {
+		args []string
+		code int
+		want string
+	}{
+		{[]string{"-socket", s.Path, "version"}, exitOK, "BIRD 2.0.8\n"},
+		{[]string{"-socket", s.Path, "totals"}, exitOK, "IPv4 RIB: 2076414, FIB: 1038207\nIPv6 RIB: 471160, FIB: 235580\n"},
+		{[]string{"totals", "-socket", s.Path, "-output", "json"}, exitOK, `"v6_fib": 235580`},
+		{[]string{"-output", "yaml", "-socket", s.Path, "totals"}, exitOK, "v4_rib: 2076414\nv4_fib: 1038207\n"},
+		{[]string{"-output", "csv", "-socket", s.Path, "totals"}, exitOK, "family,rib,fib\nipv4,2076414,1038207\nipv6,471160,235580\n"},
+		{[]string{"-output", "table", "-socket", s.Path, "totals"}, exitOK, "FAMILY  RIB      FIB\nipv4    2076414  1038207\n"},
+		{[]string{"-socket", s.Path, "origin", "192.0.2.1"}, exitNotFound, ""},
+		{[]string{"-socket", s.Path, "origin", "192.0.2.300"}, exitUsage, ""},
+		{[]string{"-socket", s.Path, "origin"}, exitUsage, ""},
+		{[]string{"-socket", s.Path, "vrps", "ASx"}, exitUsage, ""},
+		{[]string{"-socket", s.Path, "-output", "xml", "totals"}, exitUsage, ""},
+		{[]string{"-socket", s.Path, "-bird", "4", "totals"}, exitUsage, ""},
+		{[]string{"-socket", s.Path, "nonsense"}, exitUsage, ""},
+		{[]string{"-socket", s.Path, "-queries", "route,invalids", "lookingglass"}, exitUsage, ""},
+		{[]string{"-socket", s.Path, "run", "show", "nonsense"}, exitError, ""},
+		{[]string{}, exitUsage, ""},
+		{[]string{"help"}, exitOK, "Usage: birdtest"},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(test.args, strings.NewReader(""), &stdout, &stderr)
+		if code != test.code {
+			t.Errorf("%q exited with %d, want %d (stderr %q)", test.args, code, test.code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), test.want) {
+			t.Errorf("%q printed %q, want %q", test.args, stdout.String(), test.want)
+		}
+	}
+}
+
+func TestShell(t *testing.T) {
+	s := fakebird.NewServer(fakebird.Canned(map[string]fakebird.Reply{
+		"show status": fakebird.Lines(1000, "BIRD 3.0.1"),
+		"show route primary all for 192.0.2.1 table master4": fakebird.Error(8001, "Network not in table"),
+	}))
+	defer s.Close()
+
+	var stdout bytes.Buffer
+	code := run([]string{"-socket", s.Path, "shell"}, strings.NewReader("9\n192.0.2.1\n\n42\n\nq\n"), &stdout, &stdout)
+	if code != exitOK {
+		t.Errorf("shell exited with %d", code)
+	}
+	for _, want := range []string{"Daemon: BIRD 3.0.1", "origin of 192.0.2.1 not found", "Invalid choice", "Exiting..."} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestRunUntruncated(t *testing.T) {
+	var v4 []string
+	for i := range 12 {
+		v4 = append(v4, fmt.Sprintf("10.%d.0.0/16-24 AS64500 [rpki1 2025-11-19] * (100)", i))
+	}
+	s := fakebird.NewServer(fakebird.Canned(map[string]fakebird.Reply{
+		"show route all table roa_v4 where net.asn=64500": fakebird.Lines(1007, strings.Join(v4, "\n")),
+		"show route all table roa_v6 where net.asn=64500": fakebird.Lines(1007, "2001:db8::/32-48 AS64500 [rpki1 2025-11-19] * (100)"),
+	}))
+	defer s.Close()
+
+	var text, csv bytes.Buffer
+	if code := run([]string{"-socket", s.Path, "vrps", "64500"}, nil, &text, io.Discard); code != exitOK {
+		t.Fatalf("vrps exited with %d", code)
+	}
+	if !strings.Contains(text.String(), "... and 3 more") {
+		t.Errorf("Expected text output to be truncated:\n%s", text.String())
+	}
+
+	if code := run([]string{"-socket", s.Path, "-output", "csv", "vrps", "AS64500"}, nil, &csv, io.Discard); code != exitOK {
+		t.Fatalf("vrps exited with %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 14 || lines[0] != "asn,prefix,max_length" || lines[13] != "64500,2001:db8::/32,48" {
+		t.Errorf("Unexpected CSV output:\n%s", csv.String())
+	}
+}
+
+func TestExporter(t *testing.T) {
+	s := fakebird.NewServer(fakebird.Canned(map[string]fakebird.Reply{
+		"show route table master4 table master6 count": fakebird.Lines(1007, `2076414 of 2076414 routes for 1038207 networks in table master4
+471160 of 471160 routes for 235580 networks in table master6`),
+	}))
+	defer s.Close()
+
+	opts := options{timeout: time.Second}
+	srv := httptest.NewServer(exporterHandler(&clidecode.BirdClient{SocketPath: s.Path}, opts))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`bgp_rib_routes{family="ipv4"} 2.076414e+06`,
+		`bgp_scrape_collector_success{collector="totals"} 1`,
+		`bgp_scrape_collector_success{collector="peers"} 0`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected %s in:\n%s", want, body)
+		}
+	}
+}
+
+func TestLookingGlass(t *testing.T) {
+	s := fakebird.NewServer(fakebird.Canned(map[string]fakebird.Reply{
+		"show route primary for 192.0.2.1 table master4": fakebird.Lines(1007, "192.0.2.0/24         unicast [bgp1 2025-11-19] * (100) [AS64500i]"),
+	}))
+	defer s.Close()
+
+	opts := options{timeout: time.Second, queries: "route"}
+	h := lookingGlassHandler(&clidecode.BirdClient{SocketPath: s.Path}, opts, io.Discard)
+
+	for target, want := range map[string]int{
+		"/api/route?ip=192.0.2.1": http.StatusOK,
+		"/api/vrps?asn=64500":     http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != want {
+			t.Errorf("%s returned %d %s, want %d", target, w.Code, w.Body, want)
+		}
+	}
+}
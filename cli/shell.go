@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mellowdrifter/clidecode"
+)
+
+// menuItem is an entry of the interactive menu, running a command with the
+// arguments prompted for
+type menuItem struct {
+	key, label string
+	command    string
+	prompts    []string
+	v4, v6     bool
+}
+
+var menu = []menuItem{
+	{key: "1", label: "GetBGPTotal (RIB/FIB counts)", command: "totals"},
+	{key: "2", label: "GetPeers (Peer counts)", command: "peers"},
+	{key: "3", label: "GetTotalSourceASNs (Unique ASN counts)", command: "asns"},
+	{key: "4", label: "GetMasks (Prefix length distribution)", command: "masks"},
+	{key: "5", label: "GetROAs (ROA state counts)", command: "roas"},
+	{key: "6", label: "GetLargeCommunities (Large community counts)", command: "large"},
+	{key: "7", label: "GetIPv4FromSource (Prefixes by Origin ASN)", command: "prefixes", prompts: []string{"Enter Source ASN (e.g. 15169): "}, v4: true},
+	{key: "8", label: "GetIPv6FromSource (Prefixes by Origin ASN)", command: "prefixes", prompts: []string{"Enter Source ASN (e.g. 15169): "}, v6: true},
+	{key: "9", label: "GetOriginFromIP (Origin ASN for IP)", command: "origin", prompts: []string{"Enter IP Address: "}},
+	{key: "10", label: "GetASPathFromIP (AS Path for IP)", command: "aspath", prompts: []string{"Enter IP Address: "}},
+	{key: "11", label: "GetRoute (FIB entry for IP)", command: "route", prompts: []string{"Enter IP Address: "}},
+	{key: "12", label: "GetROA (ROA status for IP/ASN)", command: "roa", prompts: []string{"Enter IP Prefix (e.g. 1.1.1.0/24): ", "Enter Origin ASN: "}},
+	{key: "13", label: "GetVRPs (VRPs for ASN)", command: "vrps", prompts: []string{"Enter ASN: "}},
+	{key: "14", label: "GetInvalids (RPKI Invalid prefixes)", command: "invalids"},
+	// Not listed
+	{key: "99", command: "run", prompts: []string{"Enter Command: "}},
+}
+
+// shell runs the interactive menu until stdin ends or the user quits
+func shell(client *clidecode.BirdClient, opts options, stdin io.Reader, stdout io.Writer) int {
+	fmt.Fprintln(stdout, "BIRD Socket Connection Test & Interactive Tool")
+	fmt.Fprintln(stdout, "===============================================")
+	fmt.Fprintf(stdout, "✅ Using socket at: %s\n", client.SocketPath)
+
+	if ver, err := client.GetVersion(); err == nil {
+		fmt.Fprintf(stdout, "   Daemon: %s\n", ver)
+	} else {
+		fmt.Fprintf(stdout, "   Error getting version: %v\n", err)
+	}
+	fmt.Fprintln(stdout)
+
+	scanner := bufio.NewScanner(stdin)
+	for {
+		printMenu(stdout)
+		fmt.Fprint(stdout, "\nSelect an option: ")
+		if !scanner.Scan() {
+			break
+		}
+		choice := strings.TrimSpace(scanner.Text())
+
+		if choice == "0" || choice == "q" {
+			fmt.Fprintln(stdout, "Exiting...")
+			break
+		}
+
+		err := handleChoice(choice, client, opts, scanner, stdout)
+		if err != nil {
+			fmt.Fprintf(stdout, "\n❌ Error: %v\n", err)
+		}
+
+		fmt.Fprintln(stdout, "\nPress Enter to continue...")
+		scanner.Scan()
+	}
+	return exitOK
+}
+
+func printMenu(w io.Writer) {
+	fmt.Fprintln(w, "\nAvailable Commands:")
+	for _, item := range menu {
+		if item.label != "" {
+			fmt.Fprintf(w, "%2s. %s\n", item.key, item.label)
+		}
+	}
+	fmt.Fprintln(w, " 0. Exit")
+}
+
+func handleChoice(choice string, client *clidecode.BirdClient, opts options, scanner *bufio.Scanner, w io.Writer) error {
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+
+	for _, item := range menu {
+		if item.key != choice {
+			continue
+		}
+		var args []string
+		for _, prompt := range item.prompts {
+			fmt.Fprint(w, prompt)
+			if !scanner.Scan() {
+				return nil
+			}
+			args = append(args, strings.TrimSpace(scanner.Text()))
+		}
+		opts.v4, opts.v6 = item.v4, item.v6
+
+		cmd, _ := lookup(item.command)
+		res, err := cmd.run(context.Background(), client, opts, args)
+		if errors.Is(err, errNotFound) {
+			fmt.Fprintln(w, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		res.text(w)
+		return nil
+	}
+
+	fmt.Fprintln(w, "Invalid choice")
+	return nil
+}
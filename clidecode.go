@@ -1,6 +1,10 @@
 package clidecode
 
-import "net"
+import (
+	"context"
+	"encoding/json"
+	"net"
+)
 
 // Decoder is an interface that represents a router to interrogate
 type Decoder interface {
@@ -49,18 +53,41 @@ type Decoder interface {
 	GetInvalids() (map[string][]string, error)
 }
 
+// DecoderContext mirrors Decoder with every method taking a context.
+// Cancelling the context, or reaching its deadline, aborts the query.
+type DecoderContext interface {
+	GetBGPTotalContext(context.Context) (Totals, error)
+	GetPeersContext(context.Context) (Peers, error)
+	GetTotalSourceASNsContext(context.Context) (ASNs, error)
+	GetMasksContext(context.Context) ([]map[string]uint32, error)
+	GetROAsContext(context.Context) (Roas, error)
+	GetLargeCommunitiesContext(context.Context) (Large, error)
+	GetIPv4FromSourceContext(context.Context, uint32) ([]*net.IPNet, error)
+	GetIPv6FromSourceContext(context.Context, uint32) ([]*net.IPNet, error)
+	GetOriginFromIPContext(context.Context, net.IP) (uint32, bool, error)
+	GetASPathFromIPContext(context.Context, net.IP) (ASPath, bool, error)
+	GetRouteContext(context.Context, net.IP) (*net.IPNet, bool, error)
+	GetROAContext(context.Context, *net.IPNet, uint32) (int, bool, error)
+	GetVRPsContext(context.Context, uint32) ([]VRP, error)
+	GetInvalidsContext(context.Context) (map[string][]string, error)
+}
+
 // Totals holds the total BGP route count.
 type Totals struct {
-	V4Rib, V4Fib uint32
-	V6Rib, V6Fib uint32
+	V4Rib uint32 `json:"v4_rib" yaml:"v4_rib"`
+	V4Fib uint32 `json:"v4_fib" yaml:"v4_fib"`
+	V6Rib uint32 `json:"v6_rib" yaml:"v6_rib"`
+	V6Fib uint32 `json:"v6_fib" yaml:"v6_fib"`
 }
 
 // Peers holds the total peers configured.
 // c = configured
 // e = established
 type Peers struct {
-	V4c, V4e uint32
-	V6c, V6e uint32
+	V4c uint32 `json:"v4_configured" yaml:"v4_configured"`
+	V4e uint32 `json:"v4_established" yaml:"v4_established"`
+	V6c uint32 `json:"v6_configured" yaml:"v6_configured"`
+	V6e uint32 `json:"v6_established" yaml:"v6_established"`
 }
 
 // ASNs holds counts for all types of ASNs.
@@ -71,9 +98,12 @@ type Peers struct {
 // as6Only: ASNs originating IPv6 only
 // asBoth:  ASNs originating both IPv4 and IPv6
 type ASNs struct {
-	As4, As6, As10   uint32
-	As4Only, As6Only uint32
-	AsBoth           uint32
+	As4     uint32 `json:"as4" yaml:"as4"`
+	As6     uint32 `json:"as6" yaml:"as6"`
+	As10    uint32 `json:"as10" yaml:"as10"`
+	As4Only uint32 `json:"as4_only" yaml:"as4_only"`
+	As6Only uint32 `json:"as6_only" yaml:"as6_only"`
+	AsBoth  uint32 `json:"as_both" yaml:"as_both"`
 }
 
 // Roas holds the ROA state.
@@ -81,19 +111,24 @@ type ASNs struct {
 // i = invalid
 // u = unknown
 type Roas struct {
-	V4v, V4i, V4u uint32
-	V6v, V6i, V6u uint32
+	V4v uint32 `json:"v4_valid" yaml:"v4_valid"`
+	V4i uint32 `json:"v4_invalid" yaml:"v4_invalid"`
+	V4u uint32 `json:"v4_unknown" yaml:"v4_unknown"`
+	V6v uint32 `json:"v6_valid" yaml:"v6_valid"`
+	V6i uint32 `json:"v6_invalid" yaml:"v6_invalid"`
+	V6u uint32 `json:"v6_unknown" yaml:"v6_unknown"`
 }
 
 // Large contains the amount of prefixes with large communities.
 type Large struct {
-	V4, V6 uint32
+	V4 uint32 `json:"v4" yaml:"v4"`
+	V6 uint32 `json:"v6" yaml:"v6"`
 }
 
 // ASPath contains a regular AS path and an AS Set, if it exists.
 type ASPath struct {
-	Path []uint32
-	Set  []uint32
+	Path []uint32 `json:"path" yaml:"path"`
+	Set  []uint32 `json:"set,omitempty" yaml:"set,omitempty"`
 }
 
 // VRP contains an IP prefix, a maximum length, and an origin AS number.
@@ -103,6 +138,47 @@ type VRP struct {
 	Max    int
 }
 
+// vrpJSON is the encoding of a VRP, with the prefix in CIDR notation
+type vrpJSON struct {
+	Prefix string `json:"prefix" yaml:"prefix"`
+	Max    int    `json:"max_length" yaml:"max_length"`
+}
+
+func (v VRP) encoded() vrpJSON {
+	e := vrpJSON{Max: v.Max}
+	if v.Prefix != nil {
+		e.Prefix = v.Prefix.String()
+	}
+	return e
+}
+
+// MarshalJSON encodes a VRP as {"prefix": "192.0.2.0/24", "max_length": 24}
+func (v VRP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.encoded())
+}
+
+// UnmarshalJSON decodes a VRP encoded by MarshalJSON
+func (v *VRP) UnmarshalJSON(data []byte) error {
+	var e vrpJSON
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	*v = VRP{Max: e.Max}
+	if e.Prefix != "" {
+		_, prefix, err := net.ParseCIDR(e.Prefix)
+		if err != nil {
+			return err
+		}
+		v.Prefix = prefix
+	}
+	return nil
+}
+
+// MarshalYAML encodes a VRP the same way as MarshalJSON
+func (v VRP) MarshalYAML() (any, error) {
+	return v.encoded(), nil
+}
+
 const (
 	// RUnknown = ROA Unknown
 	RUnknown = iota
@@ -0,0 +1,39 @@
+package clidecode
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("192.0.2.0/24")
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{Totals{1, 2, 3, 4}, `{"v4_rib":1,"v4_fib":2,"v6_rib":3,"v6_fib":4}`},
+		{Peers{1, 2, 3, 4}, `{"v4_configured":1,"v4_established":2,"v6_configured":3,"v6_established":4}`},
+		{ASNs{1, 2, 3, 4, 5, 6}, `{"as4":1,"as6":2,"as10":3,"as4_only":4,"as6_only":5,"as_both":6}`},
+		{Roas{1, 2, 3, 4, 5, 6}, `{"v4_valid":1,"v4_invalid":2,"v4_unknown":3,"v6_valid":4,"v6_invalid":5,"v6_unknown":6}`},
+		{Large{1, 2}, `{"v4":1,"v6":2}`},
+		{ASPath{Path: []uint32{64500, 64501}}, `{"path":[64500,64501]}`},
+		{ASPath{Path: []uint32{64500}, Set: []uint32{64502}}, `{"path":[64500],"set":[64502]}`},
+		{VRP{Prefix: prefix, Max: 24}, `{"prefix":"192.0.2.0/24","max_length":24}`},
+	}
+	for _, test := range tests {
+		got, err := json.Marshal(test.v)
+		if err != nil || string(got) != test.want {
+			t.Errorf("%T encoded as %s, %v; want %s", test.v, got, err, test.want)
+		}
+	}
+
+	var v VRP
+	if err := json.Unmarshal([]byte(`{"prefix":"192.0.2.0/24","max_length":24}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, VRP{Prefix: prefix, Max: 24}) {
+		t.Errorf("Decoded %+v", v)
+	}
+}
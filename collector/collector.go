@@ -0,0 +1,343 @@
+// Package collector exports the counts of a clidecode.Decoder as Prometheus metrics.
+//
+// Each scrape runs the queries concurrently, each bounded by its own timeout.
+// Queries walking the full table (source ASNs, masks, ROA states and large
+// communities) are cached, as they are slow against a full table and change
+// little between scrapes. A query that fails is reported through
+// bgp_scrape_collector_success, and its metrics are left out of that scrape.
+package collector
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "bgp"
+
+// DefaultCacheTTL is how long full-table results are reused unless set otherwise
+const DefaultCacheTTL = time.Minute
+
+var families = []string{"ipv4", "ipv6"}
+
+var (
+	ribDesc = prometheus.NewDesc(namespace+"_rib_routes",
+		"Routes in the RIB.", []string{"family"}, nil)
+	fibDesc = prometheus.NewDesc(namespace+"_fib_routes",
+		"Networks in the FIB.", []string{"family"}, nil)
+	peersConfiguredDesc = prometheus.NewDesc(namespace+"_peers_configured",
+		"BGP peers configured.", []string{"family"}, nil)
+	peersEstablishedDesc = prometheus.NewDesc(namespace+"_peers_established",
+		"BGP peers established.", []string{"family"}, nil)
+	sourceASNsDesc = prometheus.NewDesc(namespace+"_source_asns",
+		"Unique origin ASNs, by the families they originate.", []string{"origin"}, nil)
+	prefixLengthDesc = prometheus.NewDesc(namespace+"_prefix_length",
+		"Distribution of the prefix length of networks.", []string{"family"}, nil)
+	roaDesc = prometheus.NewDesc(namespace+"_roa_networks",
+		"Networks by RPKI origin validation state.", []string{"family", "state"}, nil)
+	largeDesc = prometheus.NewDesc(namespace+"_large_community_networks",
+		"Networks carrying large communities.", []string{"family"}, nil)
+	durationDesc = prometheus.NewDesc(namespace+"_scrape_collector_duration_seconds",
+		"Time taken by a collector during the last scrape, or when its result was cached.", []string{"collector"}, nil)
+	successDesc = prometheus.NewDesc(namespace+"_scrape_collector_success",
+		"Whether a collector succeeded during the last scrape.", []string{"collector"}, nil)
+	cachedDesc = prometheus.NewDesc(namespace+"_scrape_collector_cache_age_seconds",
+		"Age of the cached result served by a collector, 0 if it was queried.", []string{"collector"}, nil)
+)
+
+// Collector is a prometheus.Collector over a Decoder.
+// Fields should be set before the Collector is registered.
+type Collector struct {
+	Decoder clidecode.Decoder
+
+	// Timeout bounds each query, DefaultTimeout if 0. A Decoder that does not
+	// implement DecoderContext is left to finish a query the scrape gave up on.
+	Timeout time.Duration
+
+	// CacheTTL is how long full-table results are reused. 0 queries every scrape.
+	CacheTTL time.Duration
+
+	errors *prometheus.CounterVec
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+	now   func() time.Time
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+type cacheEntry struct {
+	metrics  []prometheus.Metric
+	at       time.Time
+	duration time.Duration
+}
+
+// scraper is a single query, turned into metrics
+type scraper struct {
+	name   string
+	cached bool
+	scrape func(ctx context.Context, c *Collector) ([]prometheus.Metric, error)
+}
+
+var scrapers = []scraper{
+	{"totals", false, scrapeTotals},
+	{"peers", false, scrapePeers},
+	{"asns", true, scrapeASNs},
+	{"masks", true, scrapeMasks},
+	{"roas", true, scrapeROAs},
+	{"large", true, scrapeLarge},
+}
+
+// New creates a Collector for d with the default timeout and cache lifetime
+func New(d clidecode.Decoder) *Collector {
+	return &Collector{
+		Decoder:  d,
+		Timeout:  clidecode.DefaultTimeout,
+		CacheTTL: DefaultCacheTTL,
+	}
+}
+
+func (c *Collector) init() {
+	if c.errors == nil {
+		c.errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrape_collector_errors_total",
+			Help:      "Failed queries, by collector.",
+		}, []string{"collector"})
+		c.cache = make(map[string]cacheEntry)
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.mu.Lock()
+	c.init()
+	c.mu.Unlock()
+
+	for _, d := range []*prometheus.Desc{
+		ribDesc, fibDesc, peersConfiguredDesc, peersEstablishedDesc, sourceASNsDesc,
+		prefixLengthDesc, roaDesc, largeDesc, durationDesc, successDesc, cachedDesc,
+	} {
+		ch <- d
+	}
+	c.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	c.init()
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range scrapers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.collect(s, ch)
+		}()
+	}
+	wg.Wait()
+	c.errors.Collect(ch)
+}
+
+// collect runs a single scraper, or replays its cached metrics
+func (c *Collector) collect(s scraper, ch chan<- prometheus.Metric) {
+	if s.cached && c.CacheTTL > 0 {
+		c.mu.Lock()
+		e, ok := c.cache[s.name]
+		age := c.now().Sub(e.at)
+		c.mu.Unlock()
+		if ok && age < c.CacheTTL {
+			for _, m := range e.metrics {
+				ch <- m
+			}
+			ch <- prometheus.MustNewConstMetric(durationDesc, prometheus.GaugeValue, e.duration.Seconds(), s.name)
+			ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, 1, s.name)
+			ch <- prometheus.MustNewConstMetric(cachedDesc, prometheus.GaugeValue, age.Seconds(), s.name)
+			return
+		}
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = clidecode.DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := c.now()
+	metrics, err := s.scrape(ctx, c)
+	duration := c.now().Sub(start)
+
+	success := 1.0
+	if err != nil {
+		success = 0
+		c.errors.WithLabelValues(s.name).Inc()
+	} else {
+		for _, m := range metrics {
+			ch <- m
+		}
+		if s.cached && c.CacheTTL > 0 {
+			c.mu.Lock()
+			c.cache[s.name] = cacheEntry{metrics: metrics, at: start, duration: duration}
+			c.mu.Unlock()
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(durationDesc, prometheus.GaugeValue, duration.Seconds(), s.name)
+	ch <- prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, success, s.name)
+	ch <- prometheus.MustNewConstMetric(cachedDesc, prometheus.GaugeValue, 0, s.name)
+}
+
+// query calls withCtx if the Decoder implements DecoderContext, and plain otherwise.
+// plain runs in its own goroutine, so the scrape can give up on it at the deadline.
+func query[T any](ctx context.Context, d clidecode.Decoder, plain func(clidecode.Decoder) (T, error), withCtx func(clidecode.DecoderContext, context.Context) (T, error)) (T, error) {
+	if dc, ok := d.(clidecode.DecoderContext); ok {
+		return withCtx(dc, ctx)
+	}
+
+	type answer struct {
+		v   T
+		err error
+	}
+	done := make(chan answer, 1)
+	go func() {
+		v, err := plain(d)
+		done <- answer{v, err}
+	}()
+	select {
+	case a := <-done:
+		return a.v, a.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func gauge(desc *prometheus.Desc, v uint32, labels ...string) prometheus.Metric {
+	return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(v), labels...)
+}
+
+func scrapeTotals(ctx context.Context, c *Collector) ([]prometheus.Metric, error) {
+	t, err := query(ctx, c.Decoder, clidecode.Decoder.GetBGPTotal, clidecode.DecoderContext.GetBGPTotalContext)
+	if err != nil {
+		return nil, err
+	}
+	return []prometheus.Metric{
+		gauge(ribDesc, t.V4Rib, "ipv4"),
+		gauge(ribDesc, t.V6Rib, "ipv6"),
+		gauge(fibDesc, t.V4Fib, "ipv4"),
+		gauge(fibDesc, t.V6Fib, "ipv6"),
+	}, nil
+}
+
+func scrapePeers(ctx context.Context, c *Collector) ([]prometheus.Metric, error) {
+	p, err := query(ctx, c.Decoder, clidecode.Decoder.GetPeers, clidecode.DecoderContext.GetPeersContext)
+	if err != nil {
+		return nil, err
+	}
+	return []prometheus.Metric{
+		gauge(peersConfiguredDesc, p.V4c, "ipv4"),
+		gauge(peersConfiguredDesc, p.V6c, "ipv6"),
+		gauge(peersEstablishedDesc, p.V4e, "ipv4"),
+		gauge(peersEstablishedDesc, p.V6e, "ipv6"),
+	}, nil
+}
+
+func scrapeASNs(ctx context.Context, c *Collector) ([]prometheus.Metric, error) {
+	a, err := query(ctx, c.Decoder, clidecode.Decoder.GetTotalSourceASNs, clidecode.DecoderContext.GetTotalSourceASNsContext)
+	if err != nil {
+		return nil, err
+	}
+	return []prometheus.Metric{
+		gauge(sourceASNsDesc, a.As4, "ipv4"),
+		gauge(sourceASNsDesc, a.As6, "ipv6"),
+		gauge(sourceASNsDesc, a.As10, "any"),
+		gauge(sourceASNsDesc, a.As4Only, "ipv4_only"),
+		gauge(sourceASNsDesc, a.As6Only, "ipv6_only"),
+		gauge(sourceASNsDesc, a.AsBoth, "both"),
+	}, nil
+}
+
+// prefixLengthBuckets are the upper bounds of the prefix length histogram
+// buckets, by family. Anything longer than a /24 or /48 is rare in a full
+// table, but blackholes and the like still show up.
+var prefixLengthBuckets = [][]int{
+	lengths(8, 32),
+	append(lengths(16, 64), 128),
+}
+
+func lengths(from, to int) []int {
+	var l []int
+	for i := from; i <= to; i++ {
+		l = append(l, i)
+	}
+	return l
+}
+
+// scrapeMasks exports the masks as a histogram of prefix lengths
+func scrapeMasks(ctx context.Context, c *Collector) ([]prometheus.Metric, error) {
+	masks, err := query(ctx, c.Decoder, clidecode.Decoder.GetMasks, clidecode.DecoderContext.GetMasksContext)
+	if err != nil {
+		return nil, err
+	}
+	var metrics []prometheus.Metric
+	for i, bounds := range prefixLengthBuckets {
+		var counts map[string]uint32
+		if i < len(masks) {
+			counts = masks[i]
+		}
+		var count uint64
+		var sum float64
+		buckets := make(map[float64]uint64, len(bounds))
+		next := 0
+		for length := 0; length <= bounds[len(bounds)-1]; length++ {
+			n := uint64(counts[strconv.Itoa(length)])
+			count += n
+			sum += float64(n) * float64(length)
+			if length == bounds[next] {
+				buckets[float64(length)] = count
+				next++
+			}
+		}
+		m, err := prometheus.NewConstHistogram(prefixLengthDesc, count, sum, buckets, families[i])
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+func scrapeROAs(ctx context.Context, c *Collector) ([]prometheus.Metric, error) {
+	r, err := query(ctx, c.Decoder, clidecode.Decoder.GetROAs, clidecode.DecoderContext.GetROAsContext)
+	if err != nil {
+		return nil, err
+	}
+	return []prometheus.Metric{
+		gauge(roaDesc, r.V4v, "ipv4", "valid"),
+		gauge(roaDesc, r.V4i, "ipv4", "invalid"),
+		gauge(roaDesc, r.V4u, "ipv4", "unknown"),
+		gauge(roaDesc, r.V6v, "ipv6", "valid"),
+		gauge(roaDesc, r.V6i, "ipv6", "invalid"),
+		gauge(roaDesc, r.V6u, "ipv6", "unknown"),
+	}, nil
+}
+
+func scrapeLarge(ctx context.Context, c *Collector) ([]prometheus.Metric, error) {
+	l, err := query(ctx, c.Decoder, clidecode.Decoder.GetLargeCommunities, clidecode.DecoderContext.GetLargeCommunitiesContext)
+	if err != nil {
+		return nil, err
+	}
+	return []prometheus.Metric{
+		gauge(largeDesc, l.V4, "ipv4"),
+		gauge(largeDesc, l.V6, "ipv6"),
+	}, nil
+}
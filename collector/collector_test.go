@@ -0,0 +1,205 @@
+package collector
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/expfmt"
+)
+
+func calls(f *clidecode.FakeConn, method string) int {
+	n := 0
+	for _, c := range f.Calls() {
+		if c.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCollector(t *testing.T) {
+	c := New(clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"))
+
+	want := `
+# HELP bgp_fib_routes Networks in the FIB.
+# TYPE bgp_fib_routes gauge
+bgp_fib_routes{family="ipv4"} 4
+bgp_fib_routes{family="ipv6"} 2
+# HELP bgp_large_community_networks Networks carrying large communities.
+# TYPE bgp_large_community_networks gauge
+bgp_large_community_networks{family="ipv4"} 1
+bgp_large_community_networks{family="ipv6"} 0
+# HELP bgp_peers_configured BGP peers configured.
+# TYPE bgp_peers_configured gauge
+bgp_peers_configured{family="ipv4"} 2
+bgp_peers_configured{family="ipv6"} 1
+# HELP bgp_peers_established BGP peers established.
+# TYPE bgp_peers_established gauge
+bgp_peers_established{family="ipv4"} 1
+bgp_peers_established{family="ipv6"} 1
+# HELP bgp_rib_routes Routes in the RIB.
+# TYPE bgp_rib_routes gauge
+bgp_rib_routes{family="ipv4"} 5
+bgp_rib_routes{family="ipv6"} 2
+# HELP bgp_roa_networks Networks by RPKI origin validation state.
+# TYPE bgp_roa_networks gauge
+bgp_roa_networks{family="ipv4",state="invalid"} 1
+bgp_roa_networks{family="ipv4",state="unknown"} 2
+bgp_roa_networks{family="ipv4",state="valid"} 1
+bgp_roa_networks{family="ipv6",state="invalid"} 1
+bgp_roa_networks{family="ipv6",state="unknown"} 0
+bgp_roa_networks{family="ipv6",state="valid"} 1
+# HELP bgp_scrape_collector_success Whether a collector succeeded during the last scrape.
+# TYPE bgp_scrape_collector_success gauge
+bgp_scrape_collector_success{collector="asns"} 1
+bgp_scrape_collector_success{collector="large"} 1
+bgp_scrape_collector_success{collector="masks"} 1
+bgp_scrape_collector_success{collector="peers"} 1
+bgp_scrape_collector_success{collector="roas"} 1
+bgp_scrape_collector_success{collector="totals"} 1
+# HELP bgp_source_asns Unique origin ASNs, by the families they originate.
+# TYPE bgp_source_asns gauge
+bgp_source_asns{origin="any"} 4
+bgp_source_asns{origin="both"} 1
+bgp_source_asns{origin="ipv4"} 4
+bgp_source_asns{origin="ipv4_only"} 3
+bgp_source_asns{origin="ipv6"} 1
+bgp_source_asns{origin="ipv6_only"} 0
+`
+	names := []string{
+		"bgp_fib_routes", "bgp_large_community_networks", "bgp_peers_configured", "bgp_peers_established",
+		"bgp_rib_routes", "bgp_roa_networks", "bgp_scrape_collector_success", "bgp_source_asns",
+	}
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want), names...); err != nil {
+		t.Error(err)
+	}
+
+	problems, err := testutil.CollectAndLint(c)
+	if err != nil || len(problems) > 0 {
+		t.Errorf("Lint found %v, %v", problems, err)
+	}
+}
+
+func TestPrefixLengthHistogram(t *testing.T) {
+	c := New(clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"))
+
+	// 8.0.0.0/9 and three /24s, two IPv6 /32s
+	want := []string{
+		`bgp_prefix_length_bucket{family="ipv4",le="8"} 0`,
+		`bgp_prefix_length_bucket{family="ipv4",le="9"} 1`,
+		`bgp_prefix_length_bucket{family="ipv4",le="23"} 1`,
+		`bgp_prefix_length_bucket{family="ipv4",le="24"} 4`,
+		`bgp_prefix_length_sum{family="ipv4"} 81`,
+		`bgp_prefix_length_count{family="ipv4"} 4`,
+		`bgp_prefix_length_bucket{family="ipv6",le="16"} 0`,
+		`bgp_prefix_length_bucket{family="ipv6",le="32"} 2`,
+		`bgp_prefix_length_bucket{family="ipv6",le="128"} 2`,
+		`bgp_prefix_length_bucket{family="ipv6",le="+Inf"} 2`,
+	}
+	out, err := testutil.CollectAndFormat(c, expfmt.TypeTextPlain, "bgp_prefix_length")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range want {
+		if !strings.Contains(string(out), line+"\n") {
+			t.Errorf("Expected %s in:\n%s", line, out)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	f := clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json")
+	c := New(f)
+	now := time.Unix(1700000000, 0)
+	c.now = func() time.Time { return now }
+
+	testutil.CollectAndCount(c)
+	now = now.Add(30 * time.Second)
+	out, err := testutil.CollectAndFormat(c, expfmt.TypeTextPlain, "bgp_scrape_collector_cache_age_seconds")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := calls(f, "GetBGPTotal"); n != 2 {
+		t.Errorf("Expected totals to be queried every scrape, got %d calls", n)
+	}
+	if n := calls(f, "GetMasks"); n != 1 {
+		t.Errorf("Expected masks to be cached, got %d calls", n)
+	}
+	for _, line := range []string{
+		`bgp_scrape_collector_cache_age_seconds{collector="masks"} 30`,
+		`bgp_scrape_collector_cache_age_seconds{collector="totals"} 0`,
+	} {
+		if !strings.Contains(string(out), line+"\n") {
+			t.Errorf("Expected %s in:\n%s", line, out)
+		}
+	}
+
+	now = now.Add(DefaultCacheTTL)
+	testutil.CollectAndCount(c)
+	if n := calls(f, "GetMasks"); n != 2 {
+		t.Errorf("Expected expired masks to be queried again, got %d calls", n)
+	}
+
+	c.CacheTTL = 0
+	testutil.CollectAndCount(c)
+	if n := calls(f, "GetMasks"); n != 3 {
+		t.Errorf("Expected masks to be queried without a cache, got %d calls", n)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	f := clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json")
+	f.Errors = map[string]error{"GetPeers": errors.New("socket closed")}
+	f.Latency = map[string]time.Duration{"GetROAs": time.Second}
+	c := New(f)
+	c.Timeout = 10 * time.Millisecond
+
+	want := `
+# HELP bgp_scrape_collector_errors_total Failed queries, by collector.
+# TYPE bgp_scrape_collector_errors_total counter
+bgp_scrape_collector_errors_total{collector="peers"} 1
+bgp_scrape_collector_errors_total{collector="roas"} 1
+# HELP bgp_scrape_collector_success Whether a collector succeeded during the last scrape.
+# TYPE bgp_scrape_collector_success gauge
+bgp_scrape_collector_success{collector="asns"} 1
+bgp_scrape_collector_success{collector="large"} 1
+bgp_scrape_collector_success{collector="masks"} 1
+bgp_scrape_collector_success{collector="peers"} 0
+bgp_scrape_collector_success{collector="roas"} 0
+bgp_scrape_collector_success{collector="totals"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want), "bgp_scrape_collector_errors_total", "bgp_scrape_collector_success"); err != nil {
+		t.Error(err)
+	}
+
+	// Failed queries leave out their metrics, and are not cached
+	if n := testutil.CollectAndCount(c, "bgp_peers_configured", "bgp_roa_networks"); n != 0 {
+		t.Errorf("Expected no metrics from failed collectors, got %d", n)
+	}
+	if n := calls(f, "GetROAs"); n != 2 {
+		t.Errorf("Expected the failed ROA query to be retried, got %d calls", n)
+	}
+}
+
+// plainDecoder hides the context methods of a Decoder
+type plainDecoder struct{ clidecode.Decoder }
+
+func TestPlainDecoder(t *testing.T) {
+	f := clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json")
+	f.Latency = map[string]time.Duration{"GetBGPTotal": time.Second}
+	c := New(plainDecoder{f})
+	c.Timeout = 10 * time.Millisecond
+
+	start := time.Now()
+	if n := testutil.CollectAndCount(c, "bgp_peers_configured", "bgp_rib_routes"); n != 2 {
+		t.Errorf("Expected only the peers metrics, got %d", n)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Scrape waited %s for a slow query", d)
+	}
+}
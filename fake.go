@@ -1,78 +1,362 @@
 package clidecode
 
-import "net"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
 
-// FakeConn will be a connection to a fake instance.
-type FakeConn struct{}
+	"gopkg.in/yaml.v3"
+)
+
+// FakeConn is a Decoder test double answering from a Fixture.
+// Every answer is computed from the same routes, peers and VRPs, so totals,
+// masks, origins and ROA states always agree with each other.
+// Fixture is read only: the answers are worked out from it once, by NewFakeConn.
+// Errors and Latency inject failures and delays per method name, e.g. "GetPeers",
+// and should be set before the FakeConn is used.
+type FakeConn struct {
+	Fixture Fixture
+	Errors  map[string]error
+	Latency map[string]time.Duration
+
+	table *rib
+	mu    sync.Mutex
+	calls []FakeCall
+}
+
+var _ Decoder = (*FakeConn)(nil)
+var _ DecoderContext = (*FakeConn)(nil)
+
+// Fixture describes the state of a fake router.
+type Fixture struct {
+	Routes []FakeRoute `json:"routes" yaml:"routes"`
+	Peers  []FakePeer  `json:"peers" yaml:"peers"`
+	VRPs   []FakeVRP   `json:"vrps" yaml:"vrps"`
+}
+
+// FakeRoute is a route in a Fixture. The first route listed for a prefix is the primary one.
+type FakeRoute struct {
+	Prefix           netip.Prefix     `json:"prefix" yaml:"prefix"`
+	ASPath           []uint32         `json:"as_path" yaml:"as_path"`
+	ASSet            []uint32         `json:"as_set" yaml:"as_set"`
+	LargeCommunities []LargeCommunity `json:"large_communities" yaml:"large_communities"`
+}
+
+// FakePeer is a BGP peer in a Fixture.
+type FakePeer struct {
+	Name        string     `json:"name" yaml:"name"`
+	Address     netip.Addr `json:"address" yaml:"address"`
+	Established bool       `json:"established" yaml:"established"`
+}
+
+// FakeVRP is a Validated ROA Payload in a Fixture.
+type FakeVRP struct {
+	Prefix    netip.Prefix `json:"prefix" yaml:"prefix"`
+	MaxLength int          `json:"max_length" yaml:"max_length"`
+	ASN       uint32       `json:"asn" yaml:"asn"`
+}
+
+// FakeCall is a call made to a FakeConn.
+type FakeCall struct {
+	Method string
+	Args   []any
+}
+
+// NewFakeConn creates a FakeConn answering from f
+func NewFakeConn(f Fixture) *FakeConn {
+	t := &rib{}
+	for _, r := range f.Routes {
+		t.add(ribRoute{
+			prefix: r.Prefix,
+			asPath: r.ASPath,
+			asSet:  r.ASSet,
+			large:  len(r.LargeCommunities) > 0,
+		})
+	}
+	roas := make([]ROA, 0, len(f.VRPs))
+	for _, v := range f.VRPs {
+		roas = append(roas, ROA{Prefix: v.Prefix, MaxLength: v.MaxLength, ASN: v.ASN})
+	}
+	t.validator = NewValidator(roas)
+	return &FakeConn{Fixture: f, table: t}
+}
+
+// LoadFakeConn creates a FakeConn answering from a fixture file.
+// Files ending in .yaml or .yml are read as YAML, anything else as JSON.
+func LoadFakeConn(path string) (*FakeConn, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &f)
+	default:
+		err = json.Unmarshal(data, &f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return NewFakeConn(f), nil
+}
+
+// MustLoadFakeConn is like LoadFakeConn but fails the test if the fixture cannot be loaded
+func MustLoadFakeConn(t testing.TB, path string) *FakeConn {
+	t.Helper()
+	f, err := LoadFakeConn(path)
+	if err != nil {
+		t.Fatalf("LoadFakeConn failed: %v", err)
+	}
+	return f
+}
+
+// Calls returns every call made so far, in order.
+func (f *FakeConn) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// ResetCalls forgets all recorded calls.
+func (f *FakeConn) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// enter records a call, then applies the latency and error configured for method.
+func (f *FakeConn) enter(ctx context.Context, method string, args ...any) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, FakeCall{Method: method, Args: args})
+	f.mu.Unlock()
+
+	if d := f.Latency[method]; d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.Errors[method]
+}
 
 // GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
-func (f FakeConn) GetBGPTotal() (Totals, error) {
-	return Totals{}, nil
+func (f *FakeConn) GetBGPTotal() (Totals, error) {
+	return f.GetBGPTotalContext(context.Background())
+}
+
+// GetBGPTotalContext is like GetBGPTotal but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetBGPTotalContext(ctx context.Context) (Totals, error) {
+	if err := f.enter(ctx, "GetBGPTotal"); err != nil {
+		return Totals{}, err
+	}
+	return f.table.totals(), nil
 }
 
 // GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
-func (f FakeConn) GetPeers() (Peers, error) {
-	return Peers{}, nil
+func (f *FakeConn) GetPeers() (Peers, error) {
+	return f.GetPeersContext(context.Background())
+}
+
+// GetPeersContext is like GetPeers but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetPeersContext(ctx context.Context) (Peers, error) {
+	if err := f.enter(ctx, "GetPeers"); err != nil {
+		return Peers{}, err
+	}
+	sessions := make([]BGPSession, 0, len(f.Fixture.Peers))
+	for _, p := range f.Fixture.Peers {
+		s := BGPSession{Name: p.Name, NeighborAddress: IPFromAddr(p.Address), BGPState: "Active"}
+		if p.Established {
+			s.BGPState = "Established"
+		}
+		sessions = append(sessions, s)
+	}
+	return countPeers(sessions, ClassifyByNeighbor), nil
 }
 
 // GetTotalSourceASNs returns total amount of unique ASNs
-func (f FakeConn) GetTotalSourceASNs() (ASNs, error) {
-	return ASNs{}, nil
+func (f *FakeConn) GetTotalSourceASNs() (ASNs, error) {
+	return f.GetTotalSourceASNsContext(context.Background())
+}
+
+// GetTotalSourceASNsContext is like GetTotalSourceASNs but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetTotalSourceASNsContext(ctx context.Context) (ASNs, error) {
+	if err := f.enter(ctx, "GetTotalSourceASNs"); err != nil {
+		return ASNs{}, err
+	}
+	return f.table.sourceASNs(), nil
 }
 
 // GetMasks returns the total count of each mask value
 // First item is IPv4, second item is IPv6
-func (f FakeConn) GetMasks() ([]map[string]uint32, error) {
-	v4 := make(map[string]uint32)
-	v6 := make(map[string]uint32)
-	return []map[string]uint32{v4, v6}, nil
+func (f *FakeConn) GetMasks() ([]map[string]uint32, error) {
+	return f.GetMasksContext(context.Background())
+}
+
+// GetMasksContext is like GetMasks but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	if err := f.enter(ctx, "GetMasks"); err != nil {
+		return nil, err
+	}
+	return f.table.masks(), nil
 }
 
 // GetROAs returns total amount of all ROA states
-func (f FakeConn) GetROAs() (Roas, error) {
-	return Roas{}, nil
+func (f *FakeConn) GetROAs() (Roas, error) {
+	return f.GetROAsContext(context.Background())
+}
+
+// GetROAsContext is like GetROAs but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetROAsContext(ctx context.Context) (Roas, error) {
+	if err := f.enter(ctx, "GetROAs"); err != nil {
+		return Roas{}, err
+	}
+	return f.table.roas(), nil
 }
 
 // GetLargeCommunities returns the amount of prefixes that have large communities attached (RFC8092)
-func (f FakeConn) GetLargeCommunities() (Large, error) {
-	return Large{}, nil
+func (f *FakeConn) GetLargeCommunities() (Large, error) {
+	return f.GetLargeCommunitiesContext(context.Background())
+}
+
+// GetLargeCommunitiesContext is like GetLargeCommunities but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetLargeCommunitiesContext(ctx context.Context) (Large, error) {
+	if err := f.enter(ctx, "GetLargeCommunities"); err != nil {
+		return Large{}, err
+	}
+	return f.table.large(), nil
 }
 
 // GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN.
-func (f FakeConn) GetIPv4FromSource(uint32) ([]*net.IPNet, error) {
-	return nil, nil
+func (f *FakeConn) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
+	return f.GetIPv4FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv4FromSourceContext is like GetIPv4FromSource but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	if err := f.enter(ctx, "GetIPv4FromSource", asn); err != nil {
+		return nil, err
+	}
+	return f.table.fromSource(asn, true), nil
 }
 
 // GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN.
-func (f FakeConn) GetIPv6FromSource(uint32) ([]*net.IPNet, error) {
-	return nil, nil
+func (f *FakeConn) GetIPv6FromSource(asn uint32) ([]*net.IPNet, error) {
+	return f.GetIPv6FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv6FromSourceContext is like GetIPv6FromSource but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	if err := f.enter(ctx, "GetIPv6FromSource", asn); err != nil {
+		return nil, err
+	}
+	return f.table.fromSource(asn, false), nil
 }
 
 // GetOriginFromIP will return the origin ASN from a source IP.
-func (f FakeConn) GetOriginFromIP(net.IP) (uint32, bool, error) {
-	return 0, false, nil
+func (f *FakeConn) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
+	return f.GetOriginFromIPContext(context.Background(), ip)
+}
+
+// GetOriginFromIPContext is like GetOriginFromIP but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	if err := f.enter(ctx, "GetOriginFromIP", ip); err != nil {
+		return 0, false, err
+	}
+	r, found := f.table.lookup(ip)
+	if !found {
+		return 0, false, nil
+	}
+	return r.origin(), true, nil
 }
 
 // GetASPathFromIP will return the AS path, as well as as-set if any from a source IP.
-func (f FakeConn) GetASPathFromIP(net.IP) (ASPath, bool, error) {
-	return ASPath{}, false, nil
+func (f *FakeConn) GetASPathFromIP(ip net.IP) (ASPath, bool, error) {
+	return f.GetASPathFromIPContext(context.Background(), ip)
+}
+
+// GetASPathFromIPContext is like GetASPathFromIP but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetASPathFromIPContext(ctx context.Context, ip net.IP) (ASPath, bool, error) {
+	if err := f.enter(ctx, "GetASPathFromIP", ip); err != nil {
+		return ASPath{}, false, err
+	}
+	r, found := f.table.lookup(ip)
+	if !found {
+		return ASPath{}, false, nil
+	}
+	return ASPath{Path: r.asPath, Set: r.asSet}, true, nil
 }
 
 // GetRoute will return the current FIB entry, if any, from a source IP.
-func (f FakeConn) GetRoute(net.IP) (*net.IPNet, bool, error) {
-	return nil, false, nil
+func (f *FakeConn) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
+	return f.GetRouteContext(context.Background(), ip)
+}
+
+// GetRouteContext is like GetRoute but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	if err := f.enter(ctx, "GetRoute", ip); err != nil {
+		return nil, false, err
+	}
+	r, found := f.table.lookup(ip)
+	if !found {
+		return nil, false, nil
+	}
+	return IPNetFromPrefix(r.prefix), true, nil
 }
 
 // GetROA will return the ROA status, if any, from a source IP.
-func (f FakeConn) GetROA(*net.IPNet, uint32) (int, bool, error) {
-	return 0, false, nil
+func (f *FakeConn) GetROA(prefix *net.IPNet, asn uint32) (int, bool, error) {
+	return f.GetROAContext(context.Background(), prefix, asn)
+}
+
+// GetROAContext is like GetROA but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	if err := f.enter(ctx, "GetROA", prefix, asn); err != nil {
+		return 0, false, err
+	}
+	p, ok := PrefixFromIPNet(prefix)
+	if !ok {
+		return 0, false, nil
+	}
+	return f.table.validator.Validate(p, asn), true, nil
 }
 
 // GetVRPs will return all Validated ROA Payloads for an ASN.
-func (f FakeConn) GetVRPs(uint32) {}
+func (f *FakeConn) GetVRPs(asn uint32) ([]VRP, error) {
+	return f.GetVRPsContext(context.Background(), asn)
+}
+
+// GetVRPsContext is like GetVRPs but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetVRPsContext(ctx context.Context, asn uint32) ([]VRP, error) {
+	if err := f.enter(ctx, "GetVRPs", asn); err != nil {
+		return nil, err
+	}
+	return vrpsFor(f.table.validator, asn), nil
+}
 
 // GetInvalids returns a map of ASNs that are advertising RPKI invalid prefixes.
 // It also includes all those prefixes being advertised.
-func (f FakeConn) GetInvalids() (map[string][]string, error) {
-	return nil, nil
+func (f *FakeConn) GetInvalids() (map[string][]string, error) {
+	return f.GetInvalidsContext(context.Background())
+}
+
+// GetInvalidsContext is like GetInvalids but honours the cancellation and deadline of ctx
+func (f *FakeConn) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	if err := f.enter(ctx, "GetInvalids"); err != nil {
+		return nil, err
+	}
+	return f.table.invalids(), nil
 }
@@ -0,0 +1,120 @@
+package clidecode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFakeConn(t *testing.T) {
+	f, err := LoadFakeConn("testdata/fakeconn.json")
+	if err != nil {
+		t.Fatalf("LoadFakeConn failed: %v", err)
+	}
+
+	totals, err := f.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if totals != (Totals{V4Rib: 5, V4Fib: 4, V6Rib: 2, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+
+	peers, _ := f.GetPeers()
+	if peers != (Peers{V4c: 2, V4e: 1, V6c: 1, V6e: 1}) {
+		t.Errorf("Unexpected peers %+v", peers)
+	}
+
+	asns, _ := f.GetTotalSourceASNs()
+	if asns != (ASNs{As4: 4, As6: 1, As10: 4, As4Only: 3, AsBoth: 1}) {
+		t.Errorf("Unexpected ASNs %+v", asns)
+	}
+
+	masks, _ := f.GetMasks()
+	if !reflect.DeepEqual(masks, []map[string]uint32{{"24": 3, "9": 1}, {"32": 2}}) {
+		t.Errorf("Unexpected masks %v", masks)
+	}
+
+	roas, _ := f.GetROAs()
+	if roas != (Roas{V4v: 1, V4i: 1, V4u: 2, V6v: 1, V6i: 1}) {
+		t.Errorf("Unexpected ROAs %+v", roas)
+	}
+
+	large, _ := f.GetLargeCommunities()
+	if large != (Large{V4: 1}) {
+		t.Errorf("Unexpected large communities %+v", large)
+	}
+
+	// 2001:db8::/32 is invalid too, but ends in an AS set so has no origin to list it under
+	invalids, _ := f.GetInvalids()
+	expected := map[string][]string{"64511": {"192.0.2.0/24"}}
+	if !reflect.DeepEqual(invalids, expected) {
+		t.Errorf("Expected invalids %v, got %v", expected, invalids)
+	}
+
+	// Longest match wins over the covering /9
+	route, found, _ := f.GetRoute(net.ParseIP("8.8.8.8"))
+	if !found || route.String() != "8.8.8.0/24" {
+		t.Errorf("Unexpected route %v", route)
+	}
+	origin, found, _ := f.GetOriginFromIP(net.ParseIP("8.1.1.1"))
+	if !found || origin != 3356 {
+		t.Errorf("Unexpected origin %d", origin)
+	}
+	if _, found, _ := f.GetASPathFromIP(net.ParseIP("203.0.113.1")); found {
+		t.Error("Expected no route for 203.0.113.1")
+	}
+
+	_, prefix, _ := net.ParseCIDR("1.0.0.0/24")
+	if status, _, _ := f.GetROA(prefix, 64496); status != RInvalid {
+		t.Errorf("Expected invalid, got %d", status)
+	}
+	vrps, _ := f.GetVRPs(13335)
+	if len(vrps) != 2 || vrps[1].Prefix.String() != "2606:4700::/32" || vrps[1].Max != 48 {
+		t.Errorf("Unexpected VRPs %+v", vrps)
+	}
+}
+
+func TestLoadFakeConnYAML(t *testing.T) {
+	j := MustLoadFakeConn(t, "testdata/fakeconn.json")
+	y := MustLoadFakeConn(t, "testdata/fakeconn.yaml")
+	if !reflect.DeepEqual(j.Fixture, y.Fixture) {
+		t.Errorf("Expected the YAML fixture to match the JSON one, got %+v", y.Fixture)
+	}
+}
+
+func TestFakeConnInjection(t *testing.T) {
+	boom := errors.New("boom")
+	f := NewFakeConn(Fixture{})
+	f.Errors = map[string]error{"GetPeers": boom}
+	f.Latency = map[string]time.Duration{"GetBGPTotal": time.Second}
+
+	if _, err := f.GetPeers(); !errors.Is(err, boom) {
+		t.Errorf("Expected injected error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := f.GetBGPTotalContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	f.GetVRPs(64496)
+	calls := f.Calls()
+	want := []FakeCall{
+		{Method: "GetPeers"},
+		{Method: "GetBGPTotal"},
+		{Method: "GetVRPs", Args: []any{uint32(64496)}},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %+v, got %+v", want, calls)
+	}
+
+	f.ResetCalls()
+	if len(f.Calls()) != 0 {
+		t.Error("Expected no calls after ResetCalls")
+	}
+}
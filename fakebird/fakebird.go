@@ -0,0 +1,301 @@
+// Package fakebird provides an in-process BIRD control socket for tests.
+//
+// A Server listens on a Unix socket in a temporary directory, sends the BIRD
+// greeting and answers each command with the Reply returned by its Handler,
+// encoded with reply codes and continuation lines just like BIRD does.
+// Replies can be written slowly, in small pieces, or cut short by closing the
+// connection, to exercise the error paths of a client.
+package fakebird
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGreeting is the greeting sent by a Server unless changed before Start.
+const DefaultGreeting = "0001 BIRD 2.0.8 ready."
+
+// Line is a single reply line with its code.
+type Line struct {
+	Code int
+	Text string
+}
+
+// Reply is the answer to a single command.
+type Reply struct {
+	// Lines are encoded as BIRD would send them. A line with the same code as
+	// the one before it is sent as a continuation line.
+	Lines []Line
+
+	// Raw, if set, is written verbatim instead of Lines
+	Raw string
+
+	// Chunk splits the reply into writes of at most this many bytes
+	Chunk int
+
+	// Delay pauses before each write
+	Delay time.Duration
+
+	// Hang sends nothing at all, leaving the client waiting
+	Hang bool
+
+	// Close drops the connection once the reply has been written
+	Close bool
+}
+
+// Handler returns the reply to a command.
+type Handler func(command string) Reply
+
+// Lines returns a reply holding every line of text under code, followed by the final 0000.
+func Lines(code int, text string) Reply {
+	var r Reply
+	for _, line := range strings.Split(text, "\n") {
+		r.Lines = append(r.Lines, Line{Code: code, Text: line})
+	}
+	r.Lines = append(r.Lines, Line{Code: 0})
+	return r
+}
+
+// Error returns an error reply, such as 8001 "Network not in table" or 9001 "syntax error".
+func Error(code int, message string) Reply {
+	return Reply{Lines: []Line{{Code: code, Text: message}}}
+}
+
+// Raw returns a reply written exactly as given.
+func Raw(data string) Reply {
+	return Reply{Raw: data}
+}
+
+// Canned returns a Handler answering from replies, keyed by command.
+// Unknown commands get a syntax error.
+func Canned(replies map[string]Reply) Handler {
+	return func(command string) Reply {
+		if r, ok := replies[command]; ok {
+			return r
+		}
+		return Error(9001, "syntax error, unexpected CF_SYM_UNDEFINED")
+	}
+}
+
+// Replay returns a Handler serving raw replies, keyed by command, such as those
+// recorded in a transcript. A command with several replies gets each in turn,
+// then the last one again. Unknown commands get a syntax error.
+// A reply that is empty or cut short, as recorded for an exchange that failed,
+// is followed by closing the connection rather than leaving the client waiting.
+func Replay(replies map[string][]string) Handler {
+	var mu sync.Mutex
+	served := make(map[string]int)
+	return func(command string) Reply {
+		raw, ok := replies[command]
+		if !ok || len(raw) == 0 {
+			return Error(9001, "syntax error, unexpected CF_SYM_UNDEFINED")
+		}
+		mu.Lock()
+		i := min(served[command], len(raw)-1)
+		served[command]++
+		mu.Unlock()
+		r := Raw(raw[i])
+		r.Close = !complete(raw[i])
+		return r
+	}
+}
+
+// complete reports whether raw ends with the final line of a reply, such as
+// "0000 " or "8001 Network not in table".
+func complete(raw string) bool {
+	raw = strings.TrimSuffix(raw, "\n")
+	line := raw[strings.LastIndexByte(raw, '\n')+1:]
+	if len(line) < 5 || line[4] != ' ' {
+		return false
+	}
+	for _, c := range line[:4] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode returns the reply as sent on the wire.
+func (r Reply) Encode() string {
+	if r.Raw != "" {
+		return r.Raw
+	}
+	var b strings.Builder
+	for i, l := range r.Lines {
+		switch {
+		case i > 0 && l.Code == r.Lines[i-1].Code:
+			fmt.Fprintf(&b, " %s\n", l.Text)
+		case i == len(r.Lines)-1:
+			fmt.Fprintf(&b, "%04d %s\n", l.Code, l.Text)
+		default:
+			fmt.Fprintf(&b, "%04d-%s\n", l.Code, l.Text)
+		}
+	}
+	return b.String()
+}
+
+// Server is a fake BIRD control socket.
+type Server struct {
+	// Path is the socket path, set by Start
+	Path string
+
+	// Greeting is sent to every new connection
+	Greeting string
+
+	Handler Handler
+
+	dir string
+	ln  net.Listener
+	wg  sync.WaitGroup
+
+	mu       sync.Mutex
+	closed   bool
+	conns    map[net.Conn]struct{}
+	accepted int
+	commands []string
+}
+
+// NewServer starts and returns a Server answering with h.
+// The caller should call Close when finished, to shut it down.
+func NewServer(h Handler) *Server {
+	s := NewUnstartedServer(h)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a Server that is not yet listening, so its
+// Greeting can be changed. The caller should call Start, then Close.
+func NewUnstartedServer(h Handler) *Server {
+	return &Server{Greeting: DefaultGreeting, Handler: h}
+}
+
+// Start listens on a socket in a new temporary directory.
+// The directory is kept short, as Unix socket paths are limited in length.
+func (s *Server) Start() {
+	if s.ln != nil {
+		panic("fakebird: server already started")
+	}
+	dir, err := os.MkdirTemp("", "fakebird")
+	if err != nil {
+		panic(fmt.Sprintf("fakebird: failed to create socket directory: %v", err))
+	}
+	s.dir = dir
+	s.Path = filepath.Join(dir, "bird.ctl")
+	s.ln, err = net.Listen("unix", s.Path)
+	if err != nil {
+		os.RemoveAll(dir)
+		panic(fmt.Sprintf("fakebird: failed to listen on %s: %v", s.Path, err))
+	}
+	s.conns = make(map[net.Conn]struct{})
+
+	s.wg.Add(1)
+	go s.serve()
+}
+
+// Close stops the server, drops every open connection and removes the socket.
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.ln.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	os.RemoveAll(s.dir)
+}
+
+// Commands returns every command received so far, in order.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// Accepted returns the number of connections accepted so far.
+func (s *Server) Accepted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			// Accepted while Close ran, after it dropped the open connections
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.accepted++
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// handle serves a single connection until the client goes away or a reply closes it.
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	if _, err := fmt.Fprintf(conn, "%s\n", s.Greeting); err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		command := strings.TrimSpace(scanner.Text())
+		s.mu.Lock()
+		s.commands = append(s.commands, command)
+		s.mu.Unlock()
+
+		r := s.Handler(command)
+		if r.Hang {
+			continue
+		}
+		if err := r.write(conn); err != nil || r.Close {
+			return
+		}
+	}
+}
+
+// write sends the encoded reply, split and delayed as requested.
+func (r Reply) write(conn net.Conn) error {
+	data := r.Encode()
+	chunk := r.Chunk
+	if chunk <= 0 {
+		chunk = len(data)
+	}
+	for len(data) > 0 {
+		n := min(chunk, len(data))
+		if r.Delay > 0 {
+			time.Sleep(r.Delay)
+		}
+		if _, err := conn.Write([]byte(data[:n])); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package fakebird
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	r := Reply{Lines: []Line{
+		{Code: 1000, Text: "BIRD 2.0.8"},
+		{Code: 1011, Text: "Router ID is 192.0.2.1"},
+		{Code: 1011, Text: "Hostname is bird"},
+		{Code: 13, Text: "Daemon is up and running"},
+	}}
+	want := "1000-BIRD 2.0.8\n1011-Router ID is 192.0.2.1\n Hostname is bird\n0013 Daemon is up and running\n"
+	if got := r.Encode(); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+
+	if got := Lines(1007, "a\nb").Encode(); got != "1007-a\n b\n0000 \n" {
+		t.Errorf("Lines encoded as %q", got)
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer(Canned(map[string]Reply{
+		"show status": {Lines: []Line{{Code: 13, Text: "Daemon is up and running"}}, Chunk: 3},
+	}))
+	defer s.Close()
+
+	conn, err := net.Dial("unix", s.Path)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for _, tt := range []struct{ command, want string }{
+		{"", DefaultGreeting + "\n"},
+		{"show status", "0013 Daemon is up and running\n"},
+		{"show nonsense", "9001 syntax error, unexpected CF_SYM_UNDEFINED\n"},
+	} {
+		if tt.command != "" {
+			conn.Write([]byte(tt.command + "\r\n"))
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil || line != tt.want {
+			t.Errorf("%q: got %q, %v; want %q", tt.command, line, err, tt.want)
+		}
+	}
+
+	if got := s.Commands(); len(got) != 2 || got[0] != "show status" {
+		t.Errorf("Unexpected commands %q", got)
+	}
+	if s.Accepted() != 1 {
+		t.Errorf("Expected 1 connection, got %d", s.Accepted())
+	}
+}
+
+func TestReplayCutShort(t *testing.T) {
+	s := NewServer(Replay(map[string][]string{
+		"show status":    {"0013 Daemon is up and running\n"},
+		"show route":     {"1007-8.8.8.0/24 unicast\n"},
+		"show protocols": {""},
+	}))
+	defer s.Close()
+
+	for _, tt := range []struct {
+		command string
+		want    []string
+		closed  bool
+	}{
+		{"show status", []string{"0013 Daemon is up and running\n"}, false},
+		{"show route", []string{"1007-8.8.8.0/24 unicast\n"}, true},
+		{"show protocols", nil, true},
+	} {
+		conn, err := net.Dial("unix", s.Path)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		reader := bufio.NewReader(conn)
+		reader.ReadString('\n') // greeting
+		conn.Write([]byte(tt.command + "\n"))
+		for _, want := range tt.want {
+			if line, err := reader.ReadString('\n'); err != nil || line != want {
+				t.Errorf("%q: got %q, %v; want %q", tt.command, line, err, want)
+			}
+		}
+		if tt.closed {
+			if line, err := reader.ReadString('\n'); err != io.EOF {
+				t.Errorf("%q: got %q, %v; want the connection closed", tt.command, line, err)
+			}
+		}
+		conn.Close()
+	}
+}
@@ -0,0 +1,490 @@
+package clidecode
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FRRConn represents a connection to an FRRouting bgpd.
+// Queries go through vtysh -c '... json', run by Runner, which defaults to ExecRunner.
+// Args holds extra vtysh flags, such as --vty_socket or -N for a namespace.
+type FRRConn struct {
+	Binary         string
+	Args           []string
+	Runner         CommandRunner
+	Timeout        time.Duration
+	PeerClassifier PeerClassifier
+}
+
+var _ Decoder = (*FRRConn)(nil)
+var _ DecoderContext = (*FRRConn)(nil)
+
+// NewFRRConn creates a new FRRConn using the local vtysh
+func NewFRRConn() *FRRConn {
+	return &FRRConn{Binary: "vtysh"}
+}
+
+// frrSummary is the output of show bgp <afi> unicast summary json.
+type frrSummary struct {
+	AS       uint32 `json:"as"`
+	RIBCount uint32 `json:"ribCount"`
+	Peers    map[string]struct {
+		RemoteAS uint32 `json:"remoteAs"`
+		LocalAS  uint32 `json:"localAs"`
+		State    string `json:"state"`
+		Desc     string `json:"desc"`
+		Hostname string `json:"hostname"`
+		PfxRcd   uint32 `json:"pfxRcd"`
+		PfxSnt   uint32 `json:"pfxSnt"`
+	} `json:"peers"`
+}
+
+// frrTable is the output of show bgp <afi> unicast json.
+type frrTable struct {
+	Routes map[string][]frrTablePath `json:"routes"`
+}
+
+// frrTablePath is a path in the table listing, where the AS path is a plain string.
+type frrTablePath struct {
+	Valid    bool   `json:"valid"`
+	BestPath bool   `json:"bestpath"`
+	Network  string `json:"network"`
+	Path     string `json:"path"`
+}
+
+// frrLookup is the output of show bgp <afi> unicast <address> json.
+type frrLookup struct {
+	Prefix string `json:"prefix"`
+	Paths  []struct {
+		ASPath struct {
+			String string `json:"string"`
+		} `json:"aspath"`
+		BestPath *struct {
+			Overall bool `json:"overall"`
+		} `json:"bestpath"`
+	} `json:"paths"`
+}
+
+// frrPrefixTable is the output of show rpki prefix-table json.
+type frrPrefixTable struct {
+	Prefixes []struct {
+		Prefix       string `json:"prefix"`
+		PrefixLenMin int    `json:"prefixLenMin"`
+		PrefixLenMax int    `json:"prefixLenMax"`
+		ASN          uint32 `json:"asn"`
+	} `json:"prefixes"`
+}
+
+// run runs a vtysh command and decodes its JSON output into v.
+func (f *FRRConn) run(ctx context.Context, v any, command string) error {
+	binary := f.Binary
+	if binary == "" {
+		binary = "vtysh"
+	}
+	args := append(append([]string{}, f.Args...), "-c", command)
+	return runJSON(ctx, f.Runner, f.Timeout, v, binary, args...)
+}
+
+// frrAFI returns the FRR name of an address family.
+func frrAFI(v4 bool) string {
+	if v4 {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// frrASPath splits an FRR AS path such as "64496 13335 {64510,64511}".
+func frrASPath(s string) ([]uint32, []uint32) {
+	return decodeASPaths(strings.ReplaceAll(s, ",", " "))
+}
+
+// table loads the paths of one address family, with the best path of each prefix first.
+func (f *FRRConn) table(ctx context.Context, command string) ([]ribRoute, error) {
+	var t frrTable
+	if err := f.run(ctx, &t, command); err != nil {
+		return nil, err
+	}
+	return t.ribRoutes()
+}
+
+// ribRoutes converts the table to ribRoutes, sorted by prefix with the best path first.
+func (t frrTable) ribRoutes() ([]ribRoute, error) {
+	prefixes := make([]string, 0, len(t.Routes))
+	for p := range t.Routes {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+
+	var routes []ribRoute
+	for _, p := range prefixes {
+		paths := t.Routes[p]
+		sort.SliceStable(paths, func(i, j int) bool { return paths[i].BestPath && !paths[j].BestPath })
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix in vtysh output: %w", err)
+		}
+		for _, path := range paths {
+			asPath, asSet := frrASPath(path.Path)
+			routes = append(routes, ribRoute{prefix: prefix, asPath: asPath, asSet: asSet})
+		}
+	}
+	return routes, nil
+}
+
+// rib loads both address families, and the RPKI prefix table if withVRPs is set.
+func (f *FRRConn) rib(ctx context.Context, withVRPs bool) (*rib, error) {
+	t := &rib{}
+	for _, v4 := range []bool{true, false} {
+		routes, err := f.table(ctx, fmt.Sprintf("show bgp %s unicast json", frrAFI(v4)))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range routes {
+			t.add(r)
+		}
+	}
+	if withVRPs {
+		vrps, err := f.vrps(ctx)
+		if err != nil {
+			return nil, err
+		}
+		t.validator = NewValidator(vrps)
+	}
+	return t, nil
+}
+
+// vrps returns the RPKI prefix table.
+func (f *FRRConn) vrps(ctx context.Context) ([]ROA, error) {
+	var pt frrPrefixTable
+	if err := f.run(ctx, &pt, "show rpki prefix-table json"); err != nil {
+		return nil, err
+	}
+	vrps := make([]ROA, 0, len(pt.Prefixes))
+	for _, p := range pt.Prefixes {
+		addr, err := netip.ParseAddr(p.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPKI prefix in vtysh output: %w", err)
+		}
+		vrps = append(vrps, ROA{
+			Prefix:    netip.PrefixFrom(addr, p.PrefixLenMin).Masked(),
+			MaxLength: p.PrefixLenMax,
+			ASN:       p.ASN,
+		})
+	}
+	return vrps, nil
+}
+
+// lookup returns the best route covering ip, as chosen by bgpd.
+func (f *FRRConn) lookup(ctx context.Context, ip net.IP) (ribRoute, bool, error) {
+	var l frrLookup
+	if err := f.run(ctx, &l, fmt.Sprintf("show bgp %s unicast %s json", frrAFI(ip.To4() != nil), ip)); err != nil {
+		return ribRoute{}, false, err
+	}
+	if l.Prefix == "" || len(l.Paths) == 0 {
+		// Network not in table
+		return ribRoute{}, false, nil
+	}
+	prefix, err := netip.ParsePrefix(l.Prefix)
+	if err != nil {
+		return ribRoute{}, false, fmt.Errorf("invalid prefix in vtysh output: %w", err)
+	}
+
+	best := l.Paths[0]
+	for _, p := range l.Paths {
+		if p.BestPath != nil && p.BestPath.Overall {
+			best = p
+			break
+		}
+	}
+	asPath, asSet := frrASPath(best.ASPath.String)
+	return ribRoute{prefix: prefix, asPath: asPath, asSet: asSet}, true, nil
+}
+
+// GetBGPSessions returns every BGP neighbor, with a channel for each address family it is configured for
+func (f *FRRConn) GetBGPSessions() ([]BGPSession, error) {
+	return f.GetBGPSessionsContext(context.Background())
+}
+
+// GetBGPSessionsContext is like GetBGPSessions but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetBGPSessionsContext(ctx context.Context) ([]BGPSession, error) {
+	var sessions []BGPSession
+	index := make(map[string]int)
+
+	for _, v4 := range []bool{true, false} {
+		var s frrSummary
+		if err := f.run(ctx, &s, fmt.Sprintf("show bgp %s unicast summary json", frrAFI(v4))); err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(s.Peers))
+		for name := range s.Peers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			p := s.Peers[name]
+			i, ok := index[name]
+			if !ok {
+				session := BGPSession{
+					Name:            name,
+					Description:     p.Desc,
+					BGPState:        p.State,
+					NeighborAddress: net.ParseIP(name),
+					NeighborAS:      p.RemoteAS,
+					LocalAS:         p.LocalAS,
+					State:           "down",
+				}
+				if session.LocalAS == 0 {
+					session.LocalAS = s.AS
+				}
+				if session.Established() {
+					session.State = "up"
+				}
+				i = len(sessions)
+				index[name] = i
+				sessions = append(sessions, session)
+			}
+
+			c := BGPChannel{Name: frrAFI(v4), State: "DOWN", Imported: p.PfxRcd, Exported: p.PfxSnt}
+			if p.State == "Established" {
+				c.State = "UP"
+			}
+			sessions[i].Channels = append(sessions[i].Channels, c)
+		}
+	}
+	return sessions, nil
+}
+
+// GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
+func (f *FRRConn) GetBGPTotal() (Totals, error) {
+	return f.GetBGPTotalContext(context.Background())
+}
+
+// GetBGPTotalContext is like GetBGPTotal but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetBGPTotalContext(ctx context.Context) (Totals, error) {
+	// The summary counts the prefixes in the table, and the prefixes each peer
+	// sent, which are the paths, without listing the table itself
+	var t Totals
+	for _, v4 := range []bool{true, false} {
+		var s frrSummary
+		if err := f.run(ctx, &s, fmt.Sprintf("show bgp %s unicast summary json", frrAFI(v4))); err != nil {
+			return t, err
+		}
+		var paths uint32
+		for _, p := range s.Peers {
+			paths += p.PfxRcd
+		}
+		if v4 {
+			t.V4Rib, t.V4Fib = paths, s.RIBCount
+		} else {
+			t.V6Rib, t.V6Fib = paths, s.RIBCount
+		}
+	}
+	return t, nil
+}
+
+// GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
+func (f *FRRConn) GetPeers() (Peers, error) {
+	return f.GetPeersContext(context.Background())
+}
+
+// GetPeersContext is like GetPeers but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetPeersContext(ctx context.Context) (Peers, error) {
+	sessions, err := f.GetBGPSessionsContext(ctx)
+	if err != nil {
+		return Peers{}, err
+	}
+	classify := f.PeerClassifier
+	if classify == nil {
+		classify = BGPSession.Families
+	}
+	return countPeers(sessions, classify), nil
+}
+
+// GetTotalSourceASNs returns total amount of unique ASNs
+func (f *FRRConn) GetTotalSourceASNs() (ASNs, error) {
+	return f.GetTotalSourceASNsContext(context.Background())
+}
+
+// GetTotalSourceASNsContext is like GetTotalSourceASNs but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetTotalSourceASNsContext(ctx context.Context) (ASNs, error) {
+	t, err := f.rib(ctx, false)
+	if err != nil {
+		return ASNs{}, err
+	}
+	return t.sourceASNs(), nil
+}
+
+// GetMasks returns the total count of each mask value
+// First item is IPv4, second item is IPv6
+func (f *FRRConn) GetMasks() ([]map[string]uint32, error) {
+	return f.GetMasksContext(context.Background())
+}
+
+// GetMasksContext is like GetMasks but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	t, err := f.rib(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return t.masks(), nil
+}
+
+// GetROAs returns total amount of all ROA states
+func (f *FRRConn) GetROAs() (Roas, error) {
+	return f.GetROAsContext(context.Background())
+}
+
+// GetROAsContext is like GetROAs but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetROAsContext(ctx context.Context) (Roas, error) {
+	t, err := f.rib(ctx, true)
+	if err != nil {
+		return Roas{}, err
+	}
+	return t.roas(), nil
+}
+
+// GetLargeCommunities returns the amount of prefixes that have large communities attached (RFC8092)
+func (f *FRRConn) GetLargeCommunities() (Large, error) {
+	return f.GetLargeCommunitiesContext(context.Background())
+}
+
+// GetLargeCommunitiesContext is like GetLargeCommunities but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetLargeCommunitiesContext(ctx context.Context) (Large, error) {
+	t := &rib{}
+	for _, v4 := range []bool{true, false} {
+		routes, err := f.table(ctx, fmt.Sprintf("show bgp %s unicast large-community json", frrAFI(v4)))
+		if err != nil {
+			return Large{}, err
+		}
+		for _, r := range routes {
+			r.large = true
+			t.add(r)
+		}
+	}
+	return t.large(), nil
+}
+
+// GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN.
+func (f *FRRConn) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
+	return f.GetIPv4FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv4FromSourceContext is like GetIPv4FromSource but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	return f.fromSource(ctx, asn, true)
+}
+
+// GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN.
+func (f *FRRConn) GetIPv6FromSource(asn uint32) ([]*net.IPNet, error) {
+	return f.GetIPv6FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv6FromSourceContext is like GetIPv6FromSource but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	return f.fromSource(ctx, asn, false)
+}
+
+// fromSource asks bgpd for the routes whose AS path ends in asn.
+func (f *FRRConn) fromSource(ctx context.Context, asn uint32, v4 bool) ([]*net.IPNet, error) {
+	routes, err := f.table(ctx, fmt.Sprintf("show bgp %s unicast regexp _%d$ json", frrAFI(v4), asn))
+	if err != nil {
+		return nil, err
+	}
+	return newRIB(routes).fromSource(asn, v4), nil
+}
+
+// GetOriginFromIP will return the origin ASN from a source IP.
+func (f *FRRConn) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
+	return f.GetOriginFromIPContext(context.Background(), ip)
+}
+
+// GetOriginFromIPContext is like GetOriginFromIP but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	r, found, err := f.lookup(ctx, ip)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	return r.origin(), true, nil
+}
+
+// GetASPathFromIP will return the AS path, as well as as-set if any from a source IP.
+func (f *FRRConn) GetASPathFromIP(ip net.IP) (ASPath, bool, error) {
+	return f.GetASPathFromIPContext(context.Background(), ip)
+}
+
+// GetASPathFromIPContext is like GetASPathFromIP but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetASPathFromIPContext(ctx context.Context, ip net.IP) (ASPath, bool, error) {
+	r, found, err := f.lookup(ctx, ip)
+	if err != nil || !found {
+		return ASPath{}, false, err
+	}
+	return ASPath{Path: r.asPath, Set: r.asSet}, true, nil
+}
+
+// GetRoute will return the current FIB entry, if any, from a source IP.
+func (f *FRRConn) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
+	return f.GetRouteContext(context.Background(), ip)
+}
+
+// GetRouteContext is like GetRoute but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	r, found, err := f.lookup(ctx, ip)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return IPNetFromPrefix(r.prefix), true, nil
+}
+
+// GetROA will return the ROA status, if any, from a source IP and ASN.
+func (f *FRRConn) GetROA(prefix *net.IPNet, asn uint32) (int, bool, error) {
+	return f.GetROAContext(context.Background(), prefix, asn)
+}
+
+// GetROAContext is like GetROA but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	p, ok := PrefixFromIPNet(prefix)
+	if !ok {
+		return 0, false, nil
+	}
+	vrps, err := f.vrps(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	return NewValidator(vrps).Validate(p, asn), true, nil
+}
+
+// GetVRPs will return all Validated ROA Payloads for an ASN.
+func (f *FRRConn) GetVRPs(asn uint32) ([]VRP, error) {
+	return f.GetVRPsContext(context.Background(), asn)
+}
+
+// GetVRPsContext is like GetVRPs but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetVRPsContext(ctx context.Context, asn uint32) ([]VRP, error) {
+	vrps, err := f.vrps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vrpsFor(NewValidator(vrps), asn), nil
+}
+
+// GetInvalids returns a map of ASNs that are advertising RPKI invalid prefixes.
+// It also includes all those prefixes being advertised.
+func (f *FRRConn) GetInvalids() (map[string][]string, error) {
+	return f.GetInvalidsContext(context.Background())
+}
+
+// GetInvalidsContext is like GetInvalids but honours the cancellation and deadline of ctx
+func (f *FRRConn) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	t, err := f.rib(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return t.invalids(), nil
+}
@@ -0,0 +1,126 @@
+package clidecode
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fileRunner returns a CommandRunner answering each command line with the contents of a file.
+func fileRunner(files map[string]string) CommandRunner {
+	return func(_ context.Context, name string, args ...string) ([]byte, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		if path, ok := files[command]; ok {
+			return os.ReadFile(path)
+		}
+		return nil, fmt.Errorf("unexpected command: %s", command)
+	}
+}
+
+func TestFRRConn(t *testing.T) {
+	f := NewFRRConn()
+	f.Runner = fileRunner(map[string]string{
+		"vtysh -c show bgp ipv4 unicast summary json":         "testdata/frr/summary_ipv4.json",
+		"vtysh -c show bgp ipv6 unicast summary json":         "testdata/frr/summary_ipv6.json",
+		"vtysh -c show bgp ipv4 unicast json":                 "testdata/frr/table_ipv4.json",
+		"vtysh -c show bgp ipv6 unicast json":                 "testdata/frr/table_ipv6.json",
+		"vtysh -c show bgp ipv4 unicast large-community json": "testdata/frr/large_ipv4.json",
+		"vtysh -c show bgp ipv6 unicast large-community json": "testdata/frr/large_ipv6.json",
+		"vtysh -c show bgp ipv4 unicast regexp _15169$ json":  "testdata/frr/regexp_15169_ipv4.json",
+		"vtysh -c show bgp ipv4 unicast 8.8.8.8 json":         "testdata/frr/lookup_8.8.8.8.json",
+		"vtysh -c show bgp ipv4 unicast 203.0.113.1 json":     "testdata/frr/lookup_203.0.113.1.json",
+		"vtysh -c show rpki prefix-table json":                "testdata/frr/rpki_prefix_table.json",
+	})
+
+	totals, err := f.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if totals != (Totals{V4Rib: 4, V4Fib: 3, V6Rib: 2, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+
+	// 192.0.2.1 carries both families over one session
+	peers, err := f.GetPeers()
+	if err != nil {
+		t.Fatalf("GetPeers failed: %v", err)
+	}
+	if peers != (Peers{V4c: 2, V4e: 1, V6c: 2, V6e: 1}) {
+		t.Errorf("Unexpected peers %+v", peers)
+	}
+	sessions, _ := f.GetBGPSessions()
+	if len(sessions) != 3 || len(sessions[0].Channels) != 2 || sessions[0].Description != "transit" {
+		t.Errorf("Unexpected sessions %+v", sessions)
+	}
+
+	asns, _ := f.GetTotalSourceASNs()
+	if asns != (ASNs{As4: 3, As6: 1, As10: 3, As4Only: 2, AsBoth: 1}) {
+		t.Errorf("Unexpected ASNs %+v", asns)
+	}
+
+	masks, _ := f.GetMasks()
+	if !reflect.DeepEqual(masks, []map[string]uint32{{"24": 3}, {"32": 2}}) {
+		t.Errorf("Unexpected masks %v", masks)
+	}
+
+	roas, err := f.GetROAs()
+	if err != nil {
+		t.Fatalf("GetROAs failed: %v", err)
+	}
+	if roas != (Roas{V4v: 1, V4i: 1, V4u: 1, V6v: 1, V6i: 1}) {
+		t.Errorf("Unexpected ROAs %+v", roas)
+	}
+
+	large, _ := f.GetLargeCommunities()
+	if large != (Large{V4: 1}) {
+		t.Errorf("Unexpected large communities %+v", large)
+	}
+
+	// 2001:db8::/32 is invalid too, but ends in an AS set so has no origin to list it under
+	invalids, _ := f.GetInvalids()
+	expected := map[string][]string{"64511": {"192.0.2.0/24"}}
+	if !reflect.DeepEqual(invalids, expected) {
+		t.Errorf("Expected invalids %v, got %v", expected, invalids)
+	}
+
+	nets, _ := f.GetIPv4FromSource(15169)
+	if len(nets) != 1 || nets[0].String() != "8.8.8.0/24" {
+		t.Errorf("Unexpected networks %v", nets)
+	}
+
+	origin, found, err := f.GetOriginFromIP(net.ParseIP("8.8.8.8"))
+	if err != nil || !found || origin != 15169 {
+		t.Errorf("GetOriginFromIP returned %d, %v, %v", origin, found, err)
+	}
+	if _, found, err := f.GetRoute(net.ParseIP("203.0.113.1")); err != nil || found {
+		t.Errorf("Expected not found, got %v, %v", found, err)
+	}
+
+	_, prefix, _ := net.ParseCIDR("1.0.0.0/24")
+	if status, _, _ := f.GetROA(prefix, 64496); status != RInvalid {
+		t.Errorf("Expected invalid, got %d", status)
+	}
+	vrps, _ := f.GetVRPs(13335)
+	if len(vrps) != 2 {
+		t.Errorf("Unexpected VRPs %+v", vrps)
+	}
+}
+
+func TestFRRConnTotalsFromSummary(t *testing.T) {
+	f := NewFRRConn()
+	f.Runner = fileRunner(map[string]string{
+		"vtysh -c show bgp ipv4 unicast summary json": "testdata/frr/summary_ipv4.json",
+		"vtysh -c show bgp ipv6 unicast summary json": "testdata/frr/summary_ipv6.json",
+	})
+	totals, err := f.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if totals != (Totals{V4Rib: 4, V4Fib: 3, V6Rib: 2, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+}
@@ -0,0 +1,551 @@
+package clidecode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mellowdrifter/clidecode/internal/gobgpapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// GoBGPConn represents a connection to the gRPC API of a GoBGP daemon.
+// Paths are streamed from ListPath straight into a rib. The API messages come
+// from internal/gobgpapi, which shares their wire format, so neither GoBGP's
+// packages nor the gobgp CLI are needed.
+// Timeout bounds calls whose context has no deadline, defaulting to DefaultTimeout.
+type GoBGPConn struct {
+	Conn           grpc.ClientConnInterface
+	Timeout        time.Duration
+	PeerClassifier PeerClassifier
+}
+
+var _ Decoder = (*GoBGPConn)(nil)
+var _ DecoderContext = (*GoBGPConn)(nil)
+
+// DefaultGoBGPPort is the port gobgpd serves its gRPC API on
+const DefaultGoBGPPort = 50051
+
+// NewGoBGPConn creates a new GoBGPConn over conn, a connection to gobgpd
+func NewGoBGPConn(conn grpc.ClientConnInterface) *GoBGPConn {
+	return &GoBGPConn{Conn: conn}
+}
+
+// DialGoBGP creates a new GoBGPConn for the gobgpd gRPC API at host:port.
+// Like the gobgp CLI, it connects without TLS; use NewGoBGPConn for anything
+// else. The connection is made on the first call.
+func DialGoBGP(host string, port int) (*GoBGPConn, error) {
+	conn, err := grpc.NewClient(net.JoinHostPort(host, strconv.Itoa(port)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return NewGoBGPConn(conn), nil
+}
+
+// Close closes the connection, if it can be closed
+func (g *GoBGPConn) Close() error {
+	if c, ok := g.Conn.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// gobgpService is the full name of the GoBGP API service
+const gobgpService = "apipb.GobgpApi"
+
+// gobgpSessionStates maps the session_state enum of the GoBGP API to BGP FSM state names.
+var gobgpSessionStates = map[gobgpapi.PeerState_SessionState]string{
+	gobgpapi.PeerState_IDLE:        "Idle",
+	gobgpapi.PeerState_CONNECT:     "Connect",
+	gobgpapi.PeerState_ACTIVE:      "Active",
+	gobgpapi.PeerState_OPENSENT:    "OpenSent",
+	gobgpapi.PeerState_OPENCONFIRM: "OpenConfirm",
+	gobgpapi.PeerState_ESTABLISHED: "Established",
+}
+
+// context bounds ctx by Timeout, or DefaultTimeout, if it has no deadline
+func (g *GoBGPConn) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// call calls a unary method of the GoBGP API
+func (g *GoBGPConn) call(ctx context.Context, method string, req, reply proto.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx, cancel := g.context(ctx)
+	defer cancel()
+	if err := g.Conn.Invoke(ctx, "/"+gobgpService+"/"+method, req, reply); err != nil {
+		return fmt.Errorf("gobgp %s: %w", method, err)
+	}
+	return nil
+}
+
+// gobgpStream calls a server streaming method of the GoBGP API, handing each
+// reply to each as it arrives
+func gobgpStream[Reply any](ctx context.Context, g *GoBGPConn, method string, req proto.Message, each func(*Reply) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx, cancel := g.context(ctx)
+	defer cancel()
+	desc := &grpc.StreamDesc{StreamName: method, ServerStreams: true}
+	s, err := g.Conn.NewStream(ctx, desc, "/"+gobgpService+"/"+method)
+	if err == nil {
+		err = s.SendMsg(req)
+	}
+	if err == nil {
+		err = s.CloseSend()
+	}
+	for err == nil {
+		reply := new(Reply)
+		if err = s.RecvMsg(reply); err == nil {
+			if err := each(reply); err != nil {
+				return err
+			}
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return fmt.Errorf("gobgp %s: %w", method, err)
+}
+
+// gobgpFamily returns the IPv4 or IPv6 unicast family
+func gobgpFamily(v4 bool) *gobgpapi.Family {
+	if v4 {
+		return &gobgpapi.Family{Afi: gobgpapi.Family_AFI_IP, Safi: gobgpapi.Family_SAFI_UNICAST}
+	}
+	return &gobgpapi.Family{Afi: gobgpapi.Family_AFI_IP6, Safi: gobgpapi.Family_SAFI_UNICAST}
+}
+
+// addRoutes streams the paths of the global table of one address family into
+// t, the best path of each prefix first. If prefixes are set, only the
+// destinations they select are listed.
+func (g *GoBGPConn) addRoutes(ctx context.Context, t *rib, v4 bool, prefixes ...*gobgpapi.TableLookupPrefix) error {
+	req := &gobgpapi.ListPathRequest{
+		TableType: gobgpapi.TableType_GLOBAL,
+		Family:    gobgpFamily(v4),
+		Prefixes:  prefixes,
+	}
+	return gobgpStream(ctx, g, "ListPath", req, func(r *gobgpapi.ListPathResponse) error {
+		d := r.GetDestination()
+		prefix, err := netip.ParsePrefix(d.GetPrefix())
+		if err != nil {
+			return fmt.Errorf("invalid prefix from gobgp: %w", err)
+		}
+		paths := d.GetPaths()
+		sort.SliceStable(paths, func(i, j int) bool { return paths[i].GetBest() && !paths[j].GetBest() })
+		for _, p := range paths {
+			r, err := gobgpRoute(prefix, p)
+			if err != nil {
+				return err
+			}
+			t.add(r)
+		}
+		return nil
+	})
+}
+
+// gobgpRoute converts a path to a ribRoute. Only the AS_PATH and
+// LARGE_COMMUNITY attributes are read.
+func gobgpRoute(prefix netip.Prefix, p *gobgpapi.Path) (ribRoute, error) {
+	r := ribRoute{prefix: prefix}
+	for _, a := range p.GetPattrs() {
+		switch gobgpAttrName(a) {
+		case "AsPathAttribute":
+			var attr gobgpapi.AsPathAttribute
+			if err := proto.Unmarshal(a.GetValue(), &attr); err != nil {
+				return ribRoute{}, fmt.Errorf("invalid AS path from gobgp: %w", err)
+			}
+			for _, seg := range attr.GetSegments() {
+				r.addSegment(seg.GetType(), seg.GetNumbers())
+			}
+		case "LargeCommunitiesAttribute":
+			var attr gobgpapi.LargeCommunitiesAttribute
+			if err := proto.Unmarshal(a.GetValue(), &attr); err != nil {
+				return ribRoute{}, fmt.Errorf("invalid large communities from gobgp: %w", err)
+			}
+			r.large = len(attr.GetCommunities()) > 0
+		}
+	}
+	return r, nil
+}
+
+// gobgpAttrName returns the name of the message in a, without its package
+func gobgpAttrName(a *anypb.Any) string {
+	name := a.GetTypeUrl()
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
+
+// vrps returns the ROAs known to gobgpd.
+func (g *GoBGPConn) vrps(ctx context.Context) ([]ROA, error) {
+	var vrps []ROA
+	for _, v4 := range []bool{true, false} {
+		req := &gobgpapi.ListRpkiTableRequest{Family: gobgpFamily(v4)}
+		err := gobgpStream(ctx, g, "ListRpkiTable", req, func(r *gobgpapi.ListRpkiTableResponse) error {
+			roa := r.GetRoa()
+			addr, err := netip.ParseAddr(roa.GetPrefix())
+			if err != nil {
+				return fmt.Errorf("invalid ROA prefix from gobgp: %w", err)
+			}
+			vrps = append(vrps, ROA{
+				Prefix:    netip.PrefixFrom(addr, int(roa.GetPrefixlen())).Masked(),
+				MaxLength: int(roa.GetMaxlen()),
+				ASN:       roa.GetAs(),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return vrps, nil
+}
+
+// rib loads the given address families, and the ROAs if withVRPs is set.
+func (g *GoBGPConn) rib(ctx context.Context, withVRPs bool, families ...bool) (*rib, error) {
+	if len(families) == 0 {
+		families = []bool{true, false}
+	}
+	t := &rib{}
+	for _, v4 := range families {
+		if err := g.addRoutes(ctx, t, v4); err != nil {
+			return nil, err
+		}
+	}
+	if withVRPs {
+		vrps, err := g.vrps(ctx)
+		if err != nil {
+			return nil, err
+		}
+		t.validator = NewValidator(vrps)
+	}
+	return t, nil
+}
+
+// lookup returns the best route covering ip, asking gobgpd only for the
+// prefixes covering it.
+func (g *GoBGPConn) lookup(ctx context.Context, ip net.IP) (ribRoute, bool, error) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return ribRoute{}, false, nil
+	}
+	addr = addr.Unmap()
+	host := &gobgpapi.TableLookupPrefix{
+		Prefix: netip.PrefixFrom(addr, addr.BitLen()).String(),
+		Type:   gobgpapi.TableLookupPrefix_SHORTER,
+	}
+	t := &rib{}
+	if err := g.addRoutes(ctx, t, addr.Is4(), host); err != nil {
+		return ribRoute{}, false, err
+	}
+	r, found := t.lookup(ip)
+	return r, found, nil
+}
+
+// GetBGPSessions returns every BGP neighbor with its state and address families
+func (g *GoBGPConn) GetBGPSessions() ([]BGPSession, error) {
+	return g.GetBGPSessionsContext(context.Background())
+}
+
+// GetBGPSessionsContext is like GetBGPSessions but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetBGPSessionsContext(ctx context.Context) ([]BGPSession, error) {
+	var sessions []BGPSession
+	err := gobgpStream(ctx, g, "ListPeer", &gobgpapi.ListPeerRequest{}, func(r *gobgpapi.ListPeerResponse) error {
+		sessions = append(sessions, gobgpSession(r.GetPeer()))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// gobgpSession converts a peer to a BGPSession
+func gobgpSession(p *gobgpapi.Peer) BGPSession {
+	conf, state := p.GetConf(), p.GetState()
+	addr := state.GetNeighborAddress()
+	if addr == "" {
+		addr = conf.GetNeighborAddress()
+	}
+	asn := state.GetPeerAsn()
+	if asn == 0 {
+		asn = conf.GetPeerAsn()
+	}
+	s := BGPSession{
+		Name:            addr,
+		Description:     conf.GetDescription(),
+		BGPState:        gobgpSessionStates[state.GetSessionState()],
+		NeighborAddress: net.ParseIP(addr),
+		NeighborAS:      asn,
+		LocalAS:         conf.GetLocalAsn(),
+		NeighborID:      net.ParseIP(state.GetRouterId()),
+	}
+	s.State = "down"
+	if s.Established() {
+		s.State = "up"
+	}
+	for _, af := range p.GetAfiSafis() {
+		family := af.GetConfig().GetFamily()
+		c := BGPChannel{Name: gobgpChannelName(int(family.GetAfi()), int(family.GetSafi())), State: "DOWN"}
+		if af.GetConfig().GetEnabled() {
+			c.State = "UP"
+		}
+		s.Channels = append(s.Channels, c)
+	}
+	return s
+}
+
+// gobgpChannelName names an AFI/SAFI pair the way BIRD names its channels.
+func gobgpChannelName(afi, safi int) string {
+	name := "afi" + strconv.Itoa(afi)
+	switch afi {
+	case 1:
+		name = "ipv4"
+	case 2:
+		name = "ipv6"
+	}
+	switch safi {
+	case 1:
+		return name
+	case 2:
+		return name + "-mc"
+	case 4:
+		return name + "-mpls"
+	}
+	return name + "-safi" + strconv.Itoa(safi)
+}
+
+// GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
+func (g *GoBGPConn) GetBGPTotal() (Totals, error) {
+	return g.GetBGPTotalContext(context.Background())
+}
+
+// GetBGPTotalContext is like GetBGPTotal but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetBGPTotalContext(ctx context.Context) (Totals, error) {
+	var t Totals
+	for _, v4 := range []bool{true, false} {
+		req := &gobgpapi.GetTableRequest{TableType: gobgpapi.TableType_GLOBAL, Family: gobgpFamily(v4)}
+		var table gobgpapi.GetTableResponse
+		if err := g.call(ctx, "GetTable", req, &table); err != nil {
+			return t, err
+		}
+		if v4 {
+			t.V4Rib, t.V4Fib = uint32(table.GetNumPath()), uint32(table.GetNumDestination())
+		} else {
+			t.V6Rib, t.V6Fib = uint32(table.GetNumPath()), uint32(table.GetNumDestination())
+		}
+	}
+	return t, nil
+}
+
+// GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
+func (g *GoBGPConn) GetPeers() (Peers, error) {
+	return g.GetPeersContext(context.Background())
+}
+
+// GetPeersContext is like GetPeers but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetPeersContext(ctx context.Context) (Peers, error) {
+	sessions, err := g.GetBGPSessionsContext(ctx)
+	if err != nil {
+		return Peers{}, err
+	}
+	classify := g.PeerClassifier
+	if classify == nil {
+		classify = BGPSession.Families
+	}
+	return countPeers(sessions, classify), nil
+}
+
+// GetTotalSourceASNs returns total amount of unique ASNs
+func (g *GoBGPConn) GetTotalSourceASNs() (ASNs, error) {
+	return g.GetTotalSourceASNsContext(context.Background())
+}
+
+// GetTotalSourceASNsContext is like GetTotalSourceASNs but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetTotalSourceASNsContext(ctx context.Context) (ASNs, error) {
+	t, err := g.rib(ctx, false)
+	if err != nil {
+		return ASNs{}, err
+	}
+	return t.sourceASNs(), nil
+}
+
+// GetMasks returns the total count of each mask value
+// First item is IPv4, second item is IPv6
+func (g *GoBGPConn) GetMasks() ([]map[string]uint32, error) {
+	return g.GetMasksContext(context.Background())
+}
+
+// GetMasksContext is like GetMasks but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	t, err := g.rib(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return t.masks(), nil
+}
+
+// GetROAs returns total amount of all ROA states
+func (g *GoBGPConn) GetROAs() (Roas, error) {
+	return g.GetROAsContext(context.Background())
+}
+
+// GetROAsContext is like GetROAs but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetROAsContext(ctx context.Context) (Roas, error) {
+	t, err := g.rib(ctx, true)
+	if err != nil {
+		return Roas{}, err
+	}
+	return t.roas(), nil
+}
+
+// GetLargeCommunities returns the amount of prefixes that have large communities attached (RFC8092)
+func (g *GoBGPConn) GetLargeCommunities() (Large, error) {
+	return g.GetLargeCommunitiesContext(context.Background())
+}
+
+// GetLargeCommunitiesContext is like GetLargeCommunities but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetLargeCommunitiesContext(ctx context.Context) (Large, error) {
+	t, err := g.rib(ctx, false)
+	if err != nil {
+		return Large{}, err
+	}
+	return t.large(), nil
+}
+
+// GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN.
+func (g *GoBGPConn) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
+	return g.GetIPv4FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv4FromSourceContext is like GetIPv4FromSource but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	t, err := g.rib(ctx, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return t.fromSource(asn, true), nil
+}
+
+// GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN.
+func (g *GoBGPConn) GetIPv6FromSource(asn uint32) ([]*net.IPNet, error) {
+	return g.GetIPv6FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv6FromSourceContext is like GetIPv6FromSource but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	t, err := g.rib(ctx, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return t.fromSource(asn, false), nil
+}
+
+// GetOriginFromIP will return the origin ASN from a source IP.
+func (g *GoBGPConn) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
+	return g.GetOriginFromIPContext(context.Background(), ip)
+}
+
+// GetOriginFromIPContext is like GetOriginFromIP but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	r, found, err := g.lookup(ctx, ip)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	return r.origin(), true, nil
+}
+
+// GetASPathFromIP will return the AS path, as well as as-set if any from a source IP.
+func (g *GoBGPConn) GetASPathFromIP(ip net.IP) (ASPath, bool, error) {
+	return g.GetASPathFromIPContext(context.Background(), ip)
+}
+
+// GetASPathFromIPContext is like GetASPathFromIP but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetASPathFromIPContext(ctx context.Context, ip net.IP) (ASPath, bool, error) {
+	r, found, err := g.lookup(ctx, ip)
+	if err != nil || !found {
+		return ASPath{}, false, err
+	}
+	return ASPath{Path: r.asPath, Set: r.asSet}, true, nil
+}
+
+// GetRoute will return the current FIB entry, if any, from a source IP.
+func (g *GoBGPConn) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
+	return g.GetRouteContext(context.Background(), ip)
+}
+
+// GetRouteContext is like GetRoute but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	r, found, err := g.lookup(ctx, ip)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return IPNetFromPrefix(r.prefix), true, nil
+}
+
+// GetROA will return the ROA status, if any, from a source IP and ASN.
+func (g *GoBGPConn) GetROA(prefix *net.IPNet, asn uint32) (int, bool, error) {
+	return g.GetROAContext(context.Background(), prefix, asn)
+}
+
+// GetROAContext is like GetROA but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	p, ok := PrefixFromIPNet(prefix)
+	if !ok {
+		return 0, false, nil
+	}
+	vrps, err := g.vrps(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	return NewValidator(vrps).Validate(p, asn), true, nil
+}
+
+// GetVRPs will return all Validated ROA Payloads for an ASN.
+func (g *GoBGPConn) GetVRPs(asn uint32) ([]VRP, error) {
+	return g.GetVRPsContext(context.Background(), asn)
+}
+
+// GetVRPsContext is like GetVRPs but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetVRPsContext(ctx context.Context, asn uint32) ([]VRP, error) {
+	vrps, err := g.vrps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vrpsFor(NewValidator(vrps), asn), nil
+}
+
+// GetInvalids returns a map of ASNs that are advertising RPKI invalid prefixes.
+// It also includes all those prefixes being advertised.
+func (g *GoBGPConn) GetInvalids() (map[string][]string, error) {
+	return g.GetInvalidsContext(context.Background())
+}
+
+// GetInvalidsContext is like GetInvalids but honours the cancellation and deadline of ctx
+func (g *GoBGPConn) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	t, err := g.rib(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return t.invalids(), nil
+}
@@ -0,0 +1,382 @@
+package clidecode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mellowdrifter/clidecode/internal/gobgpapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// fakeGoBGP answers the GoBGP API methods GoBGPConn calls from fixed tables,
+// as gobgpd would
+type fakeGoBGP struct {
+	paths [2][]*gobgpapi.Destination // IPv4 then IPv6
+	roas  [2][]*gobgpapi.Roa
+	peers []*gobgpapi.Peer
+	err   error // returned by every method if set
+
+	mu       sync.Mutex
+	listPath []*gobgpapi.ListPathRequest
+}
+
+func fakeFamily(f *gobgpapi.Family) int {
+	if f.GetAfi() == gobgpapi.Family_AFI_IP6 {
+		return 1
+	}
+	return 0
+}
+
+func (f *fakeGoBGP) ListPath(req *gobgpapi.ListPathRequest) []*gobgpapi.ListPathResponse {
+	f.mu.Lock()
+	f.listPath = append(f.listPath, req)
+	f.mu.Unlock()
+
+	var replies []*gobgpapi.ListPathResponse
+	for _, d := range f.paths[fakeFamily(req.GetFamily())] {
+		prefix := netip.MustParsePrefix(d.GetPrefix())
+		selected := len(req.GetPrefixes()) == 0
+		for _, lookup := range req.GetPrefixes() {
+			host := netip.MustParsePrefix(lookup.GetPrefix())
+			if lookup.GetType() == gobgpapi.TableLookupPrefix_SHORTER && prefix.Bits() <= host.Bits() && prefix.Contains(host.Addr()) {
+				selected = true
+			}
+		}
+		if selected {
+			replies = append(replies, &gobgpapi.ListPathResponse{Destination: d})
+		}
+	}
+	return replies
+}
+
+func (f *fakeGoBGP) ListPeer(*gobgpapi.ListPeerRequest) []*gobgpapi.ListPeerResponse {
+	var replies []*gobgpapi.ListPeerResponse
+	for _, p := range f.peers {
+		replies = append(replies, &gobgpapi.ListPeerResponse{Peer: p})
+	}
+	return replies
+}
+
+func (f *fakeGoBGP) ListRpkiTable(req *gobgpapi.ListRpkiTableRequest) []*gobgpapi.ListRpkiTableResponse {
+	var replies []*gobgpapi.ListRpkiTableResponse
+	for _, roa := range f.roas[fakeFamily(req.GetFamily())] {
+		replies = append(replies, &gobgpapi.ListRpkiTableResponse{Roa: roa})
+	}
+	return replies
+}
+
+func (f *fakeGoBGP) GetTable(req *gobgpapi.GetTableRequest) *gobgpapi.GetTableResponse {
+	table := &gobgpapi.GetTableResponse{}
+	for _, d := range f.paths[fakeFamily(req.GetFamily())] {
+		table.NumDestination++
+		table.NumPath += uint64(len(d.GetPaths()))
+	}
+	table.NumAccepted = table.NumPath
+	return table
+}
+
+// fakeGoBGPStream describes a server streaming method answered by list
+func fakeGoBGPStream[Req, Reply any](name string, list func(*fakeGoBGP, *Req) []*Reply) grpc.StreamDesc {
+	return grpc.StreamDesc{
+		StreamName:    name,
+		ServerStreams: true,
+		Handler: func(srv any, ss grpc.ServerStream) error {
+			f := srv.(*fakeGoBGP)
+			req := new(Req)
+			if err := ss.RecvMsg(req); err != nil {
+				return err
+			}
+			if f.err != nil {
+				return f.err
+			}
+			for _, reply := range list(f, req) {
+				if err := ss.SendMsg(reply); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+var fakeGoBGPService = grpc.ServiceDesc{
+	ServiceName: gobgpService,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "GetTable",
+		Handler: func(srv any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+			f := srv.(*fakeGoBGP)
+			req := new(gobgpapi.GetTableRequest)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			if f.err != nil {
+				return nil, f.err
+			}
+			return f.GetTable(req), nil
+		},
+	}},
+	Streams: []grpc.StreamDesc{
+		fakeGoBGPStream("ListPath", (*fakeGoBGP).ListPath),
+		fakeGoBGPStream("ListPeer", (*fakeGoBGP).ListPeer),
+		fakeGoBGPStream("ListRpkiTable", (*fakeGoBGP).ListRpkiTable),
+	},
+}
+
+// dialFakeGoBGP serves f over an in-memory listener and returns a GoBGPConn connected to it
+func dialFakeGoBGP(t *testing.T, f *fakeGoBGP) *GoBGPConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	s.RegisterService(&fakeGoBGPService, f)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///gobgpd",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGoBGPConn(conn)
+	t.Cleanup(func() { g.Close() })
+	return g
+}
+
+// gobgpAttr packs an attribute the way gobgpd does, under the apipb package
+func gobgpAttr(t *testing.T, m proto.Message) *anypb.Any {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := string(m.ProtoReflect().Descriptor().Name())
+	return &anypb.Any{TypeUrl: "type.googleapis.com/apipb." + name, Value: b}
+}
+
+// gobgpPath returns a path with an AS_SEQUENCE, an AS_SET if set is given,
+// and a large community if large is set
+func gobgpPath(t *testing.T, best, large bool, seq []uint32, set ...uint32) *gobgpapi.Path {
+	t.Helper()
+	asPath := &gobgpapi.AsPathAttribute{Segments: []*gobgpapi.AsSegment{{Type: 2, Numbers: seq}}}
+	if len(set) > 0 {
+		asPath.Segments = append(asPath.Segments, &gobgpapi.AsSegment{Type: 1, Numbers: set})
+	}
+	p := &gobgpapi.Path{Best: best, Pattrs: []*anypb.Any{gobgpAttr(t, asPath)}}
+	if large {
+		communities := &gobgpapi.LargeCommunitiesAttribute{
+			Communities: []*gobgpapi.LargeCommunity{{GlobalAdmin: 64496, LocalData1: 1, LocalData2: 2}},
+		}
+		p.Pattrs = append(p.Pattrs, gobgpAttr(t, communities))
+	}
+	return p
+}
+
+func newFakeGoBGP(t *testing.T) *fakeGoBGP {
+	unicast := func(afi gobgpapi.Family_Afi) *gobgpapi.AfiSafi {
+		return &gobgpapi.AfiSafi{Config: &gobgpapi.AfiSafiConfig{
+			Family:  &gobgpapi.Family{Afi: afi, Safi: gobgpapi.Family_SAFI_UNICAST},
+			Enabled: true,
+		}}
+	}
+	return &fakeGoBGP{
+		paths: [2][]*gobgpapi.Destination{
+			{
+				{Prefix: "1.0.0.0/24", Paths: []*gobgpapi.Path{
+					gobgpPath(t, false, false, []uint32{64497, 13335}),
+					gobgpPath(t, true, true, []uint32{64496, 13335}),
+				}},
+				{Prefix: "192.0.2.0/24", Paths: []*gobgpapi.Path{gobgpPath(t, true, false, []uint32{64496, 64511})}},
+				{Prefix: "8.8.8.0/24", Paths: []*gobgpapi.Path{gobgpPath(t, true, false, []uint32{64496, 15169})}},
+			},
+			{
+				{Prefix: "2606:4700::/32", Paths: []*gobgpapi.Path{gobgpPath(t, true, false, []uint32{64496, 13335})}},
+				{Prefix: "2001:db8::/32", Paths: []*gobgpapi.Path{gobgpPath(t, true, false, []uint32{64496}, 64510, 64511)}},
+			},
+		},
+		roas: [2][]*gobgpapi.Roa{
+			{
+				{As: 13335, Prefixlen: 24, Maxlen: 24, Prefix: "1.0.0.0"},
+				{As: 64511, Prefixlen: 23, Maxlen: 23, Prefix: "192.0.2.0"},
+			},
+			{
+				{As: 13335, Prefixlen: 32, Maxlen: 48, Prefix: "2606:4700::"},
+				{As: 64496, Prefixlen: 32, Maxlen: 32, Prefix: "2001:db8::"},
+			},
+		},
+		peers: []*gobgpapi.Peer{
+			{
+				Conf: &gobgpapi.PeerConf{LocalAsn: 64511, NeighborAddress: "192.0.2.1", PeerAsn: 64496, Description: "transit"},
+				State: &gobgpapi.PeerState{
+					NeighborAddress: "192.0.2.1",
+					PeerAsn:         64496,
+					RouterId:        "192.0.2.1",
+					SessionState:    gobgpapi.PeerState_ESTABLISHED,
+				},
+				AfiSafis: []*gobgpapi.AfiSafi{unicast(gobgpapi.Family_AFI_IP), unicast(gobgpapi.Family_AFI_IP6)},
+			},
+			{
+				Conf:     &gobgpapi.PeerConf{LocalAsn: 64511, NeighborAddress: "192.0.2.2", PeerAsn: 64497},
+				State:    &gobgpapi.PeerState{NeighborAddress: "192.0.2.2", PeerAsn: 64497, SessionState: gobgpapi.PeerState_ACTIVE},
+				AfiSafis: []*gobgpapi.AfiSafi{unicast(gobgpapi.Family_AFI_IP)},
+			},
+			{
+				Conf:  &gobgpapi.PeerConf{LocalAsn: 64511, NeighborAddress: "2001:db8::2", PeerAsn: 64498},
+				State: &gobgpapi.PeerState{SessionState: gobgpapi.PeerState_ESTABLISHED},
+			},
+		},
+	}
+}
+
+func TestGoBGPConn(t *testing.T) {
+	fake := newFakeGoBGP(t)
+	g := dialFakeGoBGP(t, fake)
+
+	totals, err := g.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if totals != (Totals{V4Rib: 4, V4Fib: 3, V6Rib: 2, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+
+	sessions, err := g.GetBGPSessions()
+	if err != nil {
+		t.Fatalf("GetBGPSessions failed: %v", err)
+	}
+	if s := sessions[0]; s.BGPState != "Established" || s.State != "up" || s.Description != "transit" ||
+		s.LocalAS != 64511 || !s.NeighborID.Equal(net.ParseIP("192.0.2.1")) || len(s.Channels) != 2 || s.Channels[1].Name != "ipv6" {
+		t.Errorf("Unexpected session %+v", s)
+	}
+	if s := sessions[2]; s.Name != "2001:db8::2" || s.NeighborAS != 64498 {
+		t.Errorf("Expected the configured address and ASN of a session with no state, got %+v", s)
+	}
+
+	peers, err := g.GetPeers()
+	if err != nil {
+		t.Fatalf("GetPeers failed: %v", err)
+	}
+	if peers != (Peers{V4c: 2, V4e: 1, V6c: 2, V6e: 2}) {
+		t.Errorf("Unexpected peers %+v", peers)
+	}
+
+	asns, err := g.GetTotalSourceASNs()
+	if err != nil {
+		t.Fatalf("GetTotalSourceASNs failed: %v", err)
+	}
+	if asns != (ASNs{As4: 3, As6: 1, As10: 3, As4Only: 2, AsBoth: 1}) {
+		t.Errorf("Unexpected ASNs %+v", asns)
+	}
+
+	masks, _ := g.GetMasks()
+	if !reflect.DeepEqual(masks, []map[string]uint32{{"24": 3}, {"32": 2}}) {
+		t.Errorf("Unexpected masks %v", masks)
+	}
+
+	roas, err := g.GetROAs()
+	if err != nil {
+		t.Fatalf("GetROAs failed: %v", err)
+	}
+	if roas != (Roas{V4v: 1, V4i: 1, V4u: 1, V6v: 1, V6i: 1}) {
+		t.Errorf("Unexpected ROAs %+v", roas)
+	}
+
+	// The best path is the second one listed, and the only one with a large community
+	large, _ := g.GetLargeCommunities()
+	if large != (Large{V4: 1}) {
+		t.Errorf("Unexpected large communities %+v", large)
+	}
+
+	// 2001:db8::/32 is invalid too, but ends in an AS set so has no origin to list it under
+	invalids, _ := g.GetInvalids()
+	expected := map[string][]string{"64511": {"192.0.2.0/24"}}
+	if !reflect.DeepEqual(invalids, expected) {
+		t.Errorf("Expected invalids %v, got %v", expected, invalids)
+	}
+
+	fake.listPath = nil
+	path, found, err := g.GetASPathFromIP(net.ParseIP("8.8.8.8"))
+	if err != nil || !found || !reflect.DeepEqual(path.Path, []uint32{64496, 15169}) {
+		t.Errorf("GetASPathFromIP returned %+v, %v, %v", path, found, err)
+	}
+	if len(fake.listPath) != 1 || len(fake.listPath[0].GetPrefixes()) != 1 || fake.listPath[0].GetPrefixes()[0].GetPrefix() != "8.8.8.8/32" {
+		t.Errorf("Expected a lookup of the prefixes covering 8.8.8.8/32, got %v", fake.listPath)
+	}
+	if _, found, err := g.GetRoute(net.ParseIP("203.0.113.1")); err != nil || found {
+		t.Errorf("Expected not found, got %v, %v", found, err)
+	}
+	path, found, _ = g.GetASPathFromIP(net.ParseIP("2001:db8::1"))
+	if !found || !reflect.DeepEqual(path, ASPath{Path: []uint32{64496}, Set: []uint32{64510, 64511}}) {
+		t.Errorf("Unexpected AS path with a set %+v", path)
+	}
+
+	nets, _ := g.GetIPv6FromSource(13335)
+	if len(nets) != 1 || nets[0].String() != "2606:4700::/32" {
+		t.Errorf("Unexpected networks %v", nets)
+	}
+
+	_, prefix, _ := net.ParseCIDR("2606:4700:10::/48")
+	if status, found, _ := g.GetROA(prefix, 13335); !found || status != RValid {
+		t.Errorf("Expected valid, got %d", status)
+	}
+	vrps, _ := g.GetVRPs(64511)
+	if len(vrps) != 1 || vrps[0].Prefix.String() != "192.0.2.0/23" || vrps[0].Max != 23 {
+		t.Errorf("Unexpected VRPs %+v", vrps)
+	}
+}
+
+func TestGoBGPConnErrors(t *testing.T) {
+	fake := newFakeGoBGP(t)
+	fake.err = status.Error(codes.Unavailable, "bgp server not started")
+	g := dialFakeGoBGP(t, fake)
+
+	if _, err := g.GetMasks(); status.Code(err) != codes.Unavailable || !strings.Contains(err.Error(), "ListPath") {
+		t.Errorf("GetMasks() error = %v, want the ListPath status", err)
+	}
+	if _, err := g.GetBGPTotal(); status.Code(err) != codes.Unavailable {
+		t.Errorf("GetBGPTotal() error = %v, want Unavailable", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.GetBGPSessionsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetBGPSessionsContext() error = %v, want Canceled", err)
+	}
+}
+
+func TestGoBGPRouteConfed(t *testing.T) {
+	prefix := netip.MustParsePrefix("192.0.2.0/24")
+	for _, tt := range []struct {
+		segments []*gobgpapi.AsSegment
+		path     []uint32
+		origin   uint32
+	}{
+		{[]*gobgpapi.AsSegment{{Type: 3, Numbers: []uint32{65001, 65002}}, {Type: 2, Numbers: []uint32{64496, 15169}}}, []uint32{64496, 15169}, 15169},
+		{[]*gobgpapi.AsSegment{{Type: 2, Numbers: []uint32{64496}}, {Type: 4, Numbers: []uint32{65001, 65002}}}, []uint32{64496}, 64496},
+		{[]*gobgpapi.AsSegment{{Type: 2, Numbers: []uint32{64496}}, {Type: 1, Numbers: []uint32{64510, 64511}}}, []uint32{64496}, 0},
+	} {
+		p := &gobgpapi.Path{Pattrs: []*anypb.Any{gobgpAttr(t, &gobgpapi.AsPathAttribute{Segments: tt.segments})}}
+		r, err := gobgpRoute(prefix, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(r.asPath, tt.path) || r.origin() != tt.origin {
+			t.Errorf("gobgpRoute(%v) has path %v from AS%d, want %v from AS%d", tt.segments, r.asPath, r.origin(), tt.path, tt.origin)
+		}
+	}
+}
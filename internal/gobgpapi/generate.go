@@ -0,0 +1,5 @@
+// Package gobgpapi holds the messages of the GoBGP API read by GoBGPConn,
+// generated from gobgp.proto.
+package gobgpapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative gobgp.proto
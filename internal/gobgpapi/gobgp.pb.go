@@ -0,0 +1,1734 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: gobgp.proto
+
+// The messages of the GoBGP v3 API (api/gobgp.proto and api/attribute.proto
+// of github.com/osrg/gobgp) that GoBGPConn reads. Names and field numbers are
+// GoBGP's, so the messages share its wire format; fields GoBGPConn does not
+// read are left out and skipped when decoding.
+//
+// The package is not GoBGP's apipb, so that programs linking this and
+// github.com/osrg/gobgp/v3/api do not register the same names twice. The
+// service is therefore not declared here: GoBGPConn calls the methods of
+// apipb.GobgpApi by name.
+
+package gobgpapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TableType int32
+
+const (
+	TableType_GLOBAL  TableType = 0
+	TableType_LOCAL   TableType = 1
+	TableType_ADJ_IN  TableType = 2
+	TableType_ADJ_OUT TableType = 3
+	TableType_VRF     TableType = 4
+)
+
+// Enum value maps for TableType.
+var (
+	TableType_name = map[int32]string{
+		0: "GLOBAL",
+		1: "LOCAL",
+		2: "ADJ_IN",
+		3: "ADJ_OUT",
+		4: "VRF",
+	}
+	TableType_value = map[string]int32{
+		"GLOBAL":  0,
+		"LOCAL":   1,
+		"ADJ_IN":  2,
+		"ADJ_OUT": 3,
+		"VRF":     4,
+	}
+)
+
+func (x TableType) Enum() *TableType {
+	p := new(TableType)
+	*p = x
+	return p
+}
+
+func (x TableType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TableType) Descriptor() protoreflect.EnumDescriptor {
+	return file_gobgp_proto_enumTypes[0].Descriptor()
+}
+
+func (TableType) Type() protoreflect.EnumType {
+	return &file_gobgp_proto_enumTypes[0]
+}
+
+func (x TableType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TableType.Descriptor instead.
+func (TableType) EnumDescriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{0}
+}
+
+type Family_Afi int32
+
+const (
+	Family_AFI_UNKNOWN Family_Afi = 0
+	Family_AFI_IP      Family_Afi = 1
+	Family_AFI_IP6     Family_Afi = 2
+)
+
+// Enum value maps for Family_Afi.
+var (
+	Family_Afi_name = map[int32]string{
+		0: "AFI_UNKNOWN",
+		1: "AFI_IP",
+		2: "AFI_IP6",
+	}
+	Family_Afi_value = map[string]int32{
+		"AFI_UNKNOWN": 0,
+		"AFI_IP":      1,
+		"AFI_IP6":     2,
+	}
+)
+
+func (x Family_Afi) Enum() *Family_Afi {
+	p := new(Family_Afi)
+	*p = x
+	return p
+}
+
+func (x Family_Afi) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Family_Afi) Descriptor() protoreflect.EnumDescriptor {
+	return file_gobgp_proto_enumTypes[1].Descriptor()
+}
+
+func (Family_Afi) Type() protoreflect.EnumType {
+	return &file_gobgp_proto_enumTypes[1]
+}
+
+func (x Family_Afi) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Family_Afi.Descriptor instead.
+func (Family_Afi) EnumDescriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type Family_Safi int32
+
+const (
+	Family_SAFI_UNKNOWN    Family_Safi = 0
+	Family_SAFI_UNICAST    Family_Safi = 1
+	Family_SAFI_MULTICAST  Family_Safi = 2
+	Family_SAFI_MPLS_LABEL Family_Safi = 4
+)
+
+// Enum value maps for Family_Safi.
+var (
+	Family_Safi_name = map[int32]string{
+		0: "SAFI_UNKNOWN",
+		1: "SAFI_UNICAST",
+		2: "SAFI_MULTICAST",
+		4: "SAFI_MPLS_LABEL",
+	}
+	Family_Safi_value = map[string]int32{
+		"SAFI_UNKNOWN":    0,
+		"SAFI_UNICAST":    1,
+		"SAFI_MULTICAST":  2,
+		"SAFI_MPLS_LABEL": 4,
+	}
+)
+
+func (x Family_Safi) Enum() *Family_Safi {
+	p := new(Family_Safi)
+	*p = x
+	return p
+}
+
+func (x Family_Safi) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Family_Safi) Descriptor() protoreflect.EnumDescriptor {
+	return file_gobgp_proto_enumTypes[2].Descriptor()
+}
+
+func (Family_Safi) Type() protoreflect.EnumType {
+	return &file_gobgp_proto_enumTypes[2]
+}
+
+func (x Family_Safi) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Family_Safi.Descriptor instead.
+func (Family_Safi) EnumDescriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{0, 1}
+}
+
+type PeerState_SessionState int32
+
+const (
+	PeerState_UNKNOWN     PeerState_SessionState = 0
+	PeerState_IDLE        PeerState_SessionState = 1
+	PeerState_CONNECT     PeerState_SessionState = 2
+	PeerState_ACTIVE      PeerState_SessionState = 3
+	PeerState_OPENSENT    PeerState_SessionState = 4
+	PeerState_OPENCONFIRM PeerState_SessionState = 5
+	PeerState_ESTABLISHED PeerState_SessionState = 6
+)
+
+// Enum value maps for PeerState_SessionState.
+var (
+	PeerState_SessionState_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "IDLE",
+		2: "CONNECT",
+		3: "ACTIVE",
+		4: "OPENSENT",
+		5: "OPENCONFIRM",
+		6: "ESTABLISHED",
+	}
+	PeerState_SessionState_value = map[string]int32{
+		"UNKNOWN":     0,
+		"IDLE":        1,
+		"CONNECT":     2,
+		"ACTIVE":      3,
+		"OPENSENT":    4,
+		"OPENCONFIRM": 5,
+		"ESTABLISHED": 6,
+	}
+)
+
+func (x PeerState_SessionState) Enum() *PeerState_SessionState {
+	p := new(PeerState_SessionState)
+	*p = x
+	return p
+}
+
+func (x PeerState_SessionState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PeerState_SessionState) Descriptor() protoreflect.EnumDescriptor {
+	return file_gobgp_proto_enumTypes[3].Descriptor()
+}
+
+func (PeerState_SessionState) Type() protoreflect.EnumType {
+	return &file_gobgp_proto_enumTypes[3]
+}
+
+func (x PeerState_SessionState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PeerState_SessionState.Descriptor instead.
+func (PeerState_SessionState) EnumDescriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type TableLookupPrefix_Type int32
+
+const (
+	TableLookupPrefix_EXACT   TableLookupPrefix_Type = 0
+	TableLookupPrefix_LONGER  TableLookupPrefix_Type = 1
+	TableLookupPrefix_SHORTER TableLookupPrefix_Type = 2
+)
+
+// Enum value maps for TableLookupPrefix_Type.
+var (
+	TableLookupPrefix_Type_name = map[int32]string{
+		0: "EXACT",
+		1: "LONGER",
+		2: "SHORTER",
+	}
+	TableLookupPrefix_Type_value = map[string]int32{
+		"EXACT":   0,
+		"LONGER":  1,
+		"SHORTER": 2,
+	}
+)
+
+func (x TableLookupPrefix_Type) Enum() *TableLookupPrefix_Type {
+	p := new(TableLookupPrefix_Type)
+	*p = x
+	return p
+}
+
+func (x TableLookupPrefix_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TableLookupPrefix_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_gobgp_proto_enumTypes[4].Descriptor()
+}
+
+func (TableLookupPrefix_Type) Type() protoreflect.EnumType {
+	return &file_gobgp_proto_enumTypes[4]
+}
+
+func (x TableLookupPrefix_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TableLookupPrefix_Type.Descriptor instead.
+func (TableLookupPrefix_Type) EnumDescriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type Family struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Afi           Family_Afi             `protobuf:"varint,1,opt,name=afi,proto3,enum=clidecode.gobgpapi.Family_Afi" json:"afi,omitempty"`
+	Safi          Family_Safi            `protobuf:"varint,2,opt,name=safi,proto3,enum=clidecode.gobgpapi.Family_Safi" json:"safi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Family) Reset() {
+	*x = Family{}
+	mi := &file_gobgp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Family) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Family) ProtoMessage() {}
+
+func (x *Family) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Family.ProtoReflect.Descriptor instead.
+func (*Family) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Family) GetAfi() Family_Afi {
+	if x != nil {
+		return x.Afi
+	}
+	return Family_AFI_UNKNOWN
+}
+
+func (x *Family) GetSafi() Family_Safi {
+	if x != nil {
+		return x.Safi
+	}
+	return Family_SAFI_UNKNOWN
+}
+
+type ListPeerRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Address          string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	EnableAdvertised bool                   `protobuf:"varint,2,opt,name=enableAdvertised,proto3" json:"enableAdvertised,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListPeerRequest) Reset() {
+	*x = ListPeerRequest{}
+	mi := &file_gobgp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeerRequest) ProtoMessage() {}
+
+func (x *ListPeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeerRequest.ProtoReflect.Descriptor instead.
+func (*ListPeerRequest) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListPeerRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ListPeerRequest) GetEnableAdvertised() bool {
+	if x != nil {
+		return x.EnableAdvertised
+	}
+	return false
+}
+
+type ListPeerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Peer          *Peer                  `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPeerResponse) Reset() {
+	*x = ListPeerResponse{}
+	mi := &file_gobgp_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPeerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeerResponse) ProtoMessage() {}
+
+func (x *ListPeerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeerResponse.ProtoReflect.Descriptor instead.
+func (*ListPeerResponse) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPeerResponse) GetPeer() *Peer {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+type Peer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conf          *PeerConf              `protobuf:"bytes,2,opt,name=conf,proto3" json:"conf,omitempty"`
+	State         *PeerState             `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	AfiSafis      []*AfiSafi             `protobuf:"bytes,10,rep,name=afi_safis,json=afiSafis,proto3" json:"afi_safis,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Peer) Reset() {
+	*x = Peer{}
+	mi := &file_gobgp_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Peer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Peer) ProtoMessage() {}
+
+func (x *Peer) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Peer.ProtoReflect.Descriptor instead.
+func (*Peer) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Peer) GetConf() *PeerConf {
+	if x != nil {
+		return x.Conf
+	}
+	return nil
+}
+
+func (x *Peer) GetState() *PeerState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *Peer) GetAfiSafis() []*AfiSafi {
+	if x != nil {
+		return x.AfiSafis
+	}
+	return nil
+}
+
+type PeerConf struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	LocalAsn        uint32                 `protobuf:"varint,3,opt,name=local_asn,json=localAsn,proto3" json:"local_asn,omitempty"`
+	NeighborAddress string                 `protobuf:"bytes,4,opt,name=neighbor_address,json=neighborAddress,proto3" json:"neighbor_address,omitempty"`
+	PeerAsn         uint32                 `protobuf:"varint,5,opt,name=peer_asn,json=peerAsn,proto3" json:"peer_asn,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PeerConf) Reset() {
+	*x = PeerConf{}
+	mi := &file_gobgp_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerConf) ProtoMessage() {}
+
+func (x *PeerConf) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerConf.ProtoReflect.Descriptor instead.
+func (*PeerConf) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PeerConf) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PeerConf) GetLocalAsn() uint32 {
+	if x != nil {
+		return x.LocalAsn
+	}
+	return 0
+}
+
+func (x *PeerConf) GetNeighborAddress() string {
+	if x != nil {
+		return x.NeighborAddress
+	}
+	return ""
+}
+
+func (x *PeerConf) GetPeerAsn() uint32 {
+	if x != nil {
+		return x.PeerAsn
+	}
+	return 0
+}
+
+type PeerState struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	LocalAsn        uint32                 `protobuf:"varint,3,opt,name=local_asn,json=localAsn,proto3" json:"local_asn,omitempty"`
+	NeighborAddress string                 `protobuf:"bytes,5,opt,name=neighbor_address,json=neighborAddress,proto3" json:"neighbor_address,omitempty"`
+	PeerAsn         uint32                 `protobuf:"varint,6,opt,name=peer_asn,json=peerAsn,proto3" json:"peer_asn,omitempty"`
+	SessionState    PeerState_SessionState `protobuf:"varint,13,opt,name=session_state,json=sessionState,proto3,enum=clidecode.gobgpapi.PeerState_SessionState" json:"session_state,omitempty"`
+	RouterId        string                 `protobuf:"bytes,20,opt,name=router_id,json=routerId,proto3" json:"router_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PeerState) Reset() {
+	*x = PeerState{}
+	mi := &file_gobgp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerState) ProtoMessage() {}
+
+func (x *PeerState) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerState.ProtoReflect.Descriptor instead.
+func (*PeerState) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PeerState) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PeerState) GetLocalAsn() uint32 {
+	if x != nil {
+		return x.LocalAsn
+	}
+	return 0
+}
+
+func (x *PeerState) GetNeighborAddress() string {
+	if x != nil {
+		return x.NeighborAddress
+	}
+	return ""
+}
+
+func (x *PeerState) GetPeerAsn() uint32 {
+	if x != nil {
+		return x.PeerAsn
+	}
+	return 0
+}
+
+func (x *PeerState) GetSessionState() PeerState_SessionState {
+	if x != nil {
+		return x.SessionState
+	}
+	return PeerState_UNKNOWN
+}
+
+func (x *PeerState) GetRouterId() string {
+	if x != nil {
+		return x.RouterId
+	}
+	return ""
+}
+
+type AfiSafi struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        *AfiSafiConfig         `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AfiSafi) Reset() {
+	*x = AfiSafi{}
+	mi := &file_gobgp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AfiSafi) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AfiSafi) ProtoMessage() {}
+
+func (x *AfiSafi) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AfiSafi.ProtoReflect.Descriptor instead.
+func (*AfiSafi) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AfiSafi) GetConfig() *AfiSafiConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type AfiSafiConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Family        *Family                `protobuf:"bytes,1,opt,name=family,proto3" json:"family,omitempty"`
+	Enabled       bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AfiSafiConfig) Reset() {
+	*x = AfiSafiConfig{}
+	mi := &file_gobgp_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AfiSafiConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AfiSafiConfig) ProtoMessage() {}
+
+func (x *AfiSafiConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AfiSafiConfig.ProtoReflect.Descriptor instead.
+func (*AfiSafiConfig) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AfiSafiConfig) GetFamily() *Family {
+	if x != nil {
+		return x.Family
+	}
+	return nil
+}
+
+func (x *AfiSafiConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type TableLookupPrefix struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Type          TableLookupPrefix_Type `protobuf:"varint,2,opt,name=type,proto3,enum=clidecode.gobgpapi.TableLookupPrefix_Type" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TableLookupPrefix) Reset() {
+	*x = TableLookupPrefix{}
+	mi := &file_gobgp_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableLookupPrefix) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableLookupPrefix) ProtoMessage() {}
+
+func (x *TableLookupPrefix) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableLookupPrefix.ProtoReflect.Descriptor instead.
+func (*TableLookupPrefix) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TableLookupPrefix) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *TableLookupPrefix) GetType() TableLookupPrefix_Type {
+	if x != nil {
+		return x.Type
+	}
+	return TableLookupPrefix_EXACT
+}
+
+type ListPathRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TableType     TableType              `protobuf:"varint,1,opt,name=table_type,json=tableType,proto3,enum=clidecode.gobgpapi.TableType" json:"table_type,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Family        *Family                `protobuf:"bytes,3,opt,name=family,proto3" json:"family,omitempty"`
+	Prefixes      []*TableLookupPrefix   `protobuf:"bytes,4,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPathRequest) Reset() {
+	*x = ListPathRequest{}
+	mi := &file_gobgp_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPathRequest) ProtoMessage() {}
+
+func (x *ListPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPathRequest.ProtoReflect.Descriptor instead.
+func (*ListPathRequest) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListPathRequest) GetTableType() TableType {
+	if x != nil {
+		return x.TableType
+	}
+	return TableType_GLOBAL
+}
+
+func (x *ListPathRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListPathRequest) GetFamily() *Family {
+	if x != nil {
+		return x.Family
+	}
+	return nil
+}
+
+func (x *ListPathRequest) GetPrefixes() []*TableLookupPrefix {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+type ListPathResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Destination   *Destination           `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPathResponse) Reset() {
+	*x = ListPathResponse{}
+	mi := &file_gobgp_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPathResponse) ProtoMessage() {}
+
+func (x *ListPathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPathResponse.ProtoReflect.Descriptor instead.
+func (*ListPathResponse) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListPathResponse) GetDestination() *Destination {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
+type Destination struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Paths         []*Path                `protobuf:"bytes,2,rep,name=paths,proto3" json:"paths,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Destination) Reset() {
+	*x = Destination{}
+	mi := &file_gobgp_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Destination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Destination) ProtoMessage() {}
+
+func (x *Destination) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Destination.ProtoReflect.Descriptor instead.
+func (*Destination) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Destination) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *Destination) GetPaths() []*Path {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+type Path struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nlri          *anypb.Any             `protobuf:"bytes,1,opt,name=nlri,proto3" json:"nlri,omitempty"`
+	Pattrs        []*anypb.Any           `protobuf:"bytes,2,rep,name=pattrs,proto3" json:"pattrs,omitempty"`
+	Best          bool                   `protobuf:"varint,4,opt,name=best,proto3" json:"best,omitempty"`
+	NeighborIp    string                 `protobuf:"bytes,15,opt,name=neighbor_ip,json=neighborIp,proto3" json:"neighbor_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Path) Reset() {
+	*x = Path{}
+	mi := &file_gobgp_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Path) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Path) ProtoMessage() {}
+
+func (x *Path) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Path.ProtoReflect.Descriptor instead.
+func (*Path) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Path) GetNlri() *anypb.Any {
+	if x != nil {
+		return x.Nlri
+	}
+	return nil
+}
+
+func (x *Path) GetPattrs() []*anypb.Any {
+	if x != nil {
+		return x.Pattrs
+	}
+	return nil
+}
+
+func (x *Path) GetBest() bool {
+	if x != nil {
+		return x.Best
+	}
+	return false
+}
+
+func (x *Path) GetNeighborIp() string {
+	if x != nil {
+		return x.NeighborIp
+	}
+	return ""
+}
+
+type GetTableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TableType     TableType              `protobuf:"varint,1,opt,name=table_type,json=tableType,proto3,enum=clidecode.gobgpapi.TableType" json:"table_type,omitempty"`
+	Family        *Family                `protobuf:"bytes,2,opt,name=family,proto3" json:"family,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTableRequest) Reset() {
+	*x = GetTableRequest{}
+	mi := &file_gobgp_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTableRequest) ProtoMessage() {}
+
+func (x *GetTableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTableRequest.ProtoReflect.Descriptor instead.
+func (*GetTableRequest) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetTableRequest) GetTableType() TableType {
+	if x != nil {
+		return x.TableType
+	}
+	return TableType_GLOBAL
+}
+
+func (x *GetTableRequest) GetFamily() *Family {
+	if x != nil {
+		return x.Family
+	}
+	return nil
+}
+
+func (x *GetTableRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetTableResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NumDestination uint64                 `protobuf:"varint,1,opt,name=num_destination,json=numDestination,proto3" json:"num_destination,omitempty"`
+	NumPath        uint64                 `protobuf:"varint,2,opt,name=num_path,json=numPath,proto3" json:"num_path,omitempty"`
+	NumAccepted    uint64                 `protobuf:"varint,3,opt,name=num_accepted,json=numAccepted,proto3" json:"num_accepted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetTableResponse) Reset() {
+	*x = GetTableResponse{}
+	mi := &file_gobgp_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTableResponse) ProtoMessage() {}
+
+func (x *GetTableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTableResponse.ProtoReflect.Descriptor instead.
+func (*GetTableResponse) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetTableResponse) GetNumDestination() uint64 {
+	if x != nil {
+		return x.NumDestination
+	}
+	return 0
+}
+
+func (x *GetTableResponse) GetNumPath() uint64 {
+	if x != nil {
+		return x.NumPath
+	}
+	return 0
+}
+
+func (x *GetTableResponse) GetNumAccepted() uint64 {
+	if x != nil {
+		return x.NumAccepted
+	}
+	return 0
+}
+
+type ListRpkiTableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Family        *Family                `protobuf:"bytes,1,opt,name=family,proto3" json:"family,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRpkiTableRequest) Reset() {
+	*x = ListRpkiTableRequest{}
+	mi := &file_gobgp_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRpkiTableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRpkiTableRequest) ProtoMessage() {}
+
+func (x *ListRpkiTableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRpkiTableRequest.ProtoReflect.Descriptor instead.
+func (*ListRpkiTableRequest) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListRpkiTableRequest) GetFamily() *Family {
+	if x != nil {
+		return x.Family
+	}
+	return nil
+}
+
+type ListRpkiTableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roa           *Roa                   `protobuf:"bytes,1,opt,name=roa,proto3" json:"roa,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRpkiTableResponse) Reset() {
+	*x = ListRpkiTableResponse{}
+	mi := &file_gobgp_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRpkiTableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRpkiTableResponse) ProtoMessage() {}
+
+func (x *ListRpkiTableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRpkiTableResponse.ProtoReflect.Descriptor instead.
+func (*ListRpkiTableResponse) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListRpkiTableResponse) GetRoa() *Roa {
+	if x != nil {
+		return x.Roa
+	}
+	return nil
+}
+
+type Roa struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	As            uint32                 `protobuf:"varint,1,opt,name=as,proto3" json:"as,omitempty"`
+	Prefixlen     uint32                 `protobuf:"varint,2,opt,name=prefixlen,proto3" json:"prefixlen,omitempty"`
+	Maxlen        uint32                 `protobuf:"varint,3,opt,name=maxlen,proto3" json:"maxlen,omitempty"`
+	Prefix        string                 `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Roa) Reset() {
+	*x = Roa{}
+	mi := &file_gobgp_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Roa) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Roa) ProtoMessage() {}
+
+func (x *Roa) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Roa.ProtoReflect.Descriptor instead.
+func (*Roa) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Roa) GetAs() uint32 {
+	if x != nil {
+		return x.As
+	}
+	return 0
+}
+
+func (x *Roa) GetPrefixlen() uint32 {
+	if x != nil {
+		return x.Prefixlen
+	}
+	return 0
+}
+
+func (x *Roa) GetMaxlen() uint32 {
+	if x != nil {
+		return x.Maxlen
+	}
+	return 0
+}
+
+func (x *Roa) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type AsSegment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          uint32                 `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Numbers       []uint32               `protobuf:"varint,2,rep,packed,name=numbers,proto3" json:"numbers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AsSegment) Reset() {
+	*x = AsSegment{}
+	mi := &file_gobgp_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AsSegment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AsSegment) ProtoMessage() {}
+
+func (x *AsSegment) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AsSegment.ProtoReflect.Descriptor instead.
+func (*AsSegment) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AsSegment) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *AsSegment) GetNumbers() []uint32 {
+	if x != nil {
+		return x.Numbers
+	}
+	return nil
+}
+
+type AsPathAttribute struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Segments      []*AsSegment           `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AsPathAttribute) Reset() {
+	*x = AsPathAttribute{}
+	mi := &file_gobgp_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AsPathAttribute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AsPathAttribute) ProtoMessage() {}
+
+func (x *AsPathAttribute) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AsPathAttribute.ProtoReflect.Descriptor instead.
+func (*AsPathAttribute) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AsPathAttribute) GetSegments() []*AsSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+type LargeCommunity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GlobalAdmin   uint32                 `protobuf:"varint,1,opt,name=global_admin,json=globalAdmin,proto3" json:"global_admin,omitempty"`
+	LocalData1    uint32                 `protobuf:"varint,2,opt,name=local_data1,json=localData1,proto3" json:"local_data1,omitempty"`
+	LocalData2    uint32                 `protobuf:"varint,3,opt,name=local_data2,json=localData2,proto3" json:"local_data2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LargeCommunity) Reset() {
+	*x = LargeCommunity{}
+	mi := &file_gobgp_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LargeCommunity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LargeCommunity) ProtoMessage() {}
+
+func (x *LargeCommunity) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LargeCommunity.ProtoReflect.Descriptor instead.
+func (*LargeCommunity) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LargeCommunity) GetGlobalAdmin() uint32 {
+	if x != nil {
+		return x.GlobalAdmin
+	}
+	return 0
+}
+
+func (x *LargeCommunity) GetLocalData1() uint32 {
+	if x != nil {
+		return x.LocalData1
+	}
+	return 0
+}
+
+func (x *LargeCommunity) GetLocalData2() uint32 {
+	if x != nil {
+		return x.LocalData2
+	}
+	return 0
+}
+
+type LargeCommunitiesAttribute struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Communities   []*LargeCommunity      `protobuf:"bytes,1,rep,name=communities,proto3" json:"communities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LargeCommunitiesAttribute) Reset() {
+	*x = LargeCommunitiesAttribute{}
+	mi := &file_gobgp_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LargeCommunitiesAttribute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LargeCommunitiesAttribute) ProtoMessage() {}
+
+func (x *LargeCommunitiesAttribute) ProtoReflect() protoreflect.Message {
+	mi := &file_gobgp_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LargeCommunitiesAttribute.ProtoReflect.Descriptor instead.
+func (*LargeCommunitiesAttribute) Descriptor() ([]byte, []int) {
+	return file_gobgp_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LargeCommunitiesAttribute) GetCommunities() []*LargeCommunity {
+	if x != nil {
+		return x.Communities
+	}
+	return nil
+}
+
+var File_gobgp_proto protoreflect.FileDescriptor
+
+const file_gobgp_proto_rawDesc = "" +
+	"\n" +
+	"\vgobgp.proto\x12\x12clidecode.gobgpapi\x1a\x19google/protobuf/any.proto\"\xf5\x01\n" +
+	"\x06Family\x120\n" +
+	"\x03afi\x18\x01 \x01(\x0e2\x1e.clidecode.gobgpapi.Family.AfiR\x03afi\x123\n" +
+	"\x04safi\x18\x02 \x01(\x0e2\x1f.clidecode.gobgpapi.Family.SafiR\x04safi\"/\n" +
+	"\x03Afi\x12\x0f\n" +
+	"\vAFI_UNKNOWN\x10\x00\x12\n" +
+	"\n" +
+	"\x06AFI_IP\x10\x01\x12\v\n" +
+	"\aAFI_IP6\x10\x02\"S\n" +
+	"\x04Safi\x12\x10\n" +
+	"\fSAFI_UNKNOWN\x10\x00\x12\x10\n" +
+	"\fSAFI_UNICAST\x10\x01\x12\x12\n" +
+	"\x0eSAFI_MULTICAST\x10\x02\x12\x13\n" +
+	"\x0fSAFI_MPLS_LABEL\x10\x04\"W\n" +
+	"\x0fListPeerRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12*\n" +
+	"\x10enableAdvertised\x18\x02 \x01(\bR\x10enableAdvertised\"@\n" +
+	"\x10ListPeerResponse\x12,\n" +
+	"\x04peer\x18\x01 \x01(\v2\x18.clidecode.gobgpapi.PeerR\x04peer\"\xa7\x01\n" +
+	"\x04Peer\x120\n" +
+	"\x04conf\x18\x02 \x01(\v2\x1c.clidecode.gobgpapi.PeerConfR\x04conf\x123\n" +
+	"\x05state\x18\x05 \x01(\v2\x1d.clidecode.gobgpapi.PeerStateR\x05state\x128\n" +
+	"\tafi_safis\x18\n" +
+	" \x03(\v2\x1b.clidecode.gobgpapi.AfiSafiR\bafiSafis\"\x8f\x01\n" +
+	"\bPeerConf\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tlocal_asn\x18\x03 \x01(\rR\blocalAsn\x12)\n" +
+	"\x10neighbor_address\x18\x04 \x01(\tR\x0fneighborAddress\x12\x19\n" +
+	"\bpeer_asn\x18\x05 \x01(\rR\apeerAsn\"\xee\x02\n" +
+	"\tPeerState\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tlocal_asn\x18\x03 \x01(\rR\blocalAsn\x12)\n" +
+	"\x10neighbor_address\x18\x05 \x01(\tR\x0fneighborAddress\x12\x19\n" +
+	"\bpeer_asn\x18\x06 \x01(\rR\apeerAsn\x12O\n" +
+	"\rsession_state\x18\r \x01(\x0e2*.clidecode.gobgpapi.PeerState.SessionStateR\fsessionState\x12\x1b\n" +
+	"\trouter_id\x18\x14 \x01(\tR\brouterId\"n\n" +
+	"\fSessionState\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\b\n" +
+	"\x04IDLE\x10\x01\x12\v\n" +
+	"\aCONNECT\x10\x02\x12\n" +
+	"\n" +
+	"\x06ACTIVE\x10\x03\x12\f\n" +
+	"\bOPENSENT\x10\x04\x12\x0f\n" +
+	"\vOPENCONFIRM\x10\x05\x12\x0f\n" +
+	"\vESTABLISHED\x10\x06\"D\n" +
+	"\aAfiSafi\x129\n" +
+	"\x06config\x18\x02 \x01(\v2!.clidecode.gobgpapi.AfiSafiConfigR\x06config\"]\n" +
+	"\rAfiSafiConfig\x122\n" +
+	"\x06family\x18\x01 \x01(\v2\x1a.clidecode.gobgpapi.FamilyR\x06family\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\"\x97\x01\n" +
+	"\x11TableLookupPrefix\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12>\n" +
+	"\x04type\x18\x02 \x01(\x0e2*.clidecode.gobgpapi.TableLookupPrefix.TypeR\x04type\"*\n" +
+	"\x04Type\x12\t\n" +
+	"\x05EXACT\x10\x00\x12\n" +
+	"\n" +
+	"\x06LONGER\x10\x01\x12\v\n" +
+	"\aSHORTER\x10\x02\"\xda\x01\n" +
+	"\x0fListPathRequest\x12<\n" +
+	"\n" +
+	"table_type\x18\x01 \x01(\x0e2\x1d.clidecode.gobgpapi.TableTypeR\ttableType\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x122\n" +
+	"\x06family\x18\x03 \x01(\v2\x1a.clidecode.gobgpapi.FamilyR\x06family\x12A\n" +
+	"\bprefixes\x18\x04 \x03(\v2%.clidecode.gobgpapi.TableLookupPrefixR\bprefixes\"U\n" +
+	"\x10ListPathResponse\x12A\n" +
+	"\vdestination\x18\x01 \x01(\v2\x1f.clidecode.gobgpapi.DestinationR\vdestination\"U\n" +
+	"\vDestination\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12.\n" +
+	"\x05paths\x18\x02 \x03(\v2\x18.clidecode.gobgpapi.PathR\x05paths\"\x93\x01\n" +
+	"\x04Path\x12(\n" +
+	"\x04nlri\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x04nlri\x12,\n" +
+	"\x06pattrs\x18\x02 \x03(\v2\x14.google.protobuf.AnyR\x06pattrs\x12\x12\n" +
+	"\x04best\x18\x04 \x01(\bR\x04best\x12\x1f\n" +
+	"\vneighbor_ip\x18\x0f \x01(\tR\n" +
+	"neighborIp\"\x97\x01\n" +
+	"\x0fGetTableRequest\x12<\n" +
+	"\n" +
+	"table_type\x18\x01 \x01(\x0e2\x1d.clidecode.gobgpapi.TableTypeR\ttableType\x122\n" +
+	"\x06family\x18\x02 \x01(\v2\x1a.clidecode.gobgpapi.FamilyR\x06family\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"y\n" +
+	"\x10GetTableResponse\x12'\n" +
+	"\x0fnum_destination\x18\x01 \x01(\x04R\x0enumDestination\x12\x19\n" +
+	"\bnum_path\x18\x02 \x01(\x04R\anumPath\x12!\n" +
+	"\fnum_accepted\x18\x03 \x01(\x04R\vnumAccepted\"J\n" +
+	"\x14ListRpkiTableRequest\x122\n" +
+	"\x06family\x18\x01 \x01(\v2\x1a.clidecode.gobgpapi.FamilyR\x06family\"B\n" +
+	"\x15ListRpkiTableResponse\x12)\n" +
+	"\x03roa\x18\x01 \x01(\v2\x17.clidecode.gobgpapi.RoaR\x03roa\"c\n" +
+	"\x03Roa\x12\x0e\n" +
+	"\x02as\x18\x01 \x01(\rR\x02as\x12\x1c\n" +
+	"\tprefixlen\x18\x02 \x01(\rR\tprefixlen\x12\x16\n" +
+	"\x06maxlen\x18\x03 \x01(\rR\x06maxlen\x12\x16\n" +
+	"\x06prefix\x18\x04 \x01(\tR\x06prefix\"9\n" +
+	"\tAsSegment\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\rR\x04type\x12\x18\n" +
+	"\anumbers\x18\x02 \x03(\rR\anumbers\"L\n" +
+	"\x0fAsPathAttribute\x129\n" +
+	"\bsegments\x18\x01 \x03(\v2\x1d.clidecode.gobgpapi.AsSegmentR\bsegments\"u\n" +
+	"\x0eLargeCommunity\x12!\n" +
+	"\fglobal_admin\x18\x01 \x01(\rR\vglobalAdmin\x12\x1f\n" +
+	"\vlocal_data1\x18\x02 \x01(\rR\n" +
+	"localData1\x12\x1f\n" +
+	"\vlocal_data2\x18\x03 \x01(\rR\n" +
+	"localData2\"a\n" +
+	"\x19LargeCommunitiesAttribute\x12D\n" +
+	"\vcommunities\x18\x01 \x03(\v2\".clidecode.gobgpapi.LargeCommunityR\vcommunities*D\n" +
+	"\tTableType\x12\n" +
+	"\n" +
+	"\x06GLOBAL\x10\x00\x12\t\n" +
+	"\x05LOCAL\x10\x01\x12\n" +
+	"\n" +
+	"\x06ADJ_IN\x10\x02\x12\v\n" +
+	"\aADJ_OUT\x10\x03\x12\a\n" +
+	"\x03VRF\x10\x04B6Z4github.com/mellowdrifter/clidecode/internal/gobgpapib\x06proto3"
+
+var (
+	file_gobgp_proto_rawDescOnce sync.Once
+	file_gobgp_proto_rawDescData []byte
+)
+
+func file_gobgp_proto_rawDescGZIP() []byte {
+	file_gobgp_proto_rawDescOnce.Do(func() {
+		file_gobgp_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gobgp_proto_rawDesc), len(file_gobgp_proto_rawDesc)))
+	})
+	return file_gobgp_proto_rawDescData
+}
+
+var file_gobgp_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_gobgp_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_gobgp_proto_goTypes = []any{
+	(TableType)(0),                    // 0: clidecode.gobgpapi.TableType
+	(Family_Afi)(0),                   // 1: clidecode.gobgpapi.Family.Afi
+	(Family_Safi)(0),                  // 2: clidecode.gobgpapi.Family.Safi
+	(PeerState_SessionState)(0),       // 3: clidecode.gobgpapi.PeerState.SessionState
+	(TableLookupPrefix_Type)(0),       // 4: clidecode.gobgpapi.TableLookupPrefix.Type
+	(*Family)(nil),                    // 5: clidecode.gobgpapi.Family
+	(*ListPeerRequest)(nil),           // 6: clidecode.gobgpapi.ListPeerRequest
+	(*ListPeerResponse)(nil),          // 7: clidecode.gobgpapi.ListPeerResponse
+	(*Peer)(nil),                      // 8: clidecode.gobgpapi.Peer
+	(*PeerConf)(nil),                  // 9: clidecode.gobgpapi.PeerConf
+	(*PeerState)(nil),                 // 10: clidecode.gobgpapi.PeerState
+	(*AfiSafi)(nil),                   // 11: clidecode.gobgpapi.AfiSafi
+	(*AfiSafiConfig)(nil),             // 12: clidecode.gobgpapi.AfiSafiConfig
+	(*TableLookupPrefix)(nil),         // 13: clidecode.gobgpapi.TableLookupPrefix
+	(*ListPathRequest)(nil),           // 14: clidecode.gobgpapi.ListPathRequest
+	(*ListPathResponse)(nil),          // 15: clidecode.gobgpapi.ListPathResponse
+	(*Destination)(nil),               // 16: clidecode.gobgpapi.Destination
+	(*Path)(nil),                      // 17: clidecode.gobgpapi.Path
+	(*GetTableRequest)(nil),           // 18: clidecode.gobgpapi.GetTableRequest
+	(*GetTableResponse)(nil),          // 19: clidecode.gobgpapi.GetTableResponse
+	(*ListRpkiTableRequest)(nil),      // 20: clidecode.gobgpapi.ListRpkiTableRequest
+	(*ListRpkiTableResponse)(nil),     // 21: clidecode.gobgpapi.ListRpkiTableResponse
+	(*Roa)(nil),                       // 22: clidecode.gobgpapi.Roa
+	(*AsSegment)(nil),                 // 23: clidecode.gobgpapi.AsSegment
+	(*AsPathAttribute)(nil),           // 24: clidecode.gobgpapi.AsPathAttribute
+	(*LargeCommunity)(nil),            // 25: clidecode.gobgpapi.LargeCommunity
+	(*LargeCommunitiesAttribute)(nil), // 26: clidecode.gobgpapi.LargeCommunitiesAttribute
+	(*anypb.Any)(nil),                 // 27: google.protobuf.Any
+}
+var file_gobgp_proto_depIdxs = []int32{
+	1,  // 0: clidecode.gobgpapi.Family.afi:type_name -> clidecode.gobgpapi.Family.Afi
+	2,  // 1: clidecode.gobgpapi.Family.safi:type_name -> clidecode.gobgpapi.Family.Safi
+	8,  // 2: clidecode.gobgpapi.ListPeerResponse.peer:type_name -> clidecode.gobgpapi.Peer
+	9,  // 3: clidecode.gobgpapi.Peer.conf:type_name -> clidecode.gobgpapi.PeerConf
+	10, // 4: clidecode.gobgpapi.Peer.state:type_name -> clidecode.gobgpapi.PeerState
+	11, // 5: clidecode.gobgpapi.Peer.afi_safis:type_name -> clidecode.gobgpapi.AfiSafi
+	3,  // 6: clidecode.gobgpapi.PeerState.session_state:type_name -> clidecode.gobgpapi.PeerState.SessionState
+	12, // 7: clidecode.gobgpapi.AfiSafi.config:type_name -> clidecode.gobgpapi.AfiSafiConfig
+	5,  // 8: clidecode.gobgpapi.AfiSafiConfig.family:type_name -> clidecode.gobgpapi.Family
+	4,  // 9: clidecode.gobgpapi.TableLookupPrefix.type:type_name -> clidecode.gobgpapi.TableLookupPrefix.Type
+	0,  // 10: clidecode.gobgpapi.ListPathRequest.table_type:type_name -> clidecode.gobgpapi.TableType
+	5,  // 11: clidecode.gobgpapi.ListPathRequest.family:type_name -> clidecode.gobgpapi.Family
+	13, // 12: clidecode.gobgpapi.ListPathRequest.prefixes:type_name -> clidecode.gobgpapi.TableLookupPrefix
+	16, // 13: clidecode.gobgpapi.ListPathResponse.destination:type_name -> clidecode.gobgpapi.Destination
+	17, // 14: clidecode.gobgpapi.Destination.paths:type_name -> clidecode.gobgpapi.Path
+	27, // 15: clidecode.gobgpapi.Path.nlri:type_name -> google.protobuf.Any
+	27, // 16: clidecode.gobgpapi.Path.pattrs:type_name -> google.protobuf.Any
+	0,  // 17: clidecode.gobgpapi.GetTableRequest.table_type:type_name -> clidecode.gobgpapi.TableType
+	5,  // 18: clidecode.gobgpapi.GetTableRequest.family:type_name -> clidecode.gobgpapi.Family
+	5,  // 19: clidecode.gobgpapi.ListRpkiTableRequest.family:type_name -> clidecode.gobgpapi.Family
+	22, // 20: clidecode.gobgpapi.ListRpkiTableResponse.roa:type_name -> clidecode.gobgpapi.Roa
+	23, // 21: clidecode.gobgpapi.AsPathAttribute.segments:type_name -> clidecode.gobgpapi.AsSegment
+	25, // 22: clidecode.gobgpapi.LargeCommunitiesAttribute.communities:type_name -> clidecode.gobgpapi.LargeCommunity
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_gobgp_proto_init() }
+func file_gobgp_proto_init() {
+	if File_gobgp_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gobgp_proto_rawDesc), len(file_gobgp_proto_rawDesc)),
+			NumEnums:      5,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_gobgp_proto_goTypes,
+		DependencyIndexes: file_gobgp_proto_depIdxs,
+		EnumInfos:         file_gobgp_proto_enumTypes,
+		MessageInfos:      file_gobgp_proto_msgTypes,
+	}.Build()
+	File_gobgp_proto = out.File
+	file_gobgp_proto_goTypes = nil
+	file_gobgp_proto_depIdxs = nil
+}
@@ -0,0 +1,465 @@
+// Package lookingglass serves read-only queries against a clidecode.Decoder
+// over HTTP, as a JSON API and a small HTML page, for customers to look up
+// routes, AS paths, origins, ROA states, prefixes by origin and VRPs.
+//
+// The API answers GET /api/{query}, such as /api/route?ip=192.0.2.1, with a
+// JSON object, or {"error": "..."} and a matching status code. Each client is
+// rate limited, and only so many queries run against the Decoder at once, so
+// customers can't overload the router between them.
+package lookingglass
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+)
+
+// Query names
+const (
+	QueryRoute    = "route"
+	QueryASPath   = "aspath"
+	QueryOrigin   = "origin"
+	QueryROA      = "roa"
+	QueryPrefixes = "prefixes"
+	QueryVRPs     = "vrps"
+)
+
+// Defaults used by New
+const (
+	DefaultMaxConcurrent = 4
+	DefaultRate          = 1 // requests per second per client
+	DefaultBurst         = 10
+	DefaultIPv6Prefix    = 64
+	DefaultMaxClients    = 10000
+)
+
+// maxParamLength bounds every query parameter, no valid one is longer
+const maxParamLength = 64
+
+var errNotFound = errors.New("not found")
+
+// Server is an http.Handler serving the looking glass.
+// Fields should be set before the first request.
+type Server struct {
+	Decoder clidecode.Decoder
+
+	// Queries lists the queries exposed, such as QueryRoute. Nil exposes all.
+	Queries []string
+
+	// Timeout bounds each query, including any wait for a free slot
+	Timeout time.Duration
+
+	// MaxConcurrent is the number of queries run against the Decoder at once.
+	// A Decoder that does not implement DecoderContext runs a query to the end
+	// even after it timed out, and it counts until then.
+	MaxConcurrent int
+
+	// Rate is the sustained requests per second allowed per client, with
+	// bursts of up to Burst. A Rate of 0 disables rate limiting.
+	Rate  float64
+	Burst int
+
+	// ClientID names the client a request counts against for rate limiting.
+	// If nil, the remote IP is used, which is the proxy's if behind one.
+	// IPv6 clients are grouped by their IPv6Prefix, as a single host usually
+	// holds a whole /64 and could otherwise pick a new address per request.
+	ClientID   func(*http.Request) string
+	IPv6Prefix int
+
+	// MaxClients bounds the clients tracked for rate limiting. Once reached,
+	// the client seen least recently is forgotten to make room.
+	MaxClients int
+
+	// Logf, if set, logs failed queries, whose details are not shown to clients
+	Logf func(format string, args ...any)
+
+	once    sync.Once
+	mux     *http.ServeMux
+	slots   chan struct{}
+	limiter *limiter
+	now     func() time.Time
+}
+
+// New creates a Server for d exposing every query, with the default limits
+func New(d clidecode.Decoder) *Server {
+	return &Server{
+		Decoder:       d,
+		Timeout:       clidecode.DefaultTimeout,
+		MaxConcurrent: DefaultMaxConcurrent,
+		Rate:          DefaultRate,
+		Burst:         DefaultBurst,
+		IPv6Prefix:    DefaultIPv6Prefix,
+		MaxClients:    DefaultMaxClients,
+	}
+}
+
+func (s *Server) init() {
+	if s.MaxConcurrent <= 0 {
+		s.MaxConcurrent = DefaultMaxConcurrent
+	}
+	if s.Timeout <= 0 {
+		s.Timeout = clidecode.DefaultTimeout
+	}
+	if s.Burst <= 0 {
+		s.Burst = DefaultBurst
+	}
+	if s.IPv6Prefix <= 0 || s.IPv6Prefix > 128 {
+		s.IPv6Prefix = DefaultIPv6Prefix
+	}
+	if s.MaxClients <= 0 {
+		s.MaxClients = DefaultMaxClients
+	}
+	s.slots = make(chan struct{}, s.MaxConcurrent)
+	if s.Rate > 0 {
+		s.limiter = newLimiter(s.Rate, s.Burst, s.MaxClients)
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /api/{query}", s.serveAPI)
+	s.mux.HandleFunc("GET /{$}", s.serveUI)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.once.Do(s.init)
+	s.mux.ServeHTTP(w, r)
+}
+
+// param is a query parameter
+type param struct {
+	Name, Placeholder string
+	Optional          bool
+}
+
+// query is a lookup exposed by the looking glass
+type query struct {
+	name, label string
+	params      []param
+	run         func(ctx context.Context, d clidecode.DecoderContext, a args) (any, error)
+}
+
+var (
+	ipParam     = param{Name: "ip", Placeholder: "IP address"}
+	prefixParam = param{Name: "prefix", Placeholder: "prefix, e.g. 192.0.2.0/24"}
+	asnParam    = param{Name: "asn", Placeholder: "ASN"}
+	familyParam = param{Name: "family", Placeholder: "4 or 6 (optional)", Optional: true}
+)
+
+var queries = []query{
+	{QueryRoute, "Route", []param{ipParam}, runRoute},
+	{QueryASPath, "AS path", []param{ipParam}, runASPath},
+	{QueryOrigin, "Origin ASN", []param{ipParam}, runOrigin},
+	{QueryROA, "ROA status", []param{prefixParam, asnParam}, runROA},
+	{QueryPrefixes, "Prefixes by origin", []param{asnParam, familyParam}, runPrefixes},
+	{QueryVRPs, "VRPs", []param{asnParam}, runVRPs},
+}
+
+// enabled returns the queries exposed, in the order shown
+func (s *Server) enabled() []query {
+	var q []query
+	for _, query := range queries {
+		if s.Queries == nil || slices.Contains(s.Queries, query.name) {
+			q = append(q, query)
+		}
+	}
+	return q
+}
+
+// args are the validated parameters of a query
+type args struct {
+	ip     net.IP
+	prefix *net.IPNet
+	asn    uint32
+	family string
+}
+
+// parseArgs validates the parameters q needs
+func parseArgs(q query, get func(string) string) (args, error) {
+	var a args
+	for _, p := range q.params {
+		v := strings.TrimSpace(get(p.Name))
+		if v == "" {
+			if p.Optional {
+				continue
+			}
+			return a, fmt.Errorf("missing %s", p.Name)
+		}
+		if len(v) > maxParamLength {
+			return a, fmt.Errorf("%s is too long", p.Name)
+		}
+		switch p.Name {
+		case "ip":
+			if a.ip = net.ParseIP(v); a.ip == nil {
+				return a, fmt.Errorf("invalid IP address %q", v)
+			}
+			if ip4 := a.ip.To4(); ip4 != nil {
+				a.ip = ip4
+			}
+		case "prefix":
+			_, prefix, err := net.ParseCIDR(v)
+			if err != nil {
+				return a, fmt.Errorf("invalid prefix %q", v)
+			}
+			a.prefix = prefix
+		case "asn":
+			asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(v), "AS"), 10, 32)
+			if err != nil {
+				return a, fmt.Errorf("invalid ASN %q", v)
+			}
+			a.asn = uint32(asn)
+		case "family":
+			if v != "4" && v != "6" {
+				return a, fmt.Errorf("invalid family %q, expected 4 or 6", v)
+			}
+			a.family = v
+		}
+	}
+	return a, nil
+}
+
+// apiError is an error answer with its status code
+type apiError struct {
+	status     int
+	msg        string
+	retryAfter time.Duration
+}
+
+// do runs the named query for r, as both the API and the UI do
+func (s *Server) do(r *http.Request, name string, get func(string) string) (any, *apiError) {
+	i := slices.IndexFunc(s.enabled(), func(q query) bool { return q.name == name })
+	if i < 0 {
+		return nil, &apiError{status: http.StatusNotFound, msg: fmt.Sprintf("unknown query %q", name)}
+	}
+	q := s.enabled()[i]
+
+	if s.limiter != nil {
+		if ok, wait := s.limiter.allow(s.clientID(r), s.now()); !ok {
+			return nil, &apiError{status: http.StatusTooManyRequests, msg: "too many requests", retryAfter: wait}
+		}
+	}
+
+	a, err := parseArgs(q, get)
+	if err != nil {
+		return nil, &apiError{status: http.StatusBadRequest, msg: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.Timeout)
+	defer cancel()
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, &apiError{status: http.StatusServiceUnavailable, msg: "too busy, try again later"}
+	}
+
+	res, err := s.run(ctx, q, a)
+	switch {
+	case err == nil:
+		return res, nil
+	case errors.Is(err, errNotFound):
+		return nil, &apiError{status: http.StatusNotFound, msg: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return nil, &apiError{status: http.StatusGatewayTimeout, msg: "query timed out"}
+	}
+	if s.Logf != nil {
+		s.Logf("lookingglass: %s query failed: %v", name, err)
+	}
+	return nil, &apiError{status: http.StatusBadGateway, msg: "query failed"}
+}
+
+// run runs q against the Decoder and frees the slot taken for it once the
+// Decoder is done. A plain Decoder cannot be stopped at the deadline, so its
+// query keeps the slot after the client has been answered, until it returns.
+func (s *Server) run(ctx context.Context, q query, a args) (any, error) {
+	if d, ok := s.Decoder.(clidecode.DecoderContext); ok {
+		defer func() { <-s.slots }()
+		return q.run(ctx, d, a)
+	}
+
+	type answer struct {
+		res any
+		err error
+	}
+	done := make(chan answer, 1)
+	go func() {
+		defer func() { <-s.slots }()
+		res, err := q.run(context.WithoutCancel(ctx), clidecode.WithContext(s.Decoder), a)
+		done <- answer{res, err}
+	}()
+	select {
+	case ans := <-done:
+		return ans.res, ans.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Server) clientID(r *http.Request) string {
+	if s.ClientID != nil {
+		return s.ClientID(r)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	addr = addr.Unmap()
+	if addr.Is4() {
+		return addr.String()
+	}
+	prefix, _ := addr.WithZone("").Prefix(s.IPv6Prefix)
+	return prefix.String()
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	res, aerr := s.do(r, r.PathValue("query"), r.URL.Query().Get)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if aerr != nil {
+		if aerr.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((aerr.retryAfter+time.Second-1)/time.Second)))
+		}
+		w.WriteHeader(aerr.status)
+		enc.Encode(map[string]string{"error": aerr.msg})
+		return
+	}
+	enc.Encode(res)
+}
+
+type routeResult struct {
+	IP     string `json:"ip"`
+	Prefix string `json:"prefix"`
+}
+
+func runRoute(ctx context.Context, d clidecode.DecoderContext, a args) (any, error) {
+	route, found, err := d.GetRouteContext(ctx, a.ip)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no route to %s: %w", a.ip, errNotFound)
+	}
+	return routeResult{IP: a.ip.String(), Prefix: route.String()}, nil
+}
+
+type asPathResult struct {
+	IP string `json:"ip"`
+	clidecode.ASPath
+}
+
+func runASPath(ctx context.Context, d clidecode.DecoderContext, a args) (any, error) {
+	path, found, err := d.GetASPathFromIPContext(ctx, a.ip)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no AS path to %s: %w", a.ip, errNotFound)
+	}
+	return asPathResult{IP: a.ip.String(), ASPath: path}, nil
+}
+
+type originResult struct {
+	IP  string `json:"ip"`
+	ASN uint32 `json:"asn"`
+}
+
+func runOrigin(ctx context.Context, d clidecode.DecoderContext, a args) (any, error) {
+	asn, found, err := d.GetOriginFromIPContext(ctx, a.ip)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no origin for %s: %w", a.ip, errNotFound)
+	}
+	return originResult{IP: a.ip.String(), ASN: asn}, nil
+}
+
+type roaResult struct {
+	Prefix string `json:"prefix"`
+	ASN    uint32 `json:"asn"`
+	State  string `json:"state"`
+}
+
+var roaStates = map[int]string{
+	clidecode.RUnknown: "unknown",
+	clidecode.RValid:   "valid",
+	clidecode.RInvalid: "invalid",
+}
+
+func runROA(ctx context.Context, d clidecode.DecoderContext, a args) (any, error) {
+	state, found, err := d.GetROAContext(ctx, a.prefix, a.asn)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no ROA status for %s: %w", a.prefix, errNotFound)
+	}
+	return roaResult{Prefix: a.prefix.String(), ASN: a.asn, State: roaStates[state]}, nil
+}
+
+type prefixesResult struct {
+	ASN  uint32   `json:"asn"`
+	IPv4 []string `json:"ipv4"`
+	IPv6 []string `json:"ipv6"`
+}
+
+func runPrefixes(ctx context.Context, d clidecode.DecoderContext, a args) (any, error) {
+	strs := func(nets []*net.IPNet) []string {
+		s := make([]string, 0, len(nets))
+		for _, n := range nets {
+			s = append(s, n.String())
+		}
+		return s
+	}
+	r := prefixesResult{ASN: a.asn}
+	if a.family != "6" {
+		nets, err := d.GetIPv4FromSourceContext(ctx, a.asn)
+		if err != nil {
+			return nil, err
+		}
+		r.IPv4 = strs(nets)
+	}
+	if a.family != "4" {
+		nets, err := d.GetIPv6FromSourceContext(ctx, a.asn)
+		if err != nil {
+			return nil, err
+		}
+		r.IPv6 = strs(nets)
+	}
+	return r, nil
+}
+
+type vrpsResult struct {
+	ASN  uint32          `json:"asn"`
+	VRPs []clidecode.VRP `json:"vrps"`
+}
+
+func runVRPs(ctx context.Context, d clidecode.DecoderContext, a args) (any, error) {
+	vrps, err := d.GetVRPsContext(ctx, a.asn)
+	if err != nil {
+		return nil, err
+	}
+	if vrps == nil {
+		vrps = []clidecode.VRP{}
+	}
+	return vrpsResult{ASN: a.asn, VRPs: vrps}, nil
+}
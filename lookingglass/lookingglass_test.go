@@ -0,0 +1,281 @@
+package lookingglass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+)
+
+func get(s http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestAPI(t *testing.T) {
+	s := New(clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"))
+	s.Rate = 0
+
+	tests := []struct {
+		target string
+		status int
+		want   string
+	}{
+		{"/api/route?ip=8.8.8.8", 200, `{"ip":"8.8.8.8","prefix":"8.8.8.0/24"}`},
+		{"/api/route?ip=::ffff:8.8.8.8", 200, `{"ip":"8.8.8.8","prefix":"8.8.8.0/24"}`},
+		{"/api/aspath?ip=8.8.8.8", 200, `{"ip":"8.8.8.8","path":[64496,15169]}`},
+		{"/api/origin?ip=2606:4700::1", 200, `{"ip":"2606:4700::1","asn":13335}`},
+		{"/api/origin?ip=203.0.113.1", 404, `{"error":"no origin for 203.0.113.1: not found"}`},
+		{"/api/roa?prefix=192.0.2.0/24&asn=AS64511", 200, `{"prefix":"192.0.2.0/24","asn":64511,"state":"invalid"}`},
+		{"/api/roa?prefix=1.0.0.1/24&asn=13335", 200, `{"prefix":"1.0.0.0/24","asn":13335,"state":"valid"}`},
+		{"/api/prefixes?asn=13335", 200, `{"asn":13335,"ipv4":["1.0.0.0/24"],"ipv6":["2606:4700::/32"]}`},
+		{"/api/prefixes?asn=13335&family=4", 200, `{"asn":13335,"ipv4":["1.0.0.0/24"],"ipv6":null}`},
+		{"/api/vrps?asn=64496", 200, `{"asn":64496,"vrps":[{"prefix":"2001:db8::/32","max_length":32}]}`},
+		{"/api/vrps?asn=64500", 200, `{"asn":64500,"vrps":[]}`},
+
+		{"/api/route", 400, `{"error":"missing ip"}`},
+		{"/api/route?ip=8.8.8", 400, `{"error":"invalid IP address \"8.8.8\""}`},
+		{"/api/roa?prefix=192.0.2.0&asn=1", 400, `{"error":"invalid prefix \"192.0.2.0\""}`},
+		{"/api/vrps?asn=4294967296", 400, `{"error":"invalid ASN \"4294967296\""}`},
+		{"/api/prefixes?asn=1&family=5", 400, `{"error":"invalid family \"5\", expected 4 or 6"}`},
+		{"/api/vrps?asn=" + strings.Repeat("1", 100), 400, `{"error":"asn is too long"}`},
+		{"/api/invalids", 404, `{"error":"unknown query \"invalids\""}`},
+	}
+	for _, test := range tests {
+		w := get(s, test.target)
+		got := strings.Join(strings.Fields(w.Body.String()), "")
+		if w.Code != test.status || got != strings.ReplaceAll(test.want, " ", "") {
+			t.Errorf("%s: got %d %s, want %d %s", test.target, w.Code, got, test.status, test.want)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: Content-Type %q", test.target, ct)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/route?ip=8.8.8.8", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST returned %d", w.Code)
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	s := New(clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"))
+	s.Queries = []string{QueryRoute, QueryROA}
+
+	if w := get(s, "/api/route?ip=8.8.8.8"); w.Code != 200 {
+		t.Errorf("route returned %d", w.Code)
+	}
+	if w := get(s, "/api/vrps?asn=13335"); w.Code != 404 {
+		t.Errorf("disabled vrps returned %d", w.Code)
+	}
+	body := get(s, "/").Body.String()
+	if !strings.Contains(body, `value="roa"`) || strings.Contains(body, `value="vrps"`) {
+		t.Errorf("Expected only the enabled queries on the page:\n%s", body)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := New(clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"))
+	s.Rate, s.Burst = 0.5, 2
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+
+	for i, want := range []int{200, 200, 429} {
+		if w := get(s, "/api/route?ip=8.8.8.8"); w.Code != want {
+			t.Errorf("Request %d returned %d, want %d", i, w.Code, want)
+		} else if want == 429 && w.Header().Get("Retry-After") != "2" {
+			t.Errorf("Retry-After is %q", w.Header().Get("Retry-After"))
+		}
+	}
+
+	// Another client has its own bucket
+	r := httptest.NewRequest(http.MethodGet, "/api/route?ip=8.8.8.8", nil)
+	r.RemoteAddr = "198.51.100.7:4321"
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != 200 {
+		t.Errorf("Other client returned %d", w.Code)
+	}
+
+	now = now.Add(2 * time.Second)
+	if w := get(s, "/api/route?ip=8.8.8.8"); w.Code != 200 {
+		t.Errorf("Request after refill returned %d", w.Code)
+	}
+}
+
+func TestRateLimitIPv6Prefix(t *testing.T) {
+	s := New(clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"))
+	s.Rate, s.Burst = 0.5, 1
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+
+	for _, tt := range []struct {
+		addr string
+		want int
+	}{
+		{"[2001:db8:1:2::1]:4321", 200},
+		{"[2001:db8:1:2:ffff::7]:4321", 429}, // same /64
+		{"[2001:db8:1:3::1]:4321", 200},
+		{"[::ffff:198.51.100.7]:4321", 200},
+		{"198.51.100.7:4321", 429}, // same address
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/api/route?ip=8.8.8.8", nil)
+		r.RemoteAddr = tt.addr
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s returned %d, want %d", tt.addr, w.Code, tt.want)
+		}
+	}
+}
+
+func TestLimiterMaxClients(t *testing.T) {
+	l := newLimiter(1, 1, 2)
+	now := time.Unix(1700000000, 0)
+	for i, client := range []string{"a", "b", "c"} {
+		if ok, _ := l.allow(client, now.Add(time.Duration(i)*time.Millisecond)); !ok {
+			t.Errorf("%s was refused", client)
+		}
+	}
+	if len(l.clients) != 2 {
+		t.Errorf("Tracking %d clients, want 2", len(l.clients))
+	}
+	if _, ok := l.clients["a"]; ok {
+		t.Error("The client seen least recently was kept")
+	}
+	if ok, _ := l.allow("c", now.Add(3*time.Millisecond)); ok {
+		t.Error("c got a second token")
+	}
+}
+
+// blockingDecoder holds GetRoute until released
+type blockingDecoder struct {
+	*clidecode.FakeConn
+	started, release chan struct{}
+}
+
+func (b blockingDecoder) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return b.FakeConn.GetRouteContext(ctx, ip)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	d := blockingDecoder{clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"), make(chan struct{}), make(chan struct{})}
+	s := New(d)
+	s.MaxConcurrent = 1
+	s.Timeout = 50 * time.Millisecond
+	s.Rate = 0
+
+	done := make(chan int)
+	go func() { done <- get(s, "/api/route?ip=8.8.8.8").Code }()
+	<-d.started
+
+	if w := get(s, "/api/origin?ip=8.8.8.8"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while the only slot is taken, got %d", w.Code)
+	}
+	close(d.release)
+	<-done
+
+	if w := get(s, "/api/origin?ip=8.8.8.8"); w.Code != 200 {
+		t.Errorf("Expected the slot to be freed, got %d", w.Code)
+	}
+}
+
+// plainBlockingDecoder holds GetRoute until released, and has no context methods
+type plainBlockingDecoder struct {
+	clidecode.Decoder
+	started, release chan struct{}
+}
+
+func (b plainBlockingDecoder) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return b.Decoder.GetRoute(ip)
+}
+
+func TestConcurrencyLimitPlainDecoder(t *testing.T) {
+	d := plainBlockingDecoder{clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"), make(chan struct{}), make(chan struct{})}
+	s := New(d)
+	s.MaxConcurrent = 1
+	s.Timeout = 50 * time.Millisecond
+	s.Rate = 0
+
+	done := make(chan int)
+	go func() { done <- get(s, "/api/route?ip=8.8.8.8").Code }()
+	<-d.started
+	if code := <-done; code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504 once the query timed out, got %d", code)
+	}
+
+	// GetRoute is still running, so its slot is still taken
+	if w := get(s, "/api/origin?ip=8.8.8.8"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while the timed out query runs, got %d", w.Code)
+	}
+	close(d.release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w := get(s, "/api/origin?ip=8.8.8.8")
+		if w.Code == 200 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the slot to be freed once GetRoute returned, got %d", w.Code)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	f := clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json")
+	f.Errors = map[string]error{"GetOriginFromIP": errors.New("socket: connection refused")}
+	f.Latency = map[string]time.Duration{"GetVRPs": time.Second}
+	s := New(f)
+	s.Timeout = 20 * time.Millisecond
+	var logged []string
+	s.Logf = func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) }
+
+	w := get(s, "/api/origin?ip=8.8.8.8")
+	if w.Code != http.StatusBadGateway || strings.Contains(w.Body.String(), "socket") {
+		t.Errorf("Expected a 502 hiding the cause, got %d %s", w.Code, w.Body)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], "connection refused") {
+		t.Errorf("Expected the cause to be logged, got %q", logged)
+	}
+
+	if w := get(s, "/api/vrps?asn=13335"); w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected a 504, got %d", w.Code)
+	}
+}
+
+func TestUI(t *testing.T) {
+	s := New(clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"))
+
+	w := get(s, "/")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "<h1>Looking Glass</h1>") {
+		t.Errorf("Unexpected page %d:\n%s", w.Code, w.Body)
+	}
+
+	w = get(s, "/?q=route&ip=8.8.8.8")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "8.8.8.0/24") || !strings.Contains(w.Body.String(), `value="8.8.8.8"`) {
+		t.Errorf("Unexpected result page %d:\n%s", w.Code, w.Body)
+	}
+
+	w = get(s, "/?q=route&ip=%3Cscript%3E")
+	if w.Code != 400 || strings.Contains(w.Body.String(), "<script>") {
+		t.Errorf("Expected escaped error page, got %d:\n%s", w.Code, w.Body)
+	}
+
+	if w := get(s, "/nonexistent"); w.Code != 404 {
+		t.Errorf("Unknown page returned %d", w.Code)
+	}
+}
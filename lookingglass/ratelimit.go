@@ -0,0 +1,85 @@
+package lookingglass
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket per client, for up to max clients
+type limiter struct {
+	rate  float64 // tokens per second
+	burst float64
+	max   int
+
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst, max int) *limiter {
+	return &limiter{rate: rate, burst: float64(burst), max: max, clients: make(map[string]*bucket)}
+}
+
+// allow takes a token from the bucket of client. If there is none, it
+// returns how long until there will be.
+func (l *limiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > time.Minute {
+		l.sweep(now)
+	}
+
+	b, ok := l.clients[client]
+	if !ok {
+		if len(l.clients) >= l.max {
+			l.sweep(now)
+			l.evict()
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+	b.tokens = l.fill(b, now)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration(math.Ceil((1 - b.tokens) / l.rate * float64(time.Second)))
+	return false, wait
+}
+
+// sweep forgets clients whose bucket has filled up again
+func (l *limiter) sweep(now time.Time) {
+	for k, b := range l.clients {
+		if l.fill(b, now) >= l.burst {
+			delete(l.clients, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+// evict forgets the clients seen least recently until there is room for another
+func (l *limiter) evict() {
+	for len(l.clients) >= l.max {
+		var oldest string
+		var at time.Time
+		for k, b := range l.clients {
+			if at.IsZero() || b.last.Before(at) {
+				oldest, at = k, b.last
+			}
+		}
+		delete(l.clients, oldest)
+	}
+}
+
+// fill returns the tokens in b at now
+func (l *limiter) fill(b *bucket, now time.Time) float64 {
+	return math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+}
@@ -0,0 +1,88 @@
+package lookingglass
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+)
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Looking Glass</title>
+<style>
+body { font-family: sans-serif; max-width: 48em; margin: 2em auto; padding: 0 1em; }
+form { margin: 0.5em 0; }
+label { display: inline-block; width: 10em; }
+input { width: 14em; }
+pre { background: #f4f4f4; padding: 1em; overflow-x: auto; }
+.error { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Looking Glass</h1>
+{{range .Queries}}
+<form method="get" action="/">
+<input type="hidden" name="q" value="{{.Name}}">
+<label>{{.Label}}</label>
+{{range .Params}}<input name="{{.Name}}" placeholder="{{.Placeholder}}" value="{{index $.Values .Name}}"{{if not .Optional}} required{{end}}> {{end}}
+<button type="submit">Look up</button>
+</form>
+{{end}}
+{{if .Query}}
+<h2>{{.Query}}</h2>
+{{if .Error}}<p class="error">{{.Error}}</p>{{else}}<pre>{{.Result}}</pre>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// pageQuery is a query form of the page
+type pageQuery struct {
+	Name, Label string
+	Params      []param
+}
+
+type pageData struct {
+	Queries []pageQuery
+	Values  map[string]string
+
+	// The query run, if any
+	Query  string
+	Result string
+	Error  string
+}
+
+// serveUI serves the page, with the result of a query if one was submitted
+func (s *Server) serveUI(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	data := pageData{Values: make(map[string]string)}
+	for _, q := range s.enabled() {
+		data.Queries = append(data.Queries, pageQuery{Name: q.name, Label: q.label, Params: q.params})
+	}
+
+	status := http.StatusOK
+	if name := values.Get("q"); name != "" {
+		data.Query = name
+		for _, p := range []param{ipParam, prefixParam, asnParam, familyParam} {
+			if v := values.Get(p.Name); len(v) <= maxParamLength {
+				data.Values[p.Name] = v
+			}
+		}
+		res, aerr := s.do(r, name, values.Get)
+		if aerr != nil {
+			status = aerr.status
+			data.Error = aerr.msg
+		} else {
+			b, _ := json.MarshalIndent(res, "", "  ")
+			data.Result = string(b)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	page.Execute(w, data)
+}
@@ -0,0 +1,543 @@
+package clidecode
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+)
+
+// MRT record types and TABLE_DUMP_V2 subtypes, from RFC 6396 and RFC 8050
+const (
+	mrtTableDumpV2 = 13
+
+	mrtPeerIndexTable      = 1
+	mrtRIBIPv4Unicast      = 2
+	mrtRIBIPv4Multicast    = 3
+	mrtRIBIPv6Unicast      = 4
+	mrtRIBIPv6Multicast    = 5
+	mrtRIBIPv4UnicastAdd   = 8
+	mrtRIBIPv4MulticastAdd = 9
+	mrtRIBIPv6UnicastAdd   = 10
+	mrtRIBIPv6MulticastAdd = 11
+)
+
+// BGP path attribute types read from RIB entries
+const (
+	attrASPath         = 2
+	attrLargeCommunity = 32
+)
+
+// ErrMRTFormat is returned for MRT data that cannot be decoded.
+var ErrMRTFormat = errors.New("malformed MRT data")
+
+// mrtMaxRecord bounds the length of a record. The largest RIB records of a
+// full table, listing the paths of every peer for a prefix, are well below it.
+const mrtMaxRecord = 16 << 20
+
+// MRTPeer is a peer listed in the PEER_INDEX_TABLE of an MRT dump.
+type MRTPeer struct {
+	BGPID   net.IP
+	Address net.IP
+	AS      uint32
+}
+
+// MRTDecoder answers Decoder queries from a TABLE_DUMP_V2 MRT file, such as a
+// RIPE RIS or RouteViews RIB dump or one written by BIRD's mrt protocol.
+// The first unicast route listed for a prefix is taken as its primary route, and
+// only that one is kept in memory, with a count of the paths from every peer.
+// Other records are skipped.
+//
+// ROA queries use the VRPs given to SetROAs or SetValidator. Until then every
+// route is unknown.
+type MRTDecoder struct {
+	View  string
+	Peers []MRTPeer
+
+	rib rib
+
+	// peerRoutes records which address families each peer sent routes for
+	peerRoutes []struct{ v4, v6 bool }
+}
+
+var _ Decoder = (*MRTDecoder)(nil)
+var _ DecoderContext = (*MRTDecoder)(nil)
+
+// LoadMRT reads an MRT file, which may be compressed with gzip or bzip2
+func LoadMRT(path string) (*MRTDecoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := ReadMRT(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ReadMRT reads MRT records from r until EOF.
+// Compressed input is detected from its magic number.
+func ReadMRT(r io.Reader) (*MRTDecoder, error) {
+	br := bufio.NewReaderSize(r, 1<<16)
+	magic, _ := br.Peek(3)
+	var in io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		in = bufio.NewReaderSize(zr, 1<<16)
+	case bytes.Equal(magic, []byte("BZh")):
+		in = bufio.NewReaderSize(bzip2.NewReader(br), 1<<16)
+	}
+
+	m := &MRTDecoder{}
+	var header [12]byte
+	var body []byte
+	for {
+		if _, err := io.ReadFull(in, header[:]); err != nil {
+			if err == io.EOF {
+				return m, nil
+			}
+			return nil, fmt.Errorf("%w: truncated record header", ErrMRTFormat)
+		}
+		typ := binary.BigEndian.Uint16(header[4:])
+		subtype := binary.BigEndian.Uint16(header[6:])
+		length := binary.BigEndian.Uint32(header[8:])
+		if length > mrtMaxRecord {
+			return nil, fmt.Errorf("%w: record of %d bytes", ErrMRTFormat, length)
+		}
+
+		if cap(body) < int(length) {
+			body = make([]byte, length)
+		}
+		body = body[:length]
+		if _, err := io.ReadFull(in, body); err != nil {
+			return nil, fmt.Errorf("%w: truncated record body", ErrMRTFormat)
+		}
+		if typ != mrtTableDumpV2 {
+			continue
+		}
+
+		var err error
+		switch subtype {
+		case mrtPeerIndexTable:
+			err = m.readPeerIndex(body)
+		case mrtRIBIPv4Unicast, mrtRIBIPv6Unicast:
+			err = m.readRIB(body, subtype == mrtRIBIPv4Unicast, false)
+		case mrtRIBIPv4UnicastAdd, mrtRIBIPv6UnicastAdd:
+			err = m.readRIB(body, subtype == mrtRIBIPv4UnicastAdd, true)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// wireBuffer reads big-endian fields, remembering whether it ran short.
+type wireBuffer struct {
+	b   []byte
+	bad bool
+}
+
+// next returns the next n bytes. Once the buffer has run short it returns nil,
+// as n may come from the wire and cannot be trusted.
+func (b *wireBuffer) next(n int) []byte {
+	if b.bad || n < 0 || n > len(b.b) {
+		b.bad = true
+		return nil
+	}
+	out := b.b[:n]
+	b.b = b.b[n:]
+	return out
+}
+
+func (b *wireBuffer) uint8() uint8 {
+	if v := b.next(1); v != nil {
+		return v[0]
+	}
+	return 0
+}
+
+func (b *wireBuffer) uint16() uint16 {
+	if v := b.next(2); v != nil {
+		return binary.BigEndian.Uint16(v)
+	}
+	return 0
+}
+
+func (b *wireBuffer) uint32() uint32 {
+	if v := b.next(4); v != nil {
+		return binary.BigEndian.Uint32(v)
+	}
+	return 0
+}
+
+// readPeerIndex decodes a PEER_INDEX_TABLE record.
+func (m *MRTDecoder) readPeerIndex(body []byte) error {
+	b := &wireBuffer{b: body}
+	b.next(4) // collector BGP ID
+	m.View = string(b.next(int(b.uint16())))
+	count := int(b.uint16())
+	if b.bad {
+		return fmt.Errorf("%w: truncated peer index table", ErrMRTFormat)
+	}
+
+	m.Peers = make([]MRTPeer, 0, count)
+	for range count {
+		typ := b.uint8()
+		p := MRTPeer{BGPID: net.IP(append([]byte(nil), b.next(4)...))}
+		if typ&0x01 != 0 {
+			p.Address = net.IP(append([]byte(nil), b.next(16)...))
+		} else {
+			p.Address = net.IP(append([]byte(nil), b.next(4)...))
+		}
+		if typ&0x02 != 0 {
+			p.AS = b.uint32()
+		} else {
+			p.AS = uint32(b.uint16())
+		}
+		m.Peers = append(m.Peers, p)
+	}
+	if b.bad {
+		return fmt.Errorf("%w: truncated peer index table", ErrMRTFormat)
+	}
+	m.peerRoutes = make([]struct{ v4, v6 bool }, count)
+	return nil
+}
+
+// readRIB decodes a RIB_IPV4_UNICAST or RIB_IPV6_UNICAST record, with path
+// identifiers if addPath is set.
+func (m *MRTDecoder) readRIB(body []byte, v4, addPath bool) error {
+	b := &wireBuffer{b: body}
+	b.next(4) // sequence number
+	bits := int(b.uint8())
+	size := 16
+	if v4 {
+		size = 4
+	}
+	if bits > size*8 {
+		return fmt.Errorf("%w: prefix length %d", ErrMRTFormat, bits)
+	}
+	raw := make([]byte, size)
+	copy(raw, b.next((bits+7)/8))
+	addr, _ := netip.AddrFromSlice(raw)
+	prefix := netip.PrefixFrom(addr, bits).Masked()
+
+	count := int(b.uint16())
+	for range count {
+		peer := int(b.uint16())
+		b.next(4) // originated time
+		if addPath {
+			b.next(4) // path identifier
+		}
+		attrs := b.next(int(b.uint16()))
+		if b.bad {
+			break
+		}
+		r, err := mrtRoute(prefix, attrs)
+		if err != nil {
+			return err
+		}
+		m.rib.add(r)
+		if peer < len(m.peerRoutes) {
+			if v4 {
+				m.peerRoutes[peer].v4 = true
+			} else {
+				m.peerRoutes[peer].v6 = true
+			}
+		}
+	}
+	if b.bad {
+		return fmt.Errorf("%w: truncated RIB entry for %s", ErrMRTFormat, prefix)
+	}
+	return nil
+}
+
+// mrtRoute decodes the path attributes of a RIB entry.
+// TABLE_DUMP_V2 always encodes AS_PATH with four octet ASNs.
+func mrtRoute(prefix netip.Prefix, attrs []byte) (ribRoute, error) {
+	r := ribRoute{prefix: prefix}
+	b := &wireBuffer{b: attrs}
+	for len(b.b) > 0 && !b.bad {
+		flags := b.uint8()
+		typ := b.uint8()
+		var length int
+		if flags&0x10 != 0 {
+			length = int(b.uint16())
+		} else {
+			length = int(b.uint8())
+		}
+		value := b.next(length)
+		if b.bad {
+			break
+		}
+
+		switch typ {
+		case attrASPath:
+			seg := &wireBuffer{b: value}
+			for len(seg.b) > 0 && !seg.bad {
+				segType := seg.uint8()
+				asns := make([]uint32, seg.uint8())
+				for i := range asns {
+					asns[i] = seg.uint32()
+				}
+				r.addSegment(uint32(segType), asns)
+			}
+			if seg.bad {
+				return r, fmt.Errorf("%w: bad AS_PATH for %s", ErrMRTFormat, prefix)
+			}
+		case attrLargeCommunity:
+			r.large = length > 0
+		}
+	}
+	if b.bad {
+		return r, fmt.Errorf("%w: truncated attributes for %s", ErrMRTFormat, prefix)
+	}
+	return r, nil
+}
+
+// SetROAs sets the VRPs used to validate routes.
+func (m *MRTDecoder) SetROAs(roas []ROA) {
+	m.SetValidator(NewValidator(roas))
+}
+
+// SetValidator sets the Validator used to validate routes.
+func (m *MRTDecoder) SetValidator(v *Validator) {
+	m.rib.validator = v
+}
+
+// GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
+func (m *MRTDecoder) GetBGPTotal() (Totals, error) {
+	return m.GetBGPTotalContext(context.Background())
+}
+
+// GetBGPTotalContext is like GetBGPTotal but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetBGPTotalContext(ctx context.Context) (Totals, error) {
+	if err := ctx.Err(); err != nil {
+		return Totals{}, err
+	}
+	return m.rib.totals(), nil
+}
+
+// GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
+// Every peer in the dump is configured, and established for each address family it
+// sent routes for. Peers that sent nothing are classified by their address.
+func (m *MRTDecoder) GetPeers() (Peers, error) {
+	return m.GetPeersContext(context.Background())
+}
+
+// GetPeersContext is like GetPeers but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetPeersContext(ctx context.Context) (Peers, error) {
+	if err := ctx.Err(); err != nil {
+		return Peers{}, err
+	}
+	var p Peers
+	for i, peer := range m.Peers {
+		sent := m.peerRoutes[i]
+		if !sent.v4 && !sent.v6 {
+			if peer.Address.To4() != nil {
+				p.V4c++
+			} else {
+				p.V6c++
+			}
+			continue
+		}
+		if sent.v4 {
+			p.V4c++
+			p.V4e++
+		}
+		if sent.v6 {
+			p.V6c++
+			p.V6e++
+		}
+	}
+	return p, nil
+}
+
+// GetTotalSourceASNs returns total amount of unique ASNs
+func (m *MRTDecoder) GetTotalSourceASNs() (ASNs, error) {
+	return m.GetTotalSourceASNsContext(context.Background())
+}
+
+// GetTotalSourceASNsContext is like GetTotalSourceASNs but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetTotalSourceASNsContext(ctx context.Context) (ASNs, error) {
+	if err := ctx.Err(); err != nil {
+		return ASNs{}, err
+	}
+	return m.rib.sourceASNs(), nil
+}
+
+// GetMasks returns the total count of each mask value
+// First item is IPv4, second item is IPv6
+func (m *MRTDecoder) GetMasks() ([]map[string]uint32, error) {
+	return m.GetMasksContext(context.Background())
+}
+
+// GetMasksContext is like GetMasks but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.rib.masks(), nil
+}
+
+// GetROAs returns total amount of all ROA states
+func (m *MRTDecoder) GetROAs() (Roas, error) {
+	return m.GetROAsContext(context.Background())
+}
+
+// GetROAsContext is like GetROAs but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetROAsContext(ctx context.Context) (Roas, error) {
+	if err := ctx.Err(); err != nil {
+		return Roas{}, err
+	}
+	return m.rib.roas(), nil
+}
+
+// GetLargeCommunities returns the amount of prefixes that have large communities attached (RFC8092)
+func (m *MRTDecoder) GetLargeCommunities() (Large, error) {
+	return m.GetLargeCommunitiesContext(context.Background())
+}
+
+// GetLargeCommunitiesContext is like GetLargeCommunities but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetLargeCommunitiesContext(ctx context.Context) (Large, error) {
+	if err := ctx.Err(); err != nil {
+		return Large{}, err
+	}
+	return m.rib.large(), nil
+}
+
+// GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN.
+func (m *MRTDecoder) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
+	return m.GetIPv4FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv4FromSourceContext is like GetIPv4FromSource but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.rib.fromSource(asn, true), nil
+}
+
+// GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN.
+func (m *MRTDecoder) GetIPv6FromSource(asn uint32) ([]*net.IPNet, error) {
+	return m.GetIPv6FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv6FromSourceContext is like GetIPv6FromSource but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.rib.fromSource(asn, false), nil
+}
+
+// GetOriginFromIP will return the origin ASN from a source IP.
+func (m *MRTDecoder) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
+	return m.GetOriginFromIPContext(context.Background(), ip)
+}
+
+// GetOriginFromIPContext is like GetOriginFromIP but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+	r, found := m.rib.lookup(ip)
+	if !found {
+		return 0, false, nil
+	}
+	return r.origin(), true, nil
+}
+
+// GetASPathFromIP will return the AS path, as well as as-set if any from a source IP.
+func (m *MRTDecoder) GetASPathFromIP(ip net.IP) (ASPath, bool, error) {
+	return m.GetASPathFromIPContext(context.Background(), ip)
+}
+
+// GetASPathFromIPContext is like GetASPathFromIP but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetASPathFromIPContext(ctx context.Context, ip net.IP) (ASPath, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return ASPath{}, false, err
+	}
+	r, found := m.rib.lookup(ip)
+	if !found {
+		return ASPath{}, false, nil
+	}
+	return ASPath{Path: r.asPath, Set: r.asSet}, true, nil
+}
+
+// GetRoute will return the longest matching prefix, if any, for a source IP.
+func (m *MRTDecoder) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
+	return m.GetRouteContext(context.Background(), ip)
+}
+
+// GetRouteContext is like GetRoute but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	r, found := m.rib.lookup(ip)
+	if !found {
+		return nil, false, nil
+	}
+	return IPNetFromPrefix(r.prefix), true, nil
+}
+
+// GetROA will return the ROA status of a prefix announced by an ASN.
+// It is not found if no VRPs have been set.
+func (m *MRTDecoder) GetROA(prefix *net.IPNet, asn uint32) (int, bool, error) {
+	return m.GetROAContext(context.Background(), prefix, asn)
+}
+
+// GetROAContext is like GetROA but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+	p, ok := PrefixFromIPNet(prefix)
+	if !ok || m.rib.validator == nil {
+		return 0, false, nil
+	}
+	return m.rib.validator.Validate(p, asn), true, nil
+}
+
+// GetVRPs will return all Validated ROA Payloads for an ASN.
+func (m *MRTDecoder) GetVRPs(asn uint32) ([]VRP, error) {
+	return m.GetVRPsContext(context.Background(), asn)
+}
+
+// GetVRPsContext is like GetVRPs but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetVRPsContext(ctx context.Context, asn uint32) ([]VRP, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return vrpsFor(m.rib.validator, asn), nil
+}
+
+// GetInvalids returns a map of ASNs that are advertising RPKI invalid prefixes.
+// It also includes all those prefixes being advertised.
+func (m *MRTDecoder) GetInvalids() (map[string][]string, error) {
+	return m.GetInvalidsContext(context.Background())
+}
+
+// GetInvalidsContext is like GetInvalids but honours the cancellation and deadline of ctx
+func (m *MRTDecoder) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.rib.invalids(), nil
+}
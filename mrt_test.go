@@ -0,0 +1,151 @@
+package clidecode
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/netip"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMRTDecoder(t *testing.T) {
+	m, err := LoadMRT("testdata/mrt/rib.mrt.gz")
+	if err != nil {
+		t.Fatalf("LoadMRT failed: %v", err)
+	}
+	if m.View != "rrc-test" || len(m.Peers) != 3 || m.Peers[2].AS != 64498 || !m.Peers[1].Address.Equal(net.ParseIP("2001:db8::2")) {
+		t.Errorf("Unexpected peer index %q %+v", m.View, m.Peers)
+	}
+
+	totals, _ := m.GetBGPTotal()
+	if totals != (Totals{V4Rib: 5, V4Fib: 4, V6Rib: 3, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+
+	// The IPv6 peer also sent an IPv4 route, and the last peer sent nothing
+	peers, _ := m.GetPeers()
+	if peers != (Peers{V4c: 3, V4e: 2, V6c: 1, V6e: 1}) {
+		t.Errorf("Unexpected peers %+v", peers)
+	}
+
+	asns, _ := m.GetTotalSourceASNs()
+	if asns != (ASNs{As4: 3, As6: 2, As10: 3, As4Only: 1, AsBoth: 2}) {
+		t.Errorf("Unexpected ASNs %+v", asns)
+	}
+
+	masks, _ := m.GetMasks()
+	if !reflect.DeepEqual(masks, []map[string]uint32{{"9": 1, "24": 3}, {"32": 1, "48": 1}}) {
+		t.Errorf("Unexpected masks %v", masks)
+	}
+
+	large, _ := m.GetLargeCommunities()
+	if large != (Large{V4: 1}) {
+		t.Errorf("Unexpected large communities %+v", large)
+	}
+
+	for ip, want := range map[string]string{"8.8.8.8": "8.8.8.0/24", "8.8.4.4": "8.0.0.0/9", "2001:db8:100::1": "2001:db8:100::/48"} {
+		route, found, _ := m.GetRoute(net.ParseIP(ip))
+		if !found || route.String() != want {
+			t.Errorf("Expected %s for %s, got %v", want, ip, route)
+		}
+	}
+	if _, found, _ := m.GetRoute(net.ParseIP("192.0.2.1")); found {
+		t.Error("Expected no route for 192.0.2.1")
+	}
+
+	path, found, _ := m.GetASPathFromIP(net.ParseIP("198.51.100.1"))
+	if !found || !reflect.DeepEqual(path, ASPath{Path: []uint32{64496}, Set: []uint32{64501, 64502}}) {
+		t.Errorf("Unexpected AS path %+v", path)
+	}
+	origin, _, _ := m.GetOriginFromIP(net.ParseIP("8.8.8.8"))
+	if origin != 15169 {
+		t.Errorf("Expected origin 15169, got %d", origin)
+	}
+
+	nets, _ := m.GetIPv4FromSource(15169)
+	if len(nets) != 1 || nets[0].String() != "8.8.8.0/24" {
+		t.Errorf("Unexpected networks %v", nets)
+	}
+	nets, _ = m.GetIPv6FromSource(64511)
+	if len(nets) != 1 || nets[0].String() != "2001:db8:100::/48" {
+		t.Errorf("Unexpected networks %v", nets)
+	}
+
+	_, prefix, _ := net.ParseCIDR("203.0.113.0/24")
+	if _, found, _ := m.GetROA(prefix, 64511); found {
+		t.Error("Expected no ROA state before VRPs are set")
+	}
+
+	m.SetROAs([]ROA{
+		{Prefix: netip.MustParsePrefix("8.8.8.0/24"), MaxLength: 24, ASN: 15169},
+		{Prefix: netip.MustParsePrefix("203.0.113.0/24"), MaxLength: 24, ASN: 64500},
+		{Prefix: netip.MustParsePrefix("2001:4860::/32"), MaxLength: 48, ASN: 15169},
+	})
+	roas, _ := m.GetROAs()
+	if roas != (Roas{V4v: 1, V4i: 1, V4u: 2, V6v: 1, V6u: 1}) {
+		t.Errorf("Unexpected ROAs %+v", roas)
+	}
+	if status, found, _ := m.GetROA(prefix, 64511); !found || status != RInvalid {
+		t.Errorf("Expected invalid, got %d", status)
+	}
+	invalids, _ := m.GetInvalids()
+	if !reflect.DeepEqual(invalids, map[string][]string{"64511": {"203.0.113.0/24"}}) {
+		t.Errorf("Unexpected invalids %v", invalids)
+	}
+	vrps, _ := m.GetVRPs(64500)
+	if len(vrps) != 1 || vrps[0].Prefix.String() != "203.0.113.0/24" {
+		t.Errorf("Unexpected VRPs %+v", vrps)
+	}
+}
+
+func TestReadMRTCompression(t *testing.T) {
+	for _, path := range []string{"testdata/mrt/rib.mrt", "testdata/mrt/rib.mrt.gz", "testdata/mrt/rib.mrt.bz2"} {
+		m, err := LoadMRT(path)
+		if err != nil {
+			t.Errorf("LoadMRT(%s) failed: %v", path, err)
+			continue
+		}
+		if totals, _ := m.GetBGPTotal(); totals.V4Rib != 5 || totals.V6Rib != 3 {
+			t.Errorf("Unexpected totals %+v from %s", totals, path)
+		}
+	}
+}
+
+func TestReadMRTTruncated(t *testing.T) {
+	data, err := os.ReadFile("testdata/mrt/rib.mrt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{6, 100, len(data) - 1} {
+		if _, err := ReadMRT(bytes.NewReader(data[:n])); !errors.Is(err, ErrMRTFormat) {
+			t.Errorf("Expected ErrMRTFormat reading %d bytes, got %v", n, err)
+		}
+	}
+}
+
+func TestReadMRTRecordTooLarge(t *testing.T) {
+	// A TABLE_DUMP_V2 header claiming a 4 GiB body
+	header := []byte{0, 0, 0, 0, 0, 13, 0, 2, 0xff, 0xff, 0xff, 0xff}
+	if _, err := ReadMRT(bytes.NewReader(header)); !errors.Is(err, ErrMRTFormat) {
+		t.Errorf("Expected ErrMRTFormat, got %v", err)
+	}
+}
+
+func TestMRTRouteConfed(t *testing.T) {
+	// AS_CONFED_SEQUENCE 65001, AS_SEQUENCE 64496 15169, AS_CONFED_SET 65002
+	attrs := []byte{
+		0x40, attrASPath, 22,
+		3, 1, 0, 0, 0xfd, 0xe9,
+		2, 2, 0, 0, 0xfb, 0xf0, 0, 0, 0x3b, 0x41,
+		4, 1, 0, 0, 0xfd, 0xea,
+	}
+	r, err := mrtRoute(netip.MustParsePrefix("8.8.8.0/24"), attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.asPath, []uint32{64496, 15169}) || len(r.asSet) != 0 || r.origin() != 15169 {
+		t.Errorf("Expected the confederation segments to be dropped, got path %v set %v", r.asPath, r.asSet)
+	}
+}
@@ -0,0 +1,197 @@
+package clidecode
+
+import (
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// DecoderV2 mirrors Decoder using net/netip types, which are comparable and
+// can be used directly as map keys.
+// Wrap an existing Decoder with NewDecoderV2 to move over gradually.
+type DecoderV2 interface {
+	// GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
+	GetBGPTotal() (Totals, error)
+
+	// GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
+	GetPeers() (Peers, error)
+
+	// GetTotalSourceASNs returns total amount of unique ASNs
+	GetTotalSourceASNs() (ASNs, error)
+
+	// GetMasks returns the total count of each mask value
+	// First item is IPv4, second item is IPv6
+	GetMasks() ([]map[string]uint32, error)
+
+	// GetROAs returns total amount of all ROA states
+	GetROAs() (Roas, error)
+
+	// GetLargeCommunities returns the amount of prefixes that have large communities attached (RFC8092)
+	GetLargeCommunities() (Large, error)
+
+	// GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN.
+	GetIPv4FromSource(uint32) ([]netip.Prefix, error)
+
+	// GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN.
+	GetIPv6FromSource(uint32) ([]netip.Prefix, error)
+
+	// GetOriginFromIP will return the origin ASN from a source IP.
+	GetOriginFromIP(netip.Addr) (uint32, bool, error)
+
+	// GetASPathFromIP will return the AS path, as well as as-set if any from a source IP.
+	GetASPathFromIP(netip.Addr) (ASPath, bool, error)
+
+	// GetRoute will return the current FIB entry, if any, from a source IP.
+	GetRoute(netip.Addr) (netip.Prefix, bool, error)
+
+	// GetROA will return the ROA status, if any, from a prefix and ASN.
+	GetROA(netip.Prefix, uint32) (int, bool, error)
+
+	// GetVRPs will return all Validated ROA Payloads for an ASN.
+	GetVRPs(uint32) ([]VRPV2, error)
+
+	// GetInvalids returns the prefixes with an RPKI invalid origin, keyed by the originating ASN.
+	GetInvalids() (map[uint32][]netip.Prefix, error)
+}
+
+// VRPV2 is a VRP holding its prefix as a netip.Prefix.
+type VRPV2 struct {
+	Prefix netip.Prefix
+	Max    int
+}
+
+// NewDecoderV2 returns a DecoderV2 backed by d, converting to and from net/netip types.
+func NewDecoderV2(d Decoder) DecoderV2 {
+	return decoderV2{d}
+}
+
+// decoderV2 adapts a Decoder to DecoderV2.
+// Methods whose results need no conversion are promoted from the embedded Decoder.
+type decoderV2 struct {
+	Decoder
+}
+
+var _ DecoderV2 = decoderV2{}
+
+func (d decoderV2) GetIPv4FromSource(asn uint32) ([]netip.Prefix, error) {
+	nets, err := d.Decoder.GetIPv4FromSource(asn)
+	return prefixesFromIPNets(nets), err
+}
+
+func (d decoderV2) GetIPv6FromSource(asn uint32) ([]netip.Prefix, error) {
+	nets, err := d.Decoder.GetIPv6FromSource(asn)
+	return prefixesFromIPNets(nets), err
+}
+
+func (d decoderV2) GetOriginFromIP(ip netip.Addr) (uint32, bool, error) {
+	return d.Decoder.GetOriginFromIP(IPFromAddr(ip))
+}
+
+func (d decoderV2) GetASPathFromIP(ip netip.Addr) (ASPath, bool, error) {
+	return d.Decoder.GetASPathFromIP(IPFromAddr(ip))
+}
+
+func (d decoderV2) GetRoute(ip netip.Addr) (netip.Prefix, bool, error) {
+	n, found, err := d.Decoder.GetRoute(IPFromAddr(ip))
+	if err != nil || !found {
+		return netip.Prefix{}, found, err
+	}
+	p, ok := PrefixFromIPNet(n)
+	return p, ok, nil
+}
+
+func (d decoderV2) GetROA(prefix netip.Prefix, asn uint32) (int, bool, error) {
+	if !prefix.IsValid() {
+		return 0, false, nil
+	}
+	return d.Decoder.GetROA(IPNetFromPrefix(prefix), asn)
+}
+
+func (d decoderV2) GetVRPs(asn uint32) ([]VRPV2, error) {
+	vrps, err := d.Decoder.GetVRPs(asn)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]VRPV2, 0, len(vrps))
+	for _, v := range vrps {
+		if p, ok := PrefixFromIPNet(v.Prefix); ok {
+			out = append(out, VRPV2{Prefix: p, Max: v.Max})
+		}
+	}
+	return out, nil
+}
+
+func (d decoderV2) GetInvalids() (map[uint32][]netip.Prefix, error) {
+	invalids, err := d.Decoder.GetInvalids()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uint32][]netip.Prefix, len(invalids))
+	for asn, prefixes := range invalids {
+		n, err := strconv.ParseUint(asn, 10, 32)
+		if err != nil {
+			continue
+		}
+		for _, s := range prefixes {
+			if p, err := netip.ParsePrefix(s); err == nil {
+				out[uint32(n)] = append(out[uint32(n)], p)
+			}
+		}
+	}
+	return out, nil
+}
+
+// PrefixFromIPNet converts n to a netip.Prefix.
+// IPv4 networks are returned as IPv4 even when n holds a 16-byte address.
+func PrefixFromIPNet(n *net.IPNet) (netip.Prefix, bool) {
+	if n == nil {
+		return netip.Prefix{}, false
+	}
+	addr, ok := netip.AddrFromSlice(n.IP)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	ones, bits := n.Mask.Size()
+	if bits == 0 {
+		// Non-canonical mask
+		return netip.Prefix{}, false
+	}
+	if addr.Is4In6() && bits == 32 {
+		addr = addr.Unmap()
+	}
+	return netip.PrefixFrom(addr, ones).Masked(), true
+}
+
+// IPNetFromPrefix converts p to a *net.IPNet, or nil if p is not valid.
+func IPNetFromPrefix(p netip.Prefix) *net.IPNet {
+	if !p.IsValid() {
+		return nil
+	}
+	p = p.Masked()
+	return &net.IPNet{
+		IP:   net.IP(p.Addr().AsSlice()),
+		Mask: net.CIDRMask(p.Bits(), p.Addr().BitLen()),
+	}
+}
+
+// IPFromAddr converts a to a net.IP, or nil if a is not valid.
+func IPFromAddr(a netip.Addr) net.IP {
+	if !a.IsValid() {
+		return nil
+	}
+	return net.IP(a.AsSlice())
+}
+
+// prefixesFromIPNets converts nets, dropping any that cannot be represented.
+func prefixesFromIPNets(nets []*net.IPNet) []netip.Prefix {
+	if nets == nil {
+		return nil
+	}
+	out := make([]netip.Prefix, 0, len(nets))
+	for _, n := range nets {
+		if p, ok := PrefixFromIPNet(n); ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
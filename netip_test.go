@@ -0,0 +1,87 @@
+package clidecode
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestDecoderV2(t *testing.T) {
+	responses := map[string]string{
+		"show route primary for 8.8.8.8 table master4": `Table master4:
+8.8.8.0/24           unreachable [BGP3v4 2025-11-19 from 192.110.255.57] * (100) [AS15169i]`,
+		"show route primary table master4 where bgp_path ~ [= * 15169 =]": `Table master4:
+8.8.8.0/24           unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS15169i]
+8.8.4.0/24           unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS15169i]`,
+		"eval roa_check(roa_v6, 2001:db8::/32, 64496)": `(enum 35)2`,
+		"show route primary table master4 where roa_check(roa_v4) = ROA_INVALID": `Table master4:
+192.0.2.0/24         unicast [bgp1 2025-11-19 from 198.51.100.1] * (100) [AS64496i]`,
+		"show route primary table master6 where roa_check(roa_v6) = ROA_INVALID": `Table master6:`,
+	}
+
+	d := NewDecoderV2(&BirdClient{Querier: mockQuerier(responses)})
+
+	route, found, err := d.GetRoute(netip.MustParseAddr("8.8.8.8"))
+	if err != nil || !found {
+		t.Fatalf("GetRoute returned %v, %v", found, err)
+	}
+	if route != netip.MustParsePrefix("8.8.8.0/24") {
+		t.Errorf("Expected 8.8.8.0/24, got %s", route)
+	}
+
+	prefixes, err := d.GetIPv4FromSource(15169)
+	if err != nil {
+		t.Fatalf("GetIPv4FromSource failed: %v", err)
+	}
+	want := []netip.Prefix{netip.MustParsePrefix("8.8.8.0/24"), netip.MustParsePrefix("8.8.4.0/24")}
+	if !reflect.DeepEqual(prefixes, want) {
+		t.Errorf("Expected %v, got %v", want, prefixes)
+	}
+
+	status, found, err := d.GetROA(netip.MustParsePrefix("2001:db8::/32"), 64496)
+	if err != nil || !found || status != RInvalid {
+		t.Errorf("GetROA returned %d, %v, %v", status, found, err)
+	}
+
+	status, found, err = d.GetROA(netip.Prefix{}, 64496)
+	if err != nil || found || status != RUnknown {
+		t.Errorf("GetROA of the zero prefix returned %d, %v, %v", status, found, err)
+	}
+
+	invalids, err := d.GetInvalids()
+	if err != nil {
+		t.Fatalf("GetInvalids failed: %v", err)
+	}
+	wantInvalids := map[uint32][]netip.Prefix{64496: {netip.MustParsePrefix("192.0.2.0/24")}}
+	if !reflect.DeepEqual(invalids, wantInvalids) {
+		t.Errorf("Expected %v, got %v", wantInvalids, invalids)
+	}
+}
+
+func TestPrefixConversion(t *testing.T) {
+	tests := []struct {
+		in   *net.IPNet
+		want string
+	}{
+		{&net.IPNet{IP: net.ParseIP("192.0.2.0"), Mask: net.CIDRMask(24, 32)}, "192.0.2.0/24"},
+		{&net.IPNet{IP: net.ParseIP("192.0.2.7").To4(), Mask: net.CIDRMask(24, 32)}, "192.0.2.0/24"},
+		{&net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(32, 128)}, "2001:db8::/32"},
+	}
+	for _, tt := range tests {
+		p, ok := PrefixFromIPNet(tt.in)
+		if !ok || p.String() != tt.want {
+			t.Errorf("PrefixFromIPNet(%v) = %v, %v; want %s", tt.in, p, ok, tt.want)
+		}
+		if back := IPNetFromPrefix(p); back.String() != tt.want {
+			t.Errorf("IPNetFromPrefix(%v) = %v; want %s", p, back, tt.want)
+		}
+	}
+
+	if _, ok := PrefixFromIPNet(nil); ok {
+		t.Error("Expected nil network to fail conversion")
+	}
+	if IPFromAddr(netip.Addr{}) != nil {
+		t.Error("Expected zero Addr to convert to nil")
+	}
+}
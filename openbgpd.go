@@ -0,0 +1,503 @@
+package clidecode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotSupported is returned by backends for queries their daemon cannot answer.
+var ErrNotSupported = errors.New("not supported by this backend")
+
+// OpenBGPDConn represents a connection to an OpenBGPD bgpd.
+// Queries go through bgpctl -j, run by Runner, which defaults to ExecRunner.
+// Args holds extra bgpctl flags, such as -s for the control socket.
+//
+// bgpd validates routes itself, so ROA states are those it reports (ovs).
+// It has no way to list the contents of its roa-set, so GetVRPs returns
+// ErrNotSupported, and GetROA only answers for prefixes and origins in the RIB.
+type OpenBGPDConn struct {
+	Binary         string
+	Args           []string
+	Runner         CommandRunner
+	Timeout        time.Duration
+	PeerClassifier PeerClassifier
+}
+
+var _ Decoder = (*OpenBGPDConn)(nil)
+var _ DecoderContext = (*OpenBGPDConn)(nil)
+
+// NewOpenBGPDConn creates a new OpenBGPDConn using the local bgpctl
+func NewOpenBGPDConn() *OpenBGPDConn {
+	return &OpenBGPDConn{Binary: "bgpctl"}
+}
+
+// OpenBGPDSet is a set loaded into bgpd, as listed by bgpctl show sets.
+type OpenBGPDSet struct {
+	Name       string
+	Type       string // ROA, ASPA, ASNUM, PREFIX, ...
+	LastChange string
+	ASNs       uint32
+	IPv4, IPv6 uint32
+}
+
+// openbgpdRIB is the output of bgpctl -j show rib.
+type openbgpdRIB struct {
+	RIB []struct {
+		Prefix           string   `json:"prefix"`
+		ASPath           string   `json:"aspath"`
+		Best             bool     `json:"best"`
+		OVS              string   `json:"ovs"`
+		LargeCommunities []string `json:"large_communities"`
+	} `json:"rib"`
+}
+
+// openbgpdNeighbors is the output of bgpctl -j show neighbor.
+type openbgpdNeighbors struct {
+	Neighbors []struct {
+		RemoteAS     openbgpdNumber `json:"remote_as"`
+		RemoteAddr   string         `json:"remote_addr"`
+		Description  string         `json:"description"`
+		BGPID        string         `json:"bgpid"`
+		State        string         `json:"state"`
+		LastError    string         `json:"last_error"`
+		LocalAddr    string         `json:"local_addr"`
+		Capabilities struct {
+			Negotiated struct {
+				Multiprotocol []string `json:"multiprotocol"`
+			} `json:"negotiated"`
+		} `json:"capabilities"`
+		Stats struct {
+			Prefixes struct {
+				Received openbgpdNumber `json:"received"`
+				Sent     openbgpdNumber `json:"sent"`
+			} `json:"prefixes"`
+		} `json:"stats"`
+	} `json:"neighbors"`
+}
+
+// openbgpdMemory is the output of bgpctl -j show rib memory. Memory holds an
+// element per address family, such as "IPv4 unicast", counting its prefixes,
+// then others such as "rib" and "prefix" for the RIB entries and paths.
+type openbgpdMemory struct {
+	Memory map[string]struct {
+		Count uint32 `json:"count"`
+	} `json:"memory"`
+}
+
+// openbgpdSets is the output of bgpctl -j show sets.
+type openbgpdSets struct {
+	Sets []struct {
+		Name       string `json:"name"`
+		Type       string `json:"type"`
+		LastChange string `json:"last_change"`
+		NumASNum   uint32 `json:"num_ASnum"`
+		NumIPv4    uint32 `json:"num_IPv4"`
+		NumIPv6    uint32 `json:"num_IPv6"`
+	} `json:"sets"`
+}
+
+// openbgpdNumber is a number bgpctl may print either bare or as a string, like AS numbers.
+type openbgpdNumber uint32
+
+func (n *openbgpdNumber) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*n = openbgpdNumber(v)
+	return nil
+}
+
+// openbgpdOVS maps bgpd origin validation states onto the ROA constants.
+func openbgpdOVS(s string) int {
+	switch s {
+	case "valid":
+		return RValid
+	case "invalid":
+		return RInvalid
+	}
+	// not-found, or validation turned off
+	return RUnknown
+}
+
+// run runs a bgpctl command and decodes its JSON output into v.
+func (o *OpenBGPDConn) run(ctx context.Context, v any, args ...string) error {
+	binary := o.Binary
+	if binary == "" {
+		binary = "bgpctl"
+	}
+	full := append(append(append([]string{}, o.Args...), "-j"), args...)
+	return runJSON(ctx, o.Runner, o.Timeout, v, binary, full...)
+}
+
+// routes runs a bgpctl show rib command and returns its paths, best paths
+// first, with the validation state bgpd reported.
+func (o *OpenBGPDConn) routes(ctx context.Context, args ...string) ([]ribRoute, error) {
+	var out openbgpdRIB
+	if err := o.run(ctx, &out, append([]string{"show", "rib"}, args...)...); err != nil {
+		return nil, err
+	}
+
+	// bgpctl lists every path, so move best paths ahead of the rest to make them primary
+	sort.SliceStable(out.RIB, func(i, j int) bool {
+		return out.RIB[i].Best && !out.RIB[j].Best
+	})
+
+	routes := make([]ribRoute, 0, len(out.RIB))
+	for _, r := range out.RIB {
+		prefix, err := netip.ParsePrefix(r.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix in bgpctl output: %w", err)
+		}
+		asPath, asSet := decodeASPaths(r.ASPath)
+		routes = append(routes, ribRoute{
+			prefix: prefix,
+			asPath: asPath,
+			asSet:  asSet,
+			large:  len(r.LargeCommunities) > 0,
+			roa:    openbgpdOVS(r.OVS),
+			hasROA: true,
+		})
+	}
+	return routes, nil
+}
+
+// rib loads the whole RIB, with details such as communities if detail is set.
+func (o *OpenBGPDConn) rib(ctx context.Context, detail bool) (*rib, error) {
+	var args []string
+	if detail {
+		args = append(args, "detail")
+	}
+	routes, err := o.routes(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return newRIB(routes), nil
+}
+
+// lookup returns the best route covering ip.
+func (o *OpenBGPDConn) lookup(ctx context.Context, ip net.IP) (ribRoute, bool, error) {
+	routes, err := o.routes(ctx, ip.String())
+	if err != nil {
+		return ribRoute{}, false, err
+	}
+	r, found := newRIB(routes).lookup(ip)
+	return r, found, nil
+}
+
+// GetSets returns the sets loaded into bgpd, such as its roa-set and aspa-set
+func (o *OpenBGPDConn) GetSets() ([]OpenBGPDSet, error) {
+	return o.GetSetsContext(context.Background())
+}
+
+// GetSetsContext is like GetSets but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetSetsContext(ctx context.Context) ([]OpenBGPDSet, error) {
+	var out openbgpdSets
+	if err := o.run(ctx, &out, "show", "sets"); err != nil {
+		return nil, err
+	}
+	sets := make([]OpenBGPDSet, 0, len(out.Sets))
+	for _, s := range out.Sets {
+		sets = append(sets, OpenBGPDSet{
+			Name:       s.Name,
+			Type:       s.Type,
+			LastChange: s.LastChange,
+			ASNs:       s.NumASNum,
+			IPv4:       s.NumIPv4,
+			IPv6:       s.NumIPv6,
+		})
+	}
+	return sets, nil
+}
+
+// GetBGPSessions returns every BGP neighbor, with a channel for each negotiated address family
+func (o *OpenBGPDConn) GetBGPSessions() ([]BGPSession, error) {
+	return o.GetBGPSessionsContext(context.Background())
+}
+
+// GetBGPSessionsContext is like GetBGPSessions but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetBGPSessionsContext(ctx context.Context) ([]BGPSession, error) {
+	var out openbgpdNeighbors
+	if err := o.run(ctx, &out, "show", "neighbor"); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]BGPSession, 0, len(out.Neighbors))
+	for _, n := range out.Neighbors {
+		// The remote address may carry a scope, e.g. fe80::1%em0
+		addr, _, _ := strings.Cut(n.RemoteAddr, "%")
+		s := BGPSession{
+			Name:            n.RemoteAddr,
+			Description:     n.Description,
+			State:           "down",
+			BGPState:        n.State,
+			NeighborAddress: net.ParseIP(addr),
+			NeighborAS:      uint32(n.RemoteAS),
+			NeighborID:      net.ParseIP(n.BGPID),
+			SourceAddress:   net.ParseIP(n.LocalAddr),
+			LastError:       n.LastError,
+		}
+		if s.Established() {
+			s.State = "up"
+		}
+		for _, mp := range n.Capabilities.Negotiated.Multiprotocol {
+			// "IPv4 unicast", "IPv6 unicast", "VPNv4 mpls", ...
+			afi, safi, _ := strings.Cut(strings.ToLower(mp), " ")
+			name := afi
+			if safi != "" && safi != "unicast" {
+				name += "-" + safi
+			}
+			s.Channels = append(s.Channels, BGPChannel{Name: name, State: "UP"})
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
+func (o *OpenBGPDConn) GetBGPTotal() (Totals, error) {
+	return o.GetBGPTotalContext(context.Background())
+}
+
+// GetBGPTotalContext is like GetBGPTotal but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetBGPTotalContext(ctx context.Context) (Totals, error) {
+	// bgpd counts the prefixes of each family, and the prefixes received over
+	// each session, so the RIB itself is never listed. A session carrying both
+	// families is counted under the family of its neighbor address.
+	var mem openbgpdMemory
+	if err := o.run(ctx, &mem, "show", "rib", "memory"); err != nil {
+		return Totals{}, err
+	}
+	var out openbgpdNeighbors
+	if err := o.run(ctx, &out, "show", "neighbor"); err != nil {
+		return Totals{}, err
+	}
+
+	t := Totals{V4Fib: mem.Memory["IPv4 unicast"].Count, V6Fib: mem.Memory["IPv6 unicast"].Count}
+	for _, n := range out.Neighbors {
+		mp := n.Capabilities.Negotiated.Multiprotocol
+		v4 := len(mp) == 0 || slices.Contains(mp, "IPv4 unicast")
+		v6 := slices.Contains(mp, "IPv6 unicast")
+		if v4 && v6 {
+			addr, _, _ := strings.Cut(n.RemoteAddr, "%")
+			ip := net.ParseIP(addr)
+			v4 = ip != nil && ip.To4() != nil
+			v6 = !v4
+		}
+		switch {
+		case v4:
+			t.V4Rib += uint32(n.Stats.Prefixes.Received)
+		case v6:
+			t.V6Rib += uint32(n.Stats.Prefixes.Received)
+		}
+	}
+	return t, nil
+}
+
+// GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
+func (o *OpenBGPDConn) GetPeers() (Peers, error) {
+	return o.GetPeersContext(context.Background())
+}
+
+// GetPeersContext is like GetPeers but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetPeersContext(ctx context.Context) (Peers, error) {
+	sessions, err := o.GetBGPSessionsContext(ctx)
+	if err != nil {
+		return Peers{}, err
+	}
+	classify := o.PeerClassifier
+	if classify == nil {
+		classify = BGPSession.Families
+	}
+	return countPeers(sessions, classify), nil
+}
+
+// GetTotalSourceASNs returns total amount of unique ASNs
+func (o *OpenBGPDConn) GetTotalSourceASNs() (ASNs, error) {
+	return o.GetTotalSourceASNsContext(context.Background())
+}
+
+// GetTotalSourceASNsContext is like GetTotalSourceASNs but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetTotalSourceASNsContext(ctx context.Context) (ASNs, error) {
+	t, err := o.rib(ctx, false)
+	if err != nil {
+		return ASNs{}, err
+	}
+	return t.sourceASNs(), nil
+}
+
+// GetMasks returns the total count of each mask value
+// First item is IPv4, second item is IPv6
+func (o *OpenBGPDConn) GetMasks() ([]map[string]uint32, error) {
+	return o.GetMasksContext(context.Background())
+}
+
+// GetMasksContext is like GetMasks but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	t, err := o.rib(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return t.masks(), nil
+}
+
+// GetROAs returns total amount of all ROA states
+func (o *OpenBGPDConn) GetROAs() (Roas, error) {
+	return o.GetROAsContext(context.Background())
+}
+
+// GetROAsContext is like GetROAs but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetROAsContext(ctx context.Context) (Roas, error) {
+	t, err := o.rib(ctx, false)
+	if err != nil {
+		return Roas{}, err
+	}
+	return t.roas(), nil
+}
+
+// GetLargeCommunities returns the amount of prefixes that have large communities attached (RFC8092)
+func (o *OpenBGPDConn) GetLargeCommunities() (Large, error) {
+	return o.GetLargeCommunitiesContext(context.Background())
+}
+
+// GetLargeCommunitiesContext is like GetLargeCommunities but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetLargeCommunitiesContext(ctx context.Context) (Large, error) {
+	t, err := o.rib(ctx, true)
+	if err != nil {
+		return Large{}, err
+	}
+	return t.large(), nil
+}
+
+// GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN.
+func (o *OpenBGPDConn) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
+	return o.GetIPv4FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv4FromSourceContext is like GetIPv4FromSource but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	return o.fromSource(ctx, asn, true)
+}
+
+// GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN.
+func (o *OpenBGPDConn) GetIPv6FromSource(asn uint32) ([]*net.IPNet, error) {
+	return o.GetIPv6FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv6FromSourceContext is like GetIPv6FromSource but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	return o.fromSource(ctx, asn, false)
+}
+
+// fromSource asks bgpd for the routes originated by asn.
+func (o *OpenBGPDConn) fromSource(ctx context.Context, asn uint32, v4 bool) ([]*net.IPNet, error) {
+	routes, err := o.routes(ctx, "source-as", strconv.FormatUint(uint64(asn), 10))
+	if err != nil {
+		return nil, err
+	}
+	return newRIB(routes).fromSource(asn, v4), nil
+}
+
+// GetOriginFromIP will return the origin ASN from a source IP.
+func (o *OpenBGPDConn) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
+	return o.GetOriginFromIPContext(context.Background(), ip)
+}
+
+// GetOriginFromIPContext is like GetOriginFromIP but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	r, found, err := o.lookup(ctx, ip)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	return r.origin(), true, nil
+}
+
+// GetASPathFromIP will return the AS path, as well as as-set if any from a source IP.
+func (o *OpenBGPDConn) GetASPathFromIP(ip net.IP) (ASPath, bool, error) {
+	return o.GetASPathFromIPContext(context.Background(), ip)
+}
+
+// GetASPathFromIPContext is like GetASPathFromIP but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetASPathFromIPContext(ctx context.Context, ip net.IP) (ASPath, bool, error) {
+	r, found, err := o.lookup(ctx, ip)
+	if err != nil || !found {
+		return ASPath{}, false, err
+	}
+	return ASPath{Path: r.asPath, Set: r.asSet}, true, nil
+}
+
+// GetRoute will return the current FIB entry, if any, from a source IP.
+func (o *OpenBGPDConn) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
+	return o.GetRouteContext(context.Background(), ip)
+}
+
+// GetRouteContext is like GetRoute but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	r, found, err := o.lookup(ctx, ip)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return IPNetFromPrefix(r.prefix), true, nil
+}
+
+// GetROA will return the ROA status bgpd gave a prefix announced by an ASN.
+// It is not found unless such a route is in the RIB.
+func (o *OpenBGPDConn) GetROA(prefix *net.IPNet, asn uint32) (int, bool, error) {
+	return o.GetROAContext(context.Background(), prefix, asn)
+}
+
+// GetROAContext is like GetROA but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	p, ok := PrefixFromIPNet(prefix)
+	if !ok {
+		return 0, false, nil
+	}
+	routes, err := o.routes(ctx, p.String())
+	if err != nil {
+		return 0, false, err
+	}
+	for _, r := range routes {
+		if r.prefix == p && r.origin() == asn {
+			return r.roa, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// GetVRPs is not supported, as bgpd cannot list the contents of its roa-set.
+func (o *OpenBGPDConn) GetVRPs(asn uint32) ([]VRP, error) {
+	return o.GetVRPsContext(context.Background(), asn)
+}
+
+// GetVRPsContext is like GetVRPs but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetVRPsContext(ctx context.Context, asn uint32) ([]VRP, error) {
+	return nil, ErrNotSupported
+}
+
+// GetInvalids returns a map of ASNs that are advertising RPKI invalid prefixes.
+// It also includes all those prefixes being advertised.
+func (o *OpenBGPDConn) GetInvalids() (map[string][]string, error) {
+	return o.GetInvalidsContext(context.Background())
+}
+
+// GetInvalidsContext is like GetInvalids but honours the cancellation and deadline of ctx
+func (o *OpenBGPDConn) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	routes, err := o.routes(ctx, "ovs", "invalid")
+	if err != nil {
+		return nil, err
+	}
+	return newRIB(routes).invalids(), nil
+}
@@ -0,0 +1,142 @@
+package clidecode
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestOpenBGPDConn(t *testing.T) {
+	o := NewOpenBGPDConn()
+	o.Args = []string{"-s", "/var/run/bgpd.rsock"}
+	o.Runner = fileRunner(map[string]string{
+		"bgpctl -s /var/run/bgpd.rsock -j show rib":                 "testdata/openbgpd/rib.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show rib detail":          "testdata/openbgpd/rib_detail.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show rib 8.8.8.8":         "testdata/openbgpd/rib_8.8.8.8.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show rib 192.0.2.55":      "testdata/openbgpd/rib_192.0.2.55.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show rib 203.0.113.0/24":  "testdata/openbgpd/rib_203.0.113.0_24.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show rib source-as 15169": "testdata/openbgpd/rib_source-as_15169.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show rib ovs invalid":     "testdata/openbgpd/rib_ovs_invalid.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show rib memory":          "testdata/openbgpd/rib_memory.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show neighbor":            "testdata/openbgpd/neighbor.json",
+		"bgpctl -s /var/run/bgpd.rsock -j show sets":                "testdata/openbgpd/sets.json",
+	})
+
+	totals, err := o.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if totals != (Totals{V4Rib: 4, V4Fib: 3, V6Rib: 2, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+
+	peers, err := o.GetPeers()
+	if err != nil {
+		t.Fatalf("GetPeers failed: %v", err)
+	}
+	if peers != (Peers{V4c: 2, V4e: 1, V6c: 1, V6e: 1}) {
+		t.Errorf("Unexpected peers %+v", peers)
+	}
+
+	// The AS set route has no origin, so it is not counted
+	asns, err := o.GetTotalSourceASNs()
+	if err != nil {
+		t.Fatalf("GetTotalSourceASNs failed: %v", err)
+	}
+	if asns != (ASNs{As4: 2, As6: 2, As10: 2, AsBoth: 2}) {
+		t.Errorf("Unexpected ASNs %+v", asns)
+	}
+
+	masks, _ := o.GetMasks()
+	if !reflect.DeepEqual(masks, []map[string]uint32{{"24": 3}, {"32": 1, "48": 1}}) {
+		t.Errorf("Unexpected masks %v", masks)
+	}
+
+	// States come straight from bgpd, not-found counting as unknown
+	roas, err := o.GetROAs()
+	if err != nil {
+		t.Fatalf("GetROAs failed: %v", err)
+	}
+	if roas != (Roas{V4v: 1, V4i: 1, V4u: 1, V6v: 1, V6i: 1}) {
+		t.Errorf("Unexpected ROAs %+v", roas)
+	}
+
+	large, _ := o.GetLargeCommunities()
+	if large != (Large{V4: 1, V6: 1}) {
+		t.Errorf("Unexpected large communities %+v", large)
+	}
+
+	invalids, _ := o.GetInvalids()
+	expected := map[string][]string{"64511": {"203.0.113.0/24", "2001:db8:100::/48"}}
+	if !reflect.DeepEqual(invalids, expected) {
+		t.Errorf("Expected invalids %v, got %v", expected, invalids)
+	}
+
+	// The best path is listed second
+	path, found, err := o.GetASPathFromIP(net.ParseIP("8.8.8.8"))
+	if err != nil || !found || !reflect.DeepEqual(path.Path, []uint32{64497, 15169}) {
+		t.Errorf("GetASPathFromIP returned %+v, %v, %v", path, found, err)
+	}
+	if _, found, err := o.GetRoute(net.ParseIP("192.0.2.55")); err != nil || found {
+		t.Errorf("Expected not found, got %v, %v", found, err)
+	}
+
+	nets, _ := o.GetIPv4FromSource(15169)
+	if len(nets) != 1 || nets[0].String() != "8.8.8.0/24" {
+		t.Errorf("Unexpected networks %v", nets)
+	}
+	nets, _ = o.GetIPv6FromSource(15169)
+	if len(nets) != 1 || nets[0].String() != "2001:4860::/32" {
+		t.Errorf("Unexpected networks %v", nets)
+	}
+
+	_, prefix, _ := net.ParseCIDR("203.0.113.0/24")
+	if status, found, _ := o.GetROA(prefix, 64511); !found || status != RInvalid {
+		t.Errorf("Expected invalid, got %d, %v", status, found)
+	}
+	if _, found, _ := o.GetROA(prefix, 64500); found {
+		t.Error("Expected no ROA state for an origin not in the RIB")
+	}
+	if _, err := o.GetVRPs(15169); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+
+	sessions, err := o.GetBGPSessions()
+	if err != nil {
+		t.Fatalf("GetBGPSessions failed: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("Expected 3 sessions, got %d", len(sessions))
+	}
+	s := sessions[0]
+	if s.State != "up" || s.NeighborAS != 64496 || len(s.Channels) != 1 || s.Channels[0].Name != "ipv4" {
+		t.Errorf("Unexpected session %+v", s)
+	}
+	if s := sessions[1]; s.State != "down" || s.LastError != "Cease, administratively down" {
+		t.Errorf("Unexpected session %+v", s)
+	}
+
+	sets, err := o.GetSets()
+	if err != nil {
+		t.Fatalf("GetSets failed: %v", err)
+	}
+	if len(sets) != 2 || sets[0] != (OpenBGPDSet{Name: "RPKI ROA", Type: "ROA", LastChange: "00:05:00", IPv4: 412345, IPv6: 98765}) {
+		t.Errorf("Unexpected sets %+v", sets)
+	}
+}
+
+func TestOpenBGPDConnTotalsFromCounters(t *testing.T) {
+	o := NewOpenBGPDConn()
+	o.Runner = fileRunner(map[string]string{
+		"bgpctl -j show rib memory": "testdata/openbgpd/rib_memory.json",
+		"bgpctl -j show neighbor":   "testdata/openbgpd/neighbor.json",
+	})
+	totals, err := o.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if totals != (Totals{V4Rib: 4, V4Fib: 3, V6Rib: 2, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+}
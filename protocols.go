@@ -0,0 +1,284 @@
+package clidecode
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BGPSession holds the state of a single BGP protocol, as listed by "show protocols all".
+type BGPSession struct {
+	Name        string
+	Description string
+	State       string // protocol state: up, start, down, ...
+	Since       string
+	Info        string
+
+	BGPState        string // Established, Active, Connect, ...
+	NeighborAddress net.IP
+	NeighborAS      uint32
+	LocalAS         uint32
+	NeighborID      net.IP
+	SourceAddress   net.IP
+	LastError       string
+	HoldTimer       Timer
+	KeepaliveTimer  Timer
+
+	Channels []BGPChannel
+}
+
+// Timer is a running BGP timer: time remaining until it fires, and its configured interval.
+type Timer struct {
+	Remaining time.Duration
+	Interval  time.Duration
+}
+
+// BGPChannel holds the state of one address family carried by a BGP session.
+type BGPChannel struct {
+	Name         string // ipv4, ipv6, flow4, ...
+	State        string // UP, DOWN, ...
+	Table        string
+	Preference   int
+	InputFilter  string
+	OutputFilter string
+
+	Imported, Filtered, Exported, Preferred uint32
+
+	ImportLimit, ReceiveLimit, ExportLimit *RouteLimit
+}
+
+// RouteLimit is a route limit on a channel and the action taken when it is hit.
+type RouteLimit struct {
+	Limit  uint32
+	Action string
+}
+
+// Established reports whether the BGP session is up.
+func (s BGPSession) Established() bool {
+	if s.BGPState != "" {
+		return s.BGPState == "Established"
+	}
+	return strings.HasPrefix(s.Info, "Established")
+}
+
+// Families reports which address families the session carries, taken from its
+// channels, or from the neighbor address if BIRD listed none.
+func (s BGPSession) Families() (v4, v6 bool) {
+	if len(s.Channels) == 0 {
+		return ClassifyByNeighbor(s)
+	}
+	return ClassifyByChannel(s)
+}
+
+// Family returns 4 or 6 for channels carrying IPv4 or IPv6 networks, such as ipv4,
+// ipv6-mc, flow4 or vpn6-mpls, and 0 for anything else.
+func (c BGPChannel) Family() int {
+	name := strings.SplitN(c.Name, "-", 2)[0]
+	switch {
+	case strings.HasSuffix(name, "4"):
+		return 4
+	case strings.HasSuffix(name, "6"):
+		return 6
+	}
+	return 0
+}
+
+// GetBGPSessions returns every BGP protocol with its neighbor, timers and channels
+func (b *BirdClient) GetBGPSessions() ([]BGPSession, error) {
+	return b.GetBGPSessionsContext(context.Background())
+}
+
+// GetBGPSessionsContext is like GetBGPSessions but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetBGPSessionsContext(ctx context.Context) ([]BGPSession, error) {
+	var p protocolParser
+	var sessions []BGPSession
+	err := b.replies(ctx, "show protocols all", func(rl ReplyLine) error {
+		if s, ok := p.feed(rl); ok {
+			sessions = append(sessions, s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := p.flush(); ok {
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// protocolParser turns "show protocols all" output into BGP sessions.
+// Protocols other than BGP are skipped along with their details.
+type protocolParser struct {
+	current   BGPSession
+	isBGP     bool
+	channel   *BGPChannel
+	lastLimit *RouteLimit
+}
+
+// feed parses a single line, returning the previous session once the next protocol starts.
+func (p *protocolParser) feed(rl ReplyLine) (BGPSession, bool) {
+	line := rl.Text
+	if strings.TrimSpace(line) == "" || rl.Code == CodeProtocolHeader {
+		return BGPSession{}, false
+	}
+
+	// Protocol summary lines are listed under 1002, details under 1006
+	summary := rl.Code == CodeProtocol
+	if rl.Code == CodeUnknown {
+		summary = line[0] != ' ' && line[0] != '\t'
+	}
+	if !summary {
+		if p.isBGP {
+			p.addDetail(line)
+		}
+		return BGPSession{}, false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 4 || strings.EqualFold(fields[0], "name") {
+		// Header, or something like the "BIRD 2.0.8 ready." banner
+		return BGPSession{}, false
+	}
+
+	done, had := p.flush()
+	p.isBGP = fields[1] == "BGP"
+	p.current = BGPSession{Name: fields[0], State: fields[3]}
+	p.channel = nil
+	p.lastLimit = nil
+
+	// The since column may hold a date and a time, the rest is free-form info
+	rest := fields[4:]
+	if len(rest) > 0 {
+		p.current.Since = rest[0]
+		rest = rest[1:]
+		if len(rest) > 0 && strings.Count(rest[0], ":") == 2 {
+			p.current.Since += " " + rest[0]
+			rest = rest[1:]
+		}
+	}
+	p.current.Info = strings.Join(rest, " ")
+
+	return done, had
+}
+
+// flush returns the BGP session currently being built, if any.
+func (p *protocolParser) flush() (BGPSession, bool) {
+	if !p.isBGP {
+		return BGPSession{}, false
+	}
+	p.isBGP = false
+	p.channel = nil
+	return p.current, true
+}
+
+// addDetail parses an indented "key: value" line of the current protocol.
+func (p *protocolParser) addDetail(line string) {
+	line = strings.TrimSpace(line)
+	if name, ok := strings.CutPrefix(line, "Channel "); ok {
+		p.current.Channels = append(p.current.Channels, BGPChannel{Name: strings.TrimSpace(name)})
+		p.channel = &p.current.Channels[len(p.current.Channels)-1]
+		return
+	}
+
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return
+	}
+	value = strings.TrimSpace(value)
+
+	if p.channel != nil {
+		p.setChannel(key, value)
+		return
+	}
+
+	s := &p.current
+	switch key {
+	case "Description":
+		s.Description = value
+	case "BGP state":
+		s.BGPState = value
+	case "Neighbor address":
+		// May carry a scope, e.g. fe80::1%eth0
+		addr, _, _ := strings.Cut(value, "%")
+		s.NeighborAddress = net.ParseIP(addr)
+	case "Neighbor AS":
+		s.NeighborAS = stringToUint32(value)
+	case "Local AS":
+		s.LocalAS = stringToUint32(value)
+	case "Neighbor ID":
+		s.NeighborID = net.ParseIP(value)
+	case "Source address":
+		s.SourceAddress = net.ParseIP(value)
+	case "Last error":
+		s.LastError = value
+	case "Hold timer":
+		s.HoldTimer = parseTimer(value)
+	case "Keepalive timer":
+		s.KeepaliveTimer = parseTimer(value)
+	}
+}
+
+// setChannel sets a detail of the current channel.
+func (p *protocolParser) setChannel(key, value string) {
+	c := p.channel
+	switch key {
+	case "State":
+		c.State = value
+	case "Table":
+		c.Table = value
+	case "Preference":
+		c.Preference, _ = strconv.Atoi(value)
+	case "Input filter":
+		c.InputFilter = value
+	case "Output filter":
+		c.OutputFilter = value
+	case "Routes":
+		// 10 imported, 2 filtered, 5 exported, 8 preferred
+		for _, part := range strings.Split(value, ",") {
+			f := strings.Fields(part)
+			if len(f) != 2 {
+				continue
+			}
+			n := stringToUint32(f[0])
+			switch f[1] {
+			case "imported":
+				c.Imported = n
+			case "filtered":
+				c.Filtered = n
+			case "exported":
+				c.Exported = n
+			case "preferred":
+				c.Preferred = n
+			}
+		}
+	case "Import limit":
+		c.ImportLimit = &RouteLimit{Limit: stringToUint32(value)}
+		p.lastLimit = c.ImportLimit
+	case "Receive limit":
+		c.ReceiveLimit = &RouteLimit{Limit: stringToUint32(value)}
+		p.lastLimit = c.ReceiveLimit
+	case "Export limit":
+		c.ExportLimit = &RouteLimit{Limit: stringToUint32(value)}
+		p.lastLimit = c.ExportLimit
+	case "Action":
+		if p.lastLimit != nil {
+			p.lastLimit.Action = value
+		}
+	}
+}
+
+// parseTimer parses a timer such as "180.000/240", remaining seconds then interval.
+func parseTimer(value string) Timer {
+	var t Timer
+	remaining, interval, _ := strings.Cut(value, "/")
+	if secs, err := strconv.ParseFloat(remaining, 64); err == nil {
+		t.Remaining = time.Duration(secs * float64(time.Second))
+	}
+	if secs, err := strconv.ParseFloat(interval, 64); err == nil {
+		t.Interval = time.Duration(secs * float64(time.Second))
+	}
+	return t
+}
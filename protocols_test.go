@@ -0,0 +1,202 @@
+package clidecode
+
+import (
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const protocolsAll = `Name       Proto      Table      State  Since         Info
+device1    Device     ---        up     2025-11-19    
+kernel1    Kernel     master4    up     2025-11-19    
+  Channel ipv4
+    State:          UP
+    Table:          master4
+transit_cogent BGP        ---        up     2025-11-19 10:00:00  Established   
+  Description:    Cogent transit
+  BGP state:          Established
+    Neighbor address: 192.0.2.1
+    Neighbor AS:      174
+    Local AS:         64500
+    Neighbor ID:      192.0.2.1
+    Local capabilities
+      Multiprotocol
+        AF announced: ipv4 ipv6
+    Session:          external AS4
+    Source address:   192.0.2.2
+    Hold timer:       174.500/240
+    Keepalive timer:  28.250/80
+  Channel ipv4
+    State:          UP
+    Table:          master4
+    Preference:     100
+    Input filter:   transit_in
+    Output filter:  transit_out
+    Import limit:   1200000
+      Action:       disable
+    Routes:         1000000 imported, 12 filtered, 10 exported, 900000 preferred
+  Channel ipv6
+    State:          UP
+    Table:          master6
+    Preference:     100
+    Input filter:   transit_in
+    Output filter:  transit_out
+    Routes:         200000 imported, 0 filtered, 4 exported, 190000 preferred
+ix_decix_4201 BGP        ---        start  2025-11-19    Active        Socket: Connection refused
+  BGP state:          Active
+    Neighbor address: 2001:db8::4201
+    Neighbor AS:      4201
+    Local AS:         64500
+    Last error:       Socket: Connection refused
+  Channel ipv6
+    State:          DOWN
+    Table:          master6
+    Preference:     100
+    Input filter:   ix_in
+    Output filter:  ix_out
+`
+
+func TestGetBGPSessions(t *testing.T) {
+	client := &BirdClient{
+		Querier: mockQuerier(map[string]string{"show protocols all": protocolsAll}),
+	}
+
+	sessions, err := client.GetBGPSessions()
+	if err != nil {
+		t.Fatalf("GetBGPSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 BGP sessions, got %d", len(sessions))
+	}
+
+	expected := BGPSession{
+		Name:            "transit_cogent",
+		Description:     "Cogent transit",
+		State:           "up",
+		Since:           "2025-11-19 10:00:00",
+		Info:            "Established",
+		BGPState:        "Established",
+		NeighborAddress: net.ParseIP("192.0.2.1"),
+		NeighborAS:      174,
+		LocalAS:         64500,
+		NeighborID:      net.ParseIP("192.0.2.1"),
+		SourceAddress:   net.ParseIP("192.0.2.2"),
+		HoldTimer:       Timer{Remaining: 174500 * time.Millisecond, Interval: 240 * time.Second},
+		KeepaliveTimer:  Timer{Remaining: 28250 * time.Millisecond, Interval: 80 * time.Second},
+		Channels: []BGPChannel{
+			{
+				Name: "ipv4", State: "UP", Table: "master4", Preference: 100,
+				InputFilter: "transit_in", OutputFilter: "transit_out",
+				Imported: 1000000, Filtered: 12, Exported: 10, Preferred: 900000,
+				ImportLimit: &RouteLimit{Limit: 1200000, Action: "disable"},
+			},
+			{
+				Name: "ipv6", State: "UP", Table: "master6", Preference: 100,
+				InputFilter: "transit_in", OutputFilter: "transit_out",
+				Imported: 200000, Exported: 4, Preferred: 190000,
+			},
+		},
+	}
+	if !reflect.DeepEqual(sessions[0], expected) {
+		t.Errorf("Session mismatch.\nGot  %+v\nWant %+v", sessions[0], expected)
+	}
+
+	down := sessions[1]
+	if down.Established() || down.LastError != "Socket: Connection refused" || down.Info != "Active Socket: Connection refused" {
+		t.Errorf("Unexpected down session %+v", down)
+	}
+	if v4, v6 := down.Families(); v4 || !v6 {
+		t.Errorf("Expected IPv6 only, got v4=%t v6=%t", v4, v6)
+	}
+
+	peers, err := client.GetPeers()
+	if err != nil {
+		t.Fatalf("GetPeers failed: %v", err)
+	}
+	if want := (Peers{V4c: 1, V4e: 1, V6c: 2, V6e: 1}); peers != want {
+		t.Errorf("Expected %+v, got %+v", want, peers)
+	}
+}
+
+func TestProtocolParserCodes(t *testing.T) {
+	reply := "2002-Name       Proto      Table      State  Since         Info\n" +
+		"1002-device1    Device     ---        up     2025-11-19    \n" +
+		" bgp1       BGP        ---        up     2025-11-19    Established   \n" +
+		"1006-  BGP state:          Established\n" +
+		"     Neighbor address: 192.0.2.1\n" +
+		"   Channel ipv4\n" +
+		"     State:          UP\n" +
+		"1002-bgp2       BGP        ---        up     2025-11-19    Established   \n" +
+		"0000 \n"
+
+	var p protocolParser
+	var names []string
+	for rl := range ParseReply(strings.NewReader(reply)) {
+		if s, ok := p.feed(rl); ok {
+			names = append(names, s.Name)
+		}
+	}
+	if s, ok := p.flush(); ok {
+		names = append(names, s.Name)
+	}
+
+	if want := []string{"bgp1", "bgp2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Got %v, want %v", names, want)
+	}
+}
+
+func TestPeerClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		classify PeerClassifier
+		want     Peers
+	}{
+		{"default", nil, Peers{V4c: 1, V4e: 1, V6c: 2, V6e: 1}},
+		{"neighbor", ClassifyByNeighbor, Peers{V4c: 1, V4e: 1, V6c: 1}},
+		{"legacy names", ClassifyByName(regexp.MustCompile(`_v4`), regexp.MustCompile(`_v6`)), Peers{}},
+		{"custom", func(s BGPSession) (bool, bool) { return s.NeighborAS == 4201, false }, Peers{V4c: 1}},
+	}
+
+	for _, tc := range tests {
+		client := &BirdClient{
+			Querier:        mockQuerier(map[string]string{"show protocols all": protocolsAll}),
+			PeerClassifier: tc.classify,
+		}
+		peers, err := client.GetPeers()
+		if err != nil {
+			t.Fatalf("%s: GetPeers failed: %v", tc.name, err)
+		}
+		if peers != tc.want {
+			t.Errorf("%s: Expected %+v, got %+v", tc.name, tc.want, peers)
+		}
+	}
+}
+
+func TestGetPeerGroups(t *testing.T) {
+	client := &BirdClient{
+		Querier: mockQuerier(map[string]string{"show protocols all": protocolsAll}),
+		PeerGrouper: GroupByName(
+			PeerGroup{Name: "transit", Pattern: regexp.MustCompile(`^transit_`)},
+			PeerGroup{Name: "ix", Pattern: regexp.MustCompile(`^ix_`)},
+		),
+	}
+
+	total, groups, err := client.GetPeerGroups()
+	if err != nil {
+		t.Fatalf("GetPeerGroups failed: %v", err)
+	}
+
+	if want := (Peers{V4c: 1, V4e: 1, V6c: 2, V6e: 1}); total != want {
+		t.Errorf("Expected total %+v, got %+v", want, total)
+	}
+	expected := map[string]Peers{
+		"transit": {V4c: 1, V4e: 1, V6c: 1, V6e: 1},
+		"ix":      {V6c: 1},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, groups)
+	}
+}
@@ -0,0 +1,353 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/mellowdrifter/clidecode/remote/remotepb"
+	"google.golang.org/grpc"
+)
+
+// Client is a Decoder answering from a remote server added with Register.
+// Timeout overrides DefaultTimeout for calls whose context has no deadline.
+type Client struct {
+	Conn    grpc.ClientConnInterface
+	Timeout time.Duration
+}
+
+var _ clidecode.Decoder = (*Client)(nil)
+var _ clidecode.DecoderContext = (*Client)(nil)
+
+// NewClient creates a Client over conn with the default timeout
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{Conn: conn, Timeout: clidecode.DefaultTimeout}
+}
+
+// context bounds ctx by Timeout if it has no deadline
+func (c *Client) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		return context.WithTimeout(ctx, c.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// stub returns the generated client of the service over c.Conn
+func (c *Client) stub() remotepb.DecoderClient {
+	return remotepb.NewDecoderClient(c.Conn)
+}
+
+// receive collects a stream of replies, handing each to each
+func receive[Reply any](s grpc.ServerStreamingClient[Reply], err error, each func(*Reply) error) error {
+	if err != nil {
+		return clientError(err)
+	}
+	for {
+		reply, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return clientError(err)
+		}
+		if err := each(reply); err != nil {
+			return err
+		}
+	}
+}
+
+func parsePrefix(s string) (*net.IPNet, error) {
+	_, prefix, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("remote: invalid prefix %q: %w", s, err)
+	}
+	return prefix, nil
+}
+
+// GetBGPTotal returns rib, fib ipv4. rib, fib ipv6
+func (c *Client) GetBGPTotal() (clidecode.Totals, error) {
+	return c.GetBGPTotalContext(context.Background())
+}
+
+// GetBGPTotalContext is like GetBGPTotal but honours the cancellation and deadline of ctx
+func (c *Client) GetBGPTotalContext(ctx context.Context) (clidecode.Totals, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	t, err := c.stub().GetBGPTotal(ctx, &remotepb.Empty{})
+	if err != nil {
+		return clidecode.Totals{}, clientError(err)
+	}
+	return clidecode.Totals{V4Rib: t.GetV4Rib(), V4Fib: t.GetV4Fib(), V6Rib: t.GetV6Rib(), V6Fib: t.GetV6Fib()}, nil
+}
+
+// GetPeers returns ipv4 peer configured, established. ipv6 peers configured, established
+func (c *Client) GetPeers() (clidecode.Peers, error) {
+	return c.GetPeersContext(context.Background())
+}
+
+// GetPeersContext is like GetPeers but honours the cancellation and deadline of ctx
+func (c *Client) GetPeersContext(ctx context.Context) (clidecode.Peers, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	p, err := c.stub().GetPeers(ctx, &remotepb.Empty{})
+	if err != nil {
+		return clidecode.Peers{}, clientError(err)
+	}
+	return clidecode.Peers{
+		V4c: p.GetV4Configured(),
+		V4e: p.GetV4Established(),
+		V6c: p.GetV6Configured(),
+		V6e: p.GetV6Established(),
+	}, nil
+}
+
+// GetTotalSourceASNs returns total amount of unique ASNs
+func (c *Client) GetTotalSourceASNs() (clidecode.ASNs, error) {
+	return c.GetTotalSourceASNsContext(context.Background())
+}
+
+// GetTotalSourceASNsContext is like GetTotalSourceASNs but honours the cancellation and deadline of ctx
+func (c *Client) GetTotalSourceASNsContext(ctx context.Context) (clidecode.ASNs, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	a, err := c.stub().GetTotalSourceASNs(ctx, &remotepb.Empty{})
+	if err != nil {
+		return clidecode.ASNs{}, clientError(err)
+	}
+	return clidecode.ASNs{
+		As4:     a.GetAs4(),
+		As6:     a.GetAs6(),
+		As10:    a.GetAs10(),
+		As4Only: a.GetAs4Only(),
+		As6Only: a.GetAs6Only(),
+		AsBoth:  a.GetAsBoth(),
+	}, nil
+}
+
+// GetMasks returns the total count of each mask value
+// First item is IPv4, second item is IPv6
+func (c *Client) GetMasks() ([]map[string]uint32, error) {
+	return c.GetMasksContext(context.Background())
+}
+
+// GetMasksContext is like GetMasks but honours the cancellation and deadline of ctx
+func (c *Client) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	m, err := c.stub().GetMasks(ctx, &remotepb.Empty{})
+	if err != nil {
+		return nil, clientError(err)
+	}
+	masks := make([]map[string]uint32, 0, len(m.GetMasks()))
+	for _, t := range m.GetMasks() {
+		counts := t.GetCounts()
+		if counts == nil {
+			counts = make(map[string]uint32)
+		}
+		masks = append(masks, counts)
+	}
+	return masks, nil
+}
+
+// GetROAs returns total amount of all ROA states
+func (c *Client) GetROAs() (clidecode.Roas, error) {
+	return c.GetROAsContext(context.Background())
+}
+
+// GetROAsContext is like GetROAs but honours the cancellation and deadline of ctx
+func (c *Client) GetROAsContext(ctx context.Context) (clidecode.Roas, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	r, err := c.stub().GetROAs(ctx, &remotepb.Empty{})
+	if err != nil {
+		return clidecode.Roas{}, clientError(err)
+	}
+	return clidecode.Roas{
+		V4v: r.GetV4Valid(),
+		V4i: r.GetV4Invalid(),
+		V4u: r.GetV4Unknown(),
+		V6v: r.GetV6Valid(),
+		V6i: r.GetV6Invalid(),
+		V6u: r.GetV6Unknown(),
+	}, nil
+}
+
+// GetLargeCommunities returns the amount of prefixes that have large communities attached (RFC8092)
+func (c *Client) GetLargeCommunities() (clidecode.Large, error) {
+	return c.GetLargeCommunitiesContext(context.Background())
+}
+
+// GetLargeCommunitiesContext is like GetLargeCommunities but honours the cancellation and deadline of ctx
+func (c *Client) GetLargeCommunitiesContext(ctx context.Context) (clidecode.Large, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	l, err := c.stub().GetLargeCommunities(ctx, &remotepb.Empty{})
+	if err != nil {
+		return clidecode.Large{}, clientError(err)
+	}
+	return clidecode.Large{V4: l.GetV4(), V6: l.GetV6()}, nil
+}
+
+// GetIPv4FromSource returns all the IPv4 networks sourced from a source ASN.
+func (c *Client) GetIPv4FromSource(asn uint32) ([]*net.IPNet, error) {
+	return c.GetIPv4FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv4FromSourceContext is like GetIPv4FromSource but honours the cancellation and deadline of ctx
+func (c *Client) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	return collectPrefixes(c.stub().GetIPv4FromSource(ctx, &remotepb.ASNRequest{Asn: asn}))
+}
+
+// GetIPv6FromSource returns all the IPv6 networks sourced from a source ASN.
+func (c *Client) GetIPv6FromSource(asn uint32) ([]*net.IPNet, error) {
+	return c.GetIPv6FromSourceContext(context.Background(), asn)
+}
+
+// GetIPv6FromSourceContext is like GetIPv6FromSource but honours the cancellation and deadline of ctx
+func (c *Client) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	return collectPrefixes(c.stub().GetIPv6FromSource(ctx, &remotepb.ASNRequest{Asn: asn}))
+}
+
+// collectPrefixes collects a stream of prefixes
+func collectPrefixes(s grpc.ServerStreamingClient[remotepb.PrefixReply], err error) ([]*net.IPNet, error) {
+	var prefixes []*net.IPNet
+	err = receive(s, err, func(r *remotepb.PrefixReply) error {
+		prefix, err := parsePrefix(r.GetPrefix())
+		if err != nil {
+			return err
+		}
+		prefixes = append(prefixes, prefix)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prefixes, nil
+}
+
+// GetOriginFromIP will return the origin ASN from a source IP.
+func (c *Client) GetOriginFromIP(ip net.IP) (uint32, bool, error) {
+	return c.GetOriginFromIPContext(context.Background(), ip)
+}
+
+// GetOriginFromIPContext is like GetOriginFromIP but honours the cancellation and deadline of ctx
+func (c *Client) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	r, err := c.stub().GetOriginFromIP(ctx, &remotepb.IPRequest{Ip: ip.String()})
+	if err != nil {
+		return 0, false, clientError(err)
+	}
+	return r.GetAsn(), r.GetFound(), nil
+}
+
+// GetASPathFromIP will return the AS path, as well as as-set if any from a source IP.
+func (c *Client) GetASPathFromIP(ip net.IP) (clidecode.ASPath, bool, error) {
+	return c.GetASPathFromIPContext(context.Background(), ip)
+}
+
+// GetASPathFromIPContext is like GetASPathFromIP but honours the cancellation and deadline of ctx
+func (c *Client) GetASPathFromIPContext(ctx context.Context, ip net.IP) (clidecode.ASPath, bool, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	r, err := c.stub().GetASPathFromIP(ctx, &remotepb.IPRequest{Ip: ip.String()})
+	if err != nil {
+		return clidecode.ASPath{}, false, clientError(err)
+	}
+	return clidecode.ASPath{Path: r.GetPath().GetPath(), Set: r.GetPath().GetSet()}, r.GetFound(), nil
+}
+
+// GetRoute will return the current FIB entry, if any, from a source IP.
+func (c *Client) GetRoute(ip net.IP) (*net.IPNet, bool, error) {
+	return c.GetRouteContext(context.Background(), ip)
+}
+
+// GetRouteContext is like GetRoute but honours the cancellation and deadline of ctx
+func (c *Client) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	r, err := c.stub().GetRoute(ctx, &remotepb.IPRequest{Ip: ip.String()})
+	if err != nil {
+		return nil, false, clientError(err)
+	}
+	if !r.GetFound() {
+		return nil, false, nil
+	}
+	prefix, err := parsePrefix(r.GetPrefix())
+	if err != nil {
+		return nil, false, err
+	}
+	return prefix, true, nil
+}
+
+// GetROA will return the ROA status, if any, from a source IP and ASN.
+func (c *Client) GetROA(prefix *net.IPNet, asn uint32) (int, bool, error) {
+	return c.GetROAContext(context.Background(), prefix, asn)
+}
+
+// GetROAContext is like GetROA but honours the cancellation and deadline of ctx
+func (c *Client) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	r, err := c.stub().GetROA(ctx, &remotepb.ROARequest{Prefix: prefix.String(), Asn: asn})
+	if err != nil {
+		return 0, false, clientError(err)
+	}
+	return int(r.GetState()), r.GetFound(), nil
+}
+
+// GetVRPs will return all Validated ROA Payloads for an ASN.
+func (c *Client) GetVRPs(asn uint32) ([]clidecode.VRP, error) {
+	return c.GetVRPsContext(context.Background(), asn)
+}
+
+// GetVRPsContext is like GetVRPs but honours the cancellation and deadline of ctx
+func (c *Client) GetVRPsContext(ctx context.Context, asn uint32) ([]clidecode.VRP, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	r, err := c.stub().GetVRPs(ctx, &remotepb.ASNRequest{Asn: asn})
+	if err != nil {
+		return nil, clientError(err)
+	}
+	var vrps []clidecode.VRP
+	for _, v := range r.GetVrps() {
+		prefix, err := parsePrefix(v.GetPrefix())
+		if err != nil {
+			return nil, err
+		}
+		vrps = append(vrps, clidecode.VRP{Prefix: prefix, Max: int(v.GetMaxLength())})
+	}
+	return vrps, nil
+}
+
+// GetInvalids returns a map of ASNs that are advertising RPKI invalid prefixes.
+// It also includes all those prefixes being advertised.
+func (c *Client) GetInvalids() (map[string][]string, error) {
+	return c.GetInvalidsContext(context.Background())
+}
+
+// GetInvalidsContext is like GetInvalids but honours the cancellation and deadline of ctx
+func (c *Client) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	s, err := c.stub().GetInvalids(ctx, &remotepb.Empty{})
+	invalids := make(map[string][]string)
+	err = receive(s, err, func(r *remotepb.InvalidReply) error {
+		asn := strconv.FormatUint(uint64(r.GetAsn()), 10)
+		invalids[asn] = r.GetPrefixes()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invalids, nil
+}
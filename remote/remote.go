@@ -0,0 +1,60 @@
+// Package remote serves a clidecode.Decoder over gRPC, so routers can be
+// queried from other hosts. Register adds the service to a grpc.Server, and
+// Client is a Decoder answering from it.
+//
+// The service and its messages are defined in remotepb/decoder.proto, which
+// clients in other languages can generate their stubs from. NewServer also
+// registers server reflection, so tools such as grpcurl can query a server
+// without a copy of the file.
+//
+// Prefixes by origin and invalids are streamed a prefix, or an ASN, at a time.
+// The Decoder methods answering them return the whole result, so the server
+// holds it in memory before it sends the first message.
+package remote
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mellowdrifter/clidecode"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName is the full name of the gRPC service
+const ServiceName = "clidecode.Decoder"
+
+// serverError turns an error of the Decoder into a status the client maps back
+func serverError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, clidecode.ErrNotSupported):
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// clientError turns a status into the error the Decoder would have returned,
+// so callers can test for context.DeadlineExceeded or ErrNotSupported.
+// Other statuses are returned as is.
+func clientError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch s.Code() {
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	case codes.Canceled:
+		return context.Canceled
+	case codes.Unimplemented:
+		return clidecode.ErrNotSupported
+	}
+	return err
+}
@@ -0,0 +1,303 @@
+package remote
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/mellowdrifter/clidecode/remote/remotepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// dial serves d over an in-memory listener and returns a Client connected to it
+func dial(t *testing.T, d clidecode.Decoder, server []grpc.ServerOption, creds credentials.TransportCredentials) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(d, server...)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(creds),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+func TestRoundTrip(t *testing.T) {
+	fake := clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json")
+	client := dial(t, fake, nil, nil)
+
+	_, roaPrefix, _ := net.ParseCIDR("192.0.2.0/24")
+	tests := []struct {
+		name string
+		call func(clidecode.Decoder) (any, error)
+	}{
+		{"GetBGPTotal", func(d clidecode.Decoder) (any, error) { return d.GetBGPTotal() }},
+		{"GetPeers", func(d clidecode.Decoder) (any, error) { return d.GetPeers() }},
+		{"GetTotalSourceASNs", func(d clidecode.Decoder) (any, error) { return d.GetTotalSourceASNs() }},
+		{"GetMasks", func(d clidecode.Decoder) (any, error) { return d.GetMasks() }},
+		{"GetROAs", func(d clidecode.Decoder) (any, error) { return d.GetROAs() }},
+		{"GetLargeCommunities", func(d clidecode.Decoder) (any, error) { return d.GetLargeCommunities() }},
+		{"GetIPv4FromSource", func(d clidecode.Decoder) (any, error) { return d.GetIPv4FromSource(13335) }},
+		{"GetIPv6FromSource", func(d clidecode.Decoder) (any, error) { return d.GetIPv6FromSource(13335) }},
+		{"GetInvalids", func(d clidecode.Decoder) (any, error) { return d.GetInvalids() }},
+		{"GetVRPs", func(d clidecode.Decoder) (any, error) { return d.GetVRPs(64496) }},
+		{"GetOriginFromIP", func(d clidecode.Decoder) (any, error) {
+			asn, ok, err := d.GetOriginFromIP(net.ParseIP("2606:4700::1"))
+			return []any{asn, ok}, err
+		}},
+		{"GetOriginFromIP not found", func(d clidecode.Decoder) (any, error) {
+			asn, ok, err := d.GetOriginFromIP(net.ParseIP("203.0.113.1"))
+			return []any{asn, ok}, err
+		}},
+		{"GetASPathFromIP", func(d clidecode.Decoder) (any, error) {
+			path, ok, err := d.GetASPathFromIP(net.ParseIP("2001:db8::1"))
+			return []any{path, ok}, err
+		}},
+		{"GetRoute", func(d clidecode.Decoder) (any, error) {
+			prefix, ok, err := d.GetRoute(net.ParseIP("8.8.8.8"))
+			return []any{prefix, ok}, err
+		}},
+		{"GetRoute not found", func(d clidecode.Decoder) (any, error) {
+			prefix, ok, err := d.GetRoute(net.ParseIP("203.0.113.1"))
+			return []any{prefix, ok}, err
+		}},
+		{"GetROA", func(d clidecode.Decoder) (any, error) {
+			state, ok, err := d.GetROA(roaPrefix, 64511)
+			return []any{state, ok}, err
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := tc.call(fake)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tc.call(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantJSON, _ := json.Marshal(want)
+			gotJSON, _ := json.Marshal(got)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("got %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestStreams(t *testing.T) {
+	client := dial(t, clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"), nil, nil)
+
+	prefixes, err := client.GetIPv4FromSource(13335)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prefixes) != 1 || prefixes[0].String() != "1.0.0.0/24" {
+		t.Errorf("GetIPv4FromSource(13335) = %v, want [1.0.0.0/24]", prefixes)
+	}
+
+	prefixes, err = client.GetIPv6FromSource(64500)
+	if err != nil || len(prefixes) != 0 {
+		t.Errorf("GetIPv6FromSource(64500) = %v, %v, want no prefixes", prefixes, err)
+	}
+
+	invalids, err := client.GetInvalids()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(invalids["64511"], ","); got != "192.0.2.0/24" {
+		t.Errorf("GetInvalids()[64511] = %s, want 192.0.2.0/24", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	fake := clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json")
+	fake.Errors = map[string]error{
+		"GetPeers":    clidecode.ErrNotSupported,
+		"GetBGPTotal": errors.New("socket gone"),
+		"GetInvalids": errors.New("table missing"),
+	}
+	fake.Latency = map[string]time.Duration{"GetMasks": time.Second}
+	client := dial(t, fake, nil, nil)
+	client.Timeout = 50 * time.Millisecond
+
+	if _, err := client.GetPeers(); !errors.Is(err, clidecode.ErrNotSupported) {
+		t.Errorf("GetPeers() error = %v, want ErrNotSupported", err)
+	}
+	if _, err := client.GetBGPTotal(); status.Code(err) != codes.Unknown || !strings.Contains(err.Error(), "socket gone") {
+		t.Errorf("GetBGPTotal() error = %v, want socket gone", err)
+	}
+	if _, err := client.GetInvalids(); err == nil || !strings.Contains(err.Error(), "table missing") {
+		t.Errorf("GetInvalids() error = %v, want table missing", err)
+	}
+	if _, err := client.GetMasks(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetMasks() error = %v, want DeadlineExceeded", err)
+	}
+	if _, _, err := client.GetROA(nil, 64511); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetROA(nil) error = %v, want InvalidArgument", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetLargeCommunitiesContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetLargeCommunitiesContext() error = %v, want Canceled", err)
+	}
+}
+
+func TestReflection(t *testing.T) {
+	client := dial(t, clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"), nil, nil)
+	if ServiceName != remotepb.Decoder_ServiceDesc.ServiceName {
+		t.Fatalf("ServiceName = %s, decoder.proto has %s", ServiceName, remotepb.Decoder_ServiceDesc.ServiceName)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(client.Conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.CloseSend()
+	req := &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: ServiceName},
+	}
+	if err := stream.Send(req); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := resp.GetFileDescriptorResponse().GetFileDescriptorProto()
+	if len(files) == 0 {
+		t.Fatalf("no file describes %s: %v", ServiceName, resp)
+	}
+	var fd descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(files[0], &fd); err != nil {
+		t.Fatal(err)
+	}
+	if fd.GetName() != "decoder.proto" || len(fd.GetService()) != 1 || len(fd.GetService()[0].GetMethod()) != 14 {
+		t.Errorf("reflection described %s with services %v", fd.GetName(), fd.GetService())
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	writeCerts(t, dir)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	serverCreds, err := ServerTLS(path("server.pem"), path("server.key"), path("ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := []grpc.ServerOption{grpc.Creds(serverCreds)}
+
+	creds, err := ClientTLS(path("ca.pem"), path("client.pem"), path("client.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := dial(t, clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"), server, creds)
+	if _, err := client.GetBGPTotal(); err != nil {
+		t.Errorf("with a client certificate: %v", err)
+	}
+
+	creds, err = ClientTLS(path("ca.pem"), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = dial(t, clidecode.MustLoadFakeConn(t, "../testdata/fakeconn.json"), server, creds)
+	if _, err := client.GetBGPTotal(); err == nil {
+		t.Error("without a client certificate: no error")
+	}
+
+	if _, err := ClientTLS(path("server.key"), "", ""); err == nil {
+		t.Error("ClientTLS with no certificate in the CA file: no error")
+	}
+}
+
+// writeCerts writes a CA, and a server and client certificate it signed, to dir
+func writeCerts(t *testing.T, dir string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", caDER)
+
+	for i, name := range []string{"server", "client"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"bufnet"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, cert, ca, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writePEM(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+		writePEM(t, filepath.Join(dir, name+".key"), "PRIVATE KEY", keyDER)
+	}
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
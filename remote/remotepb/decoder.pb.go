@@ -0,0 +1,1350 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: decoder.proto
+
+package remotepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ROAState has the values of the clidecode RUnknown, RValid and RInvalid
+type ROAState int32
+
+const (
+	ROAState_ROA_UNKNOWN ROAState = 0
+	ROAState_ROA_VALID   ROAState = 1
+	ROAState_ROA_INVALID ROAState = 2
+)
+
+// Enum value maps for ROAState.
+var (
+	ROAState_name = map[int32]string{
+		0: "ROA_UNKNOWN",
+		1: "ROA_VALID",
+		2: "ROA_INVALID",
+	}
+	ROAState_value = map[string]int32{
+		"ROA_UNKNOWN": 0,
+		"ROA_VALID":   1,
+		"ROA_INVALID": 2,
+	}
+)
+
+func (x ROAState) Enum() *ROAState {
+	p := new(ROAState)
+	*p = x
+	return p
+}
+
+func (x ROAState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ROAState) Descriptor() protoreflect.EnumDescriptor {
+	return file_decoder_proto_enumTypes[0].Descriptor()
+}
+
+func (ROAState) Type() protoreflect.EnumType {
+	return &file_decoder_proto_enumTypes[0]
+}
+
+func (x ROAState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ROAState.Descriptor instead.
+func (ROAState) EnumDescriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{0}
+}
+
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_decoder_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{0}
+}
+
+type IPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IPRequest) Reset() {
+	*x = IPRequest{}
+	mi := &file_decoder_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPRequest) ProtoMessage() {}
+
+func (x *IPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPRequest.ProtoReflect.Descriptor instead.
+func (*IPRequest) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IPRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type ASNRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asn           uint32                 `protobuf:"varint,1,opt,name=asn,proto3" json:"asn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ASNRequest) Reset() {
+	*x = ASNRequest{}
+	mi := &file_decoder_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ASNRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ASNRequest) ProtoMessage() {}
+
+func (x *ASNRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ASNRequest.ProtoReflect.Descriptor instead.
+func (*ASNRequest) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ASNRequest) GetAsn() uint32 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+type ROARequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Asn           uint32                 `protobuf:"varint,2,opt,name=asn,proto3" json:"asn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ROARequest) Reset() {
+	*x = ROARequest{}
+	mi := &file_decoder_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ROARequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ROARequest) ProtoMessage() {}
+
+func (x *ROARequest) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ROARequest.ProtoReflect.Descriptor instead.
+func (*ROARequest) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ROARequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ROARequest) GetAsn() uint32 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+type Totals struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	V4Rib         uint32                 `protobuf:"varint,1,opt,name=v4_rib,json=v4Rib,proto3" json:"v4_rib,omitempty"`
+	V4Fib         uint32                 `protobuf:"varint,2,opt,name=v4_fib,json=v4Fib,proto3" json:"v4_fib,omitempty"`
+	V6Rib         uint32                 `protobuf:"varint,3,opt,name=v6_rib,json=v6Rib,proto3" json:"v6_rib,omitempty"`
+	V6Fib         uint32                 `protobuf:"varint,4,opt,name=v6_fib,json=v6Fib,proto3" json:"v6_fib,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Totals) Reset() {
+	*x = Totals{}
+	mi := &file_decoder_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Totals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Totals) ProtoMessage() {}
+
+func (x *Totals) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Totals.ProtoReflect.Descriptor instead.
+func (*Totals) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Totals) GetV4Rib() uint32 {
+	if x != nil {
+		return x.V4Rib
+	}
+	return 0
+}
+
+func (x *Totals) GetV4Fib() uint32 {
+	if x != nil {
+		return x.V4Fib
+	}
+	return 0
+}
+
+func (x *Totals) GetV6Rib() uint32 {
+	if x != nil {
+		return x.V6Rib
+	}
+	return 0
+}
+
+func (x *Totals) GetV6Fib() uint32 {
+	if x != nil {
+		return x.V6Fib
+	}
+	return 0
+}
+
+type Peers struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	V4Configured  uint32                 `protobuf:"varint,1,opt,name=v4_configured,json=v4Configured,proto3" json:"v4_configured,omitempty"`
+	V4Established uint32                 `protobuf:"varint,2,opt,name=v4_established,json=v4Established,proto3" json:"v4_established,omitempty"`
+	V6Configured  uint32                 `protobuf:"varint,3,opt,name=v6_configured,json=v6Configured,proto3" json:"v6_configured,omitempty"`
+	V6Established uint32                 `protobuf:"varint,4,opt,name=v6_established,json=v6Established,proto3" json:"v6_established,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Peers) Reset() {
+	*x = Peers{}
+	mi := &file_decoder_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Peers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Peers) ProtoMessage() {}
+
+func (x *Peers) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Peers.ProtoReflect.Descriptor instead.
+func (*Peers) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Peers) GetV4Configured() uint32 {
+	if x != nil {
+		return x.V4Configured
+	}
+	return 0
+}
+
+func (x *Peers) GetV4Established() uint32 {
+	if x != nil {
+		return x.V4Established
+	}
+	return 0
+}
+
+func (x *Peers) GetV6Configured() uint32 {
+	if x != nil {
+		return x.V6Configured
+	}
+	return 0
+}
+
+func (x *Peers) GetV6Established() uint32 {
+	if x != nil {
+		return x.V6Established
+	}
+	return 0
+}
+
+type ASNs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	As4           uint32                 `protobuf:"varint,1,opt,name=as4,proto3" json:"as4,omitempty"`
+	As6           uint32                 `protobuf:"varint,2,opt,name=as6,proto3" json:"as6,omitempty"`
+	As10          uint32                 `protobuf:"varint,3,opt,name=as10,proto3" json:"as10,omitempty"`
+	As4Only       uint32                 `protobuf:"varint,4,opt,name=as4_only,json=as4Only,proto3" json:"as4_only,omitempty"`
+	As6Only       uint32                 `protobuf:"varint,5,opt,name=as6_only,json=as6Only,proto3" json:"as6_only,omitempty"`
+	AsBoth        uint32                 `protobuf:"varint,6,opt,name=as_both,json=asBoth,proto3" json:"as_both,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ASNs) Reset() {
+	*x = ASNs{}
+	mi := &file_decoder_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ASNs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ASNs) ProtoMessage() {}
+
+func (x *ASNs) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ASNs.ProtoReflect.Descriptor instead.
+func (*ASNs) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ASNs) GetAs4() uint32 {
+	if x != nil {
+		return x.As4
+	}
+	return 0
+}
+
+func (x *ASNs) GetAs6() uint32 {
+	if x != nil {
+		return x.As6
+	}
+	return 0
+}
+
+func (x *ASNs) GetAs10() uint32 {
+	if x != nil {
+		return x.As10
+	}
+	return 0
+}
+
+func (x *ASNs) GetAs4Only() uint32 {
+	if x != nil {
+		return x.As4Only
+	}
+	return 0
+}
+
+func (x *ASNs) GetAs6Only() uint32 {
+	if x != nil {
+		return x.As6Only
+	}
+	return 0
+}
+
+func (x *ASNs) GetAsBoth() uint32 {
+	if x != nil {
+		return x.AsBoth
+	}
+	return 0
+}
+
+type Roas struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	V4Valid       uint32                 `protobuf:"varint,1,opt,name=v4_valid,json=v4Valid,proto3" json:"v4_valid,omitempty"`
+	V4Invalid     uint32                 `protobuf:"varint,2,opt,name=v4_invalid,json=v4Invalid,proto3" json:"v4_invalid,omitempty"`
+	V4Unknown     uint32                 `protobuf:"varint,3,opt,name=v4_unknown,json=v4Unknown,proto3" json:"v4_unknown,omitempty"`
+	V6Valid       uint32                 `protobuf:"varint,4,opt,name=v6_valid,json=v6Valid,proto3" json:"v6_valid,omitempty"`
+	V6Invalid     uint32                 `protobuf:"varint,5,opt,name=v6_invalid,json=v6Invalid,proto3" json:"v6_invalid,omitempty"`
+	V6Unknown     uint32                 `protobuf:"varint,6,opt,name=v6_unknown,json=v6Unknown,proto3" json:"v6_unknown,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Roas) Reset() {
+	*x = Roas{}
+	mi := &file_decoder_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Roas) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Roas) ProtoMessage() {}
+
+func (x *Roas) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Roas.ProtoReflect.Descriptor instead.
+func (*Roas) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Roas) GetV4Valid() uint32 {
+	if x != nil {
+		return x.V4Valid
+	}
+	return 0
+}
+
+func (x *Roas) GetV4Invalid() uint32 {
+	if x != nil {
+		return x.V4Invalid
+	}
+	return 0
+}
+
+func (x *Roas) GetV4Unknown() uint32 {
+	if x != nil {
+		return x.V4Unknown
+	}
+	return 0
+}
+
+func (x *Roas) GetV6Valid() uint32 {
+	if x != nil {
+		return x.V6Valid
+	}
+	return 0
+}
+
+func (x *Roas) GetV6Invalid() uint32 {
+	if x != nil {
+		return x.V6Invalid
+	}
+	return 0
+}
+
+func (x *Roas) GetV6Unknown() uint32 {
+	if x != nil {
+		return x.V6Unknown
+	}
+	return 0
+}
+
+type Large struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	V4            uint32                 `protobuf:"varint,1,opt,name=v4,proto3" json:"v4,omitempty"`
+	V6            uint32                 `protobuf:"varint,2,opt,name=v6,proto3" json:"v6,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Large) Reset() {
+	*x = Large{}
+	mi := &file_decoder_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Large) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Large) ProtoMessage() {}
+
+func (x *Large) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Large.ProtoReflect.Descriptor instead.
+func (*Large) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Large) GetV4() uint32 {
+	if x != nil {
+		return x.V4
+	}
+	return 0
+}
+
+func (x *Large) GetV6() uint32 {
+	if x != nil {
+		return x.V6
+	}
+	return 0
+}
+
+// Masks holds the number of prefixes of each mask length, keyed by the
+// length. The first table is IPv4, the second IPv6.
+type Masks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Masks         []*Masks_Table         `protobuf:"bytes,1,rep,name=masks,proto3" json:"masks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Masks) Reset() {
+	*x = Masks{}
+	mi := &file_decoder_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Masks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Masks) ProtoMessage() {}
+
+func (x *Masks) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Masks.ProtoReflect.Descriptor instead.
+func (*Masks) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Masks) GetMasks() []*Masks_Table {
+	if x != nil {
+		return x.Masks
+	}
+	return nil
+}
+
+type OriginReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asn           uint32                 `protobuf:"varint,1,opt,name=asn,proto3" json:"asn,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OriginReply) Reset() {
+	*x = OriginReply{}
+	mi := &file_decoder_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OriginReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OriginReply) ProtoMessage() {}
+
+func (x *OriginReply) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OriginReply.ProtoReflect.Descriptor instead.
+func (*OriginReply) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OriginReply) GetAsn() uint32 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+func (x *OriginReply) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type ASPath struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          []uint32               `protobuf:"varint,1,rep,packed,name=path,proto3" json:"path,omitempty"`
+	Set           []uint32               `protobuf:"varint,2,rep,packed,name=set,proto3" json:"set,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ASPath) Reset() {
+	*x = ASPath{}
+	mi := &file_decoder_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ASPath) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ASPath) ProtoMessage() {}
+
+func (x *ASPath) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ASPath.ProtoReflect.Descriptor instead.
+func (*ASPath) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ASPath) GetPath() []uint32 {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *ASPath) GetSet() []uint32 {
+	if x != nil {
+		return x.Set
+	}
+	return nil
+}
+
+type PathReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          *ASPath                `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathReply) Reset() {
+	*x = PathReply{}
+	mi := &file_decoder_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathReply) ProtoMessage() {}
+
+func (x *PathReply) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathReply.ProtoReflect.Descriptor instead.
+func (*PathReply) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PathReply) GetPath() *ASPath {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *PathReply) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type RouteReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteReply) Reset() {
+	*x = RouteReply{}
+	mi := &file_decoder_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteReply) ProtoMessage() {}
+
+func (x *RouteReply) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteReply.ProtoReflect.Descriptor instead.
+func (*RouteReply) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RouteReply) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *RouteReply) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type ROAReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         ROAState               `protobuf:"varint,1,opt,name=state,proto3,enum=clidecode.ROAState" json:"state,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ROAReply) Reset() {
+	*x = ROAReply{}
+	mi := &file_decoder_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ROAReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ROAReply) ProtoMessage() {}
+
+func (x *ROAReply) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ROAReply.ProtoReflect.Descriptor instead.
+func (*ROAReply) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ROAReply) GetState() ROAState {
+	if x != nil {
+		return x.State
+	}
+	return ROAState_ROA_UNKNOWN
+}
+
+func (x *ROAReply) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type VRP struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	MaxLength     uint32                 `protobuf:"varint,2,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VRP) Reset() {
+	*x = VRP{}
+	mi := &file_decoder_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VRP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VRP) ProtoMessage() {}
+
+func (x *VRP) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VRP.ProtoReflect.Descriptor instead.
+func (*VRP) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *VRP) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *VRP) GetMaxLength() uint32 {
+	if x != nil {
+		return x.MaxLength
+	}
+	return 0
+}
+
+type VRPsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vrps          []*VRP                 `protobuf:"bytes,1,rep,name=vrps,proto3" json:"vrps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VRPsReply) Reset() {
+	*x = VRPsReply{}
+	mi := &file_decoder_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VRPsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VRPsReply) ProtoMessage() {}
+
+func (x *VRPsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VRPsReply.ProtoReflect.Descriptor instead.
+func (*VRPsReply) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *VRPsReply) GetVrps() []*VRP {
+	if x != nil {
+		return x.Vrps
+	}
+	return nil
+}
+
+type PrefixReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrefixReply) Reset() {
+	*x = PrefixReply{}
+	mi := &file_decoder_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrefixReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrefixReply) ProtoMessage() {}
+
+func (x *PrefixReply) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrefixReply.ProtoReflect.Descriptor instead.
+func (*PrefixReply) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PrefixReply) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type InvalidReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asn           uint32                 `protobuf:"varint,1,opt,name=asn,proto3" json:"asn,omitempty"`
+	Prefixes      []string               `protobuf:"bytes,2,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvalidReply) Reset() {
+	*x = InvalidReply{}
+	mi := &file_decoder_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvalidReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvalidReply) ProtoMessage() {}
+
+func (x *InvalidReply) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvalidReply.ProtoReflect.Descriptor instead.
+func (*InvalidReply) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *InvalidReply) GetAsn() uint32 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+func (x *InvalidReply) GetPrefixes() []string {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+type Masks_Table struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Counts        map[string]uint32      `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Masks_Table) Reset() {
+	*x = Masks_Table{}
+	mi := &file_decoder_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Masks_Table) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Masks_Table) ProtoMessage() {}
+
+func (x *Masks_Table) ProtoReflect() protoreflect.Message {
+	mi := &file_decoder_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Masks_Table.ProtoReflect.Descriptor instead.
+func (*Masks_Table) Descriptor() ([]byte, []int) {
+	return file_decoder_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *Masks_Table) GetCounts() map[string]uint32 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+var File_decoder_proto protoreflect.FileDescriptor
+
+const file_decoder_proto_rawDesc = "" +
+	"\n" +
+	"\rdecoder.proto\x12\tclidecode\"\a\n" +
+	"\x05Empty\"\x1b\n" +
+	"\tIPRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"\x1e\n" +
+	"\n" +
+	"ASNRequest\x12\x10\n" +
+	"\x03asn\x18\x01 \x01(\rR\x03asn\"6\n" +
+	"\n" +
+	"ROARequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x10\n" +
+	"\x03asn\x18\x02 \x01(\rR\x03asn\"d\n" +
+	"\x06Totals\x12\x15\n" +
+	"\x06v4_rib\x18\x01 \x01(\rR\x05v4Rib\x12\x15\n" +
+	"\x06v4_fib\x18\x02 \x01(\rR\x05v4Fib\x12\x15\n" +
+	"\x06v6_rib\x18\x03 \x01(\rR\x05v6Rib\x12\x15\n" +
+	"\x06v6_fib\x18\x04 \x01(\rR\x05v6Fib\"\x9f\x01\n" +
+	"\x05Peers\x12#\n" +
+	"\rv4_configured\x18\x01 \x01(\rR\fv4Configured\x12%\n" +
+	"\x0ev4_established\x18\x02 \x01(\rR\rv4Established\x12#\n" +
+	"\rv6_configured\x18\x03 \x01(\rR\fv6Configured\x12%\n" +
+	"\x0ev6_established\x18\x04 \x01(\rR\rv6Established\"\x8d\x01\n" +
+	"\x04ASNs\x12\x10\n" +
+	"\x03as4\x18\x01 \x01(\rR\x03as4\x12\x10\n" +
+	"\x03as6\x18\x02 \x01(\rR\x03as6\x12\x12\n" +
+	"\x04as10\x18\x03 \x01(\rR\x04as10\x12\x19\n" +
+	"\bas4_only\x18\x04 \x01(\rR\aas4Only\x12\x19\n" +
+	"\bas6_only\x18\x05 \x01(\rR\aas6Only\x12\x17\n" +
+	"\aas_both\x18\x06 \x01(\rR\x06asBoth\"\xb8\x01\n" +
+	"\x04Roas\x12\x19\n" +
+	"\bv4_valid\x18\x01 \x01(\rR\av4Valid\x12\x1d\n" +
+	"\n" +
+	"v4_invalid\x18\x02 \x01(\rR\tv4Invalid\x12\x1d\n" +
+	"\n" +
+	"v4_unknown\x18\x03 \x01(\rR\tv4Unknown\x12\x19\n" +
+	"\bv6_valid\x18\x04 \x01(\rR\av6Valid\x12\x1d\n" +
+	"\n" +
+	"v6_invalid\x18\x05 \x01(\rR\tv6Invalid\x12\x1d\n" +
+	"\n" +
+	"v6_unknown\x18\x06 \x01(\rR\tv6Unknown\"'\n" +
+	"\x05Large\x12\x0e\n" +
+	"\x02v4\x18\x01 \x01(\rR\x02v4\x12\x0e\n" +
+	"\x02v6\x18\x02 \x01(\rR\x02v6\"\xb5\x01\n" +
+	"\x05Masks\x12,\n" +
+	"\x05masks\x18\x01 \x03(\v2\x16.clidecode.Masks.TableR\x05masks\x1a~\n" +
+	"\x05Table\x12:\n" +
+	"\x06counts\x18\x01 \x03(\v2\".clidecode.Masks.Table.CountsEntryR\x06counts\x1a9\n" +
+	"\vCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\rR\x05value:\x028\x01\"5\n" +
+	"\vOriginReply\x12\x10\n" +
+	"\x03asn\x18\x01 \x01(\rR\x03asn\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\".\n" +
+	"\x06ASPath\x12\x12\n" +
+	"\x04path\x18\x01 \x03(\rR\x04path\x12\x10\n" +
+	"\x03set\x18\x02 \x03(\rR\x03set\"H\n" +
+	"\tPathReply\x12%\n" +
+	"\x04path\x18\x01 \x01(\v2\x11.clidecode.ASPathR\x04path\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\":\n" +
+	"\n" +
+	"RouteReply\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"K\n" +
+	"\bROAReply\x12)\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x13.clidecode.ROAStateR\x05state\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"<\n" +
+	"\x03VRP\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x1d\n" +
+	"\n" +
+	"max_length\x18\x02 \x01(\rR\tmaxLength\"/\n" +
+	"\tVRPsReply\x12\"\n" +
+	"\x04vrps\x18\x01 \x03(\v2\x0e.clidecode.VRPR\x04vrps\"%\n" +
+	"\vPrefixReply\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\"<\n" +
+	"\fInvalidReply\x12\x10\n" +
+	"\x03asn\x18\x01 \x01(\rR\x03asn\x12\x1a\n" +
+	"\bprefixes\x18\x02 \x03(\tR\bprefixes*;\n" +
+	"\bROAState\x12\x0f\n" +
+	"\vROA_UNKNOWN\x10\x00\x12\r\n" +
+	"\tROA_VALID\x10\x01\x12\x0f\n" +
+	"\vROA_INVALID\x10\x022\xae\x06\n" +
+	"\aDecoder\x122\n" +
+	"\vGetBGPTotal\x12\x10.clidecode.Empty\x1a\x11.clidecode.Totals\x12.\n" +
+	"\bGetPeers\x12\x10.clidecode.Empty\x1a\x10.clidecode.Peers\x127\n" +
+	"\x12GetTotalSourceASNs\x12\x10.clidecode.Empty\x1a\x0f.clidecode.ASNs\x12.\n" +
+	"\bGetMasks\x12\x10.clidecode.Empty\x1a\x10.clidecode.Masks\x12,\n" +
+	"\aGetROAs\x12\x10.clidecode.Empty\x1a\x0f.clidecode.Roas\x129\n" +
+	"\x13GetLargeCommunities\x12\x10.clidecode.Empty\x1a\x10.clidecode.Large\x12?\n" +
+	"\x0fGetOriginFromIP\x12\x14.clidecode.IPRequest\x1a\x16.clidecode.OriginReply\x12=\n" +
+	"\x0fGetASPathFromIP\x12\x14.clidecode.IPRequest\x1a\x14.clidecode.PathReply\x127\n" +
+	"\bGetRoute\x12\x14.clidecode.IPRequest\x1a\x15.clidecode.RouteReply\x124\n" +
+	"\x06GetROA\x12\x15.clidecode.ROARequest\x1a\x13.clidecode.ROAReply\x126\n" +
+	"\aGetVRPs\x12\x15.clidecode.ASNRequest\x1a\x14.clidecode.VRPsReply\x12D\n" +
+	"\x11GetIPv4FromSource\x12\x15.clidecode.ASNRequest\x1a\x16.clidecode.PrefixReply0\x01\x12D\n" +
+	"\x11GetIPv6FromSource\x12\x15.clidecode.ASNRequest\x1a\x16.clidecode.PrefixReply0\x01\x12:\n" +
+	"\vGetInvalids\x12\x10.clidecode.Empty\x1a\x17.clidecode.InvalidReply0\x01B4Z2github.com/mellowdrifter/clidecode/remote/remotepbb\x06proto3"
+
+var (
+	file_decoder_proto_rawDescOnce sync.Once
+	file_decoder_proto_rawDescData []byte
+)
+
+func file_decoder_proto_rawDescGZIP() []byte {
+	file_decoder_proto_rawDescOnce.Do(func() {
+		file_decoder_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_decoder_proto_rawDesc), len(file_decoder_proto_rawDesc)))
+	})
+	return file_decoder_proto_rawDescData
+}
+
+var file_decoder_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_decoder_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_decoder_proto_goTypes = []any{
+	(ROAState)(0),        // 0: clidecode.ROAState
+	(*Empty)(nil),        // 1: clidecode.Empty
+	(*IPRequest)(nil),    // 2: clidecode.IPRequest
+	(*ASNRequest)(nil),   // 3: clidecode.ASNRequest
+	(*ROARequest)(nil),   // 4: clidecode.ROARequest
+	(*Totals)(nil),       // 5: clidecode.Totals
+	(*Peers)(nil),        // 6: clidecode.Peers
+	(*ASNs)(nil),         // 7: clidecode.ASNs
+	(*Roas)(nil),         // 8: clidecode.Roas
+	(*Large)(nil),        // 9: clidecode.Large
+	(*Masks)(nil),        // 10: clidecode.Masks
+	(*OriginReply)(nil),  // 11: clidecode.OriginReply
+	(*ASPath)(nil),       // 12: clidecode.ASPath
+	(*PathReply)(nil),    // 13: clidecode.PathReply
+	(*RouteReply)(nil),   // 14: clidecode.RouteReply
+	(*ROAReply)(nil),     // 15: clidecode.ROAReply
+	(*VRP)(nil),          // 16: clidecode.VRP
+	(*VRPsReply)(nil),    // 17: clidecode.VRPsReply
+	(*PrefixReply)(nil),  // 18: clidecode.PrefixReply
+	(*InvalidReply)(nil), // 19: clidecode.InvalidReply
+	(*Masks_Table)(nil),  // 20: clidecode.Masks.Table
+	nil,                  // 21: clidecode.Masks.Table.CountsEntry
+}
+var file_decoder_proto_depIdxs = []int32{
+	20, // 0: clidecode.Masks.masks:type_name -> clidecode.Masks.Table
+	12, // 1: clidecode.PathReply.path:type_name -> clidecode.ASPath
+	0,  // 2: clidecode.ROAReply.state:type_name -> clidecode.ROAState
+	16, // 3: clidecode.VRPsReply.vrps:type_name -> clidecode.VRP
+	21, // 4: clidecode.Masks.Table.counts:type_name -> clidecode.Masks.Table.CountsEntry
+	1,  // 5: clidecode.Decoder.GetBGPTotal:input_type -> clidecode.Empty
+	1,  // 6: clidecode.Decoder.GetPeers:input_type -> clidecode.Empty
+	1,  // 7: clidecode.Decoder.GetTotalSourceASNs:input_type -> clidecode.Empty
+	1,  // 8: clidecode.Decoder.GetMasks:input_type -> clidecode.Empty
+	1,  // 9: clidecode.Decoder.GetROAs:input_type -> clidecode.Empty
+	1,  // 10: clidecode.Decoder.GetLargeCommunities:input_type -> clidecode.Empty
+	2,  // 11: clidecode.Decoder.GetOriginFromIP:input_type -> clidecode.IPRequest
+	2,  // 12: clidecode.Decoder.GetASPathFromIP:input_type -> clidecode.IPRequest
+	2,  // 13: clidecode.Decoder.GetRoute:input_type -> clidecode.IPRequest
+	4,  // 14: clidecode.Decoder.GetROA:input_type -> clidecode.ROARequest
+	3,  // 15: clidecode.Decoder.GetVRPs:input_type -> clidecode.ASNRequest
+	3,  // 16: clidecode.Decoder.GetIPv4FromSource:input_type -> clidecode.ASNRequest
+	3,  // 17: clidecode.Decoder.GetIPv6FromSource:input_type -> clidecode.ASNRequest
+	1,  // 18: clidecode.Decoder.GetInvalids:input_type -> clidecode.Empty
+	5,  // 19: clidecode.Decoder.GetBGPTotal:output_type -> clidecode.Totals
+	6,  // 20: clidecode.Decoder.GetPeers:output_type -> clidecode.Peers
+	7,  // 21: clidecode.Decoder.GetTotalSourceASNs:output_type -> clidecode.ASNs
+	10, // 22: clidecode.Decoder.GetMasks:output_type -> clidecode.Masks
+	8,  // 23: clidecode.Decoder.GetROAs:output_type -> clidecode.Roas
+	9,  // 24: clidecode.Decoder.GetLargeCommunities:output_type -> clidecode.Large
+	11, // 25: clidecode.Decoder.GetOriginFromIP:output_type -> clidecode.OriginReply
+	13, // 26: clidecode.Decoder.GetASPathFromIP:output_type -> clidecode.PathReply
+	14, // 27: clidecode.Decoder.GetRoute:output_type -> clidecode.RouteReply
+	15, // 28: clidecode.Decoder.GetROA:output_type -> clidecode.ROAReply
+	17, // 29: clidecode.Decoder.GetVRPs:output_type -> clidecode.VRPsReply
+	18, // 30: clidecode.Decoder.GetIPv4FromSource:output_type -> clidecode.PrefixReply
+	18, // 31: clidecode.Decoder.GetIPv6FromSource:output_type -> clidecode.PrefixReply
+	19, // 32: clidecode.Decoder.GetInvalids:output_type -> clidecode.InvalidReply
+	19, // [19:33] is the sub-list for method output_type
+	5,  // [5:19] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_decoder_proto_init() }
+func file_decoder_proto_init() {
+	if File_decoder_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_decoder_proto_rawDesc), len(file_decoder_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_decoder_proto_goTypes,
+		DependencyIndexes: file_decoder_proto_depIdxs,
+		EnumInfos:         file_decoder_proto_enumTypes,
+		MessageInfos:      file_decoder_proto_msgTypes,
+	}.Build()
+	File_decoder_proto = out.File
+	file_decoder_proto_goTypes = nil
+	file_decoder_proto_depIdxs = nil
+}
@@ -0,0 +1,645 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: decoder.proto
+
+package remotepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Decoder_GetBGPTotal_FullMethodName         = "/clidecode.Decoder/GetBGPTotal"
+	Decoder_GetPeers_FullMethodName            = "/clidecode.Decoder/GetPeers"
+	Decoder_GetTotalSourceASNs_FullMethodName  = "/clidecode.Decoder/GetTotalSourceASNs"
+	Decoder_GetMasks_FullMethodName            = "/clidecode.Decoder/GetMasks"
+	Decoder_GetROAs_FullMethodName             = "/clidecode.Decoder/GetROAs"
+	Decoder_GetLargeCommunities_FullMethodName = "/clidecode.Decoder/GetLargeCommunities"
+	Decoder_GetOriginFromIP_FullMethodName     = "/clidecode.Decoder/GetOriginFromIP"
+	Decoder_GetASPathFromIP_FullMethodName     = "/clidecode.Decoder/GetASPathFromIP"
+	Decoder_GetRoute_FullMethodName            = "/clidecode.Decoder/GetRoute"
+	Decoder_GetROA_FullMethodName              = "/clidecode.Decoder/GetROA"
+	Decoder_GetVRPs_FullMethodName             = "/clidecode.Decoder/GetVRPs"
+	Decoder_GetIPv4FromSource_FullMethodName   = "/clidecode.Decoder/GetIPv4FromSource"
+	Decoder_GetIPv6FromSource_FullMethodName   = "/clidecode.Decoder/GetIPv6FromSource"
+	Decoder_GetInvalids_FullMethodName         = "/clidecode.Decoder/GetInvalids"
+)
+
+// DecoderClient is the client API for Decoder service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Decoder answers the queries of a clidecode.Decoder from a router on another
+// host. Prefixes are in CIDR notation and IP addresses in their usual text
+// form, IPv4 or IPv6.
+type DecoderClient interface {
+	GetBGPTotal(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Totals, error)
+	GetPeers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Peers, error)
+	GetTotalSourceASNs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ASNs, error)
+	GetMasks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Masks, error)
+	GetROAs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Roas, error)
+	GetLargeCommunities(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Large, error)
+	GetOriginFromIP(ctx context.Context, in *IPRequest, opts ...grpc.CallOption) (*OriginReply, error)
+	GetASPathFromIP(ctx context.Context, in *IPRequest, opts ...grpc.CallOption) (*PathReply, error)
+	GetRoute(ctx context.Context, in *IPRequest, opts ...grpc.CallOption) (*RouteReply, error)
+	GetROA(ctx context.Context, in *ROARequest, opts ...grpc.CallOption) (*ROAReply, error)
+	GetVRPs(ctx context.Context, in *ASNRequest, opts ...grpc.CallOption) (*VRPsReply, error)
+	// GetIPv4FromSource sends the IPv4 prefixes originated by an ASN, a prefix
+	// at a time
+	GetIPv4FromSource(ctx context.Context, in *ASNRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrefixReply], error)
+	// GetIPv6FromSource sends the IPv6 prefixes originated by an ASN, a prefix
+	// at a time
+	GetIPv6FromSource(ctx context.Context, in *ASNRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrefixReply], error)
+	// GetInvalids sends the RPKI invalid prefixes of each origin ASN, an ASN at
+	// a time in ascending order
+	GetInvalids(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InvalidReply], error)
+}
+
+type decoderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDecoderClient(cc grpc.ClientConnInterface) DecoderClient {
+	return &decoderClient{cc}
+}
+
+func (c *decoderClient) GetBGPTotal(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Totals, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Totals)
+	err := c.cc.Invoke(ctx, Decoder_GetBGPTotal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetPeers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Peers, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Peers)
+	err := c.cc.Invoke(ctx, Decoder_GetPeers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetTotalSourceASNs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ASNs, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ASNs)
+	err := c.cc.Invoke(ctx, Decoder_GetTotalSourceASNs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetMasks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Masks, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Masks)
+	err := c.cc.Invoke(ctx, Decoder_GetMasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetROAs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Roas, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Roas)
+	err := c.cc.Invoke(ctx, Decoder_GetROAs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetLargeCommunities(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Large, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Large)
+	err := c.cc.Invoke(ctx, Decoder_GetLargeCommunities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetOriginFromIP(ctx context.Context, in *IPRequest, opts ...grpc.CallOption) (*OriginReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OriginReply)
+	err := c.cc.Invoke(ctx, Decoder_GetOriginFromIP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetASPathFromIP(ctx context.Context, in *IPRequest, opts ...grpc.CallOption) (*PathReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PathReply)
+	err := c.cc.Invoke(ctx, Decoder_GetASPathFromIP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetRoute(ctx context.Context, in *IPRequest, opts ...grpc.CallOption) (*RouteReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RouteReply)
+	err := c.cc.Invoke(ctx, Decoder_GetRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetROA(ctx context.Context, in *ROARequest, opts ...grpc.CallOption) (*ROAReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ROAReply)
+	err := c.cc.Invoke(ctx, Decoder_GetROA_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetVRPs(ctx context.Context, in *ASNRequest, opts ...grpc.CallOption) (*VRPsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VRPsReply)
+	err := c.cc.Invoke(ctx, Decoder_GetVRPs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoderClient) GetIPv4FromSource(ctx context.Context, in *ASNRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrefixReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Decoder_ServiceDesc.Streams[0], Decoder_GetIPv4FromSource_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ASNRequest, PrefixReply]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Decoder_GetIPv4FromSourceClient = grpc.ServerStreamingClient[PrefixReply]
+
+func (c *decoderClient) GetIPv6FromSource(ctx context.Context, in *ASNRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrefixReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Decoder_ServiceDesc.Streams[1], Decoder_GetIPv6FromSource_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ASNRequest, PrefixReply]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Decoder_GetIPv6FromSourceClient = grpc.ServerStreamingClient[PrefixReply]
+
+func (c *decoderClient) GetInvalids(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InvalidReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Decoder_ServiceDesc.Streams[2], Decoder_GetInvalids_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Empty, InvalidReply]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Decoder_GetInvalidsClient = grpc.ServerStreamingClient[InvalidReply]
+
+// DecoderServer is the server API for Decoder service.
+// All implementations must embed UnimplementedDecoderServer
+// for forward compatibility.
+//
+// Decoder answers the queries of a clidecode.Decoder from a router on another
+// host. Prefixes are in CIDR notation and IP addresses in their usual text
+// form, IPv4 or IPv6.
+type DecoderServer interface {
+	GetBGPTotal(context.Context, *Empty) (*Totals, error)
+	GetPeers(context.Context, *Empty) (*Peers, error)
+	GetTotalSourceASNs(context.Context, *Empty) (*ASNs, error)
+	GetMasks(context.Context, *Empty) (*Masks, error)
+	GetROAs(context.Context, *Empty) (*Roas, error)
+	GetLargeCommunities(context.Context, *Empty) (*Large, error)
+	GetOriginFromIP(context.Context, *IPRequest) (*OriginReply, error)
+	GetASPathFromIP(context.Context, *IPRequest) (*PathReply, error)
+	GetRoute(context.Context, *IPRequest) (*RouteReply, error)
+	GetROA(context.Context, *ROARequest) (*ROAReply, error)
+	GetVRPs(context.Context, *ASNRequest) (*VRPsReply, error)
+	// GetIPv4FromSource sends the IPv4 prefixes originated by an ASN, a prefix
+	// at a time
+	GetIPv4FromSource(*ASNRequest, grpc.ServerStreamingServer[PrefixReply]) error
+	// GetIPv6FromSource sends the IPv6 prefixes originated by an ASN, a prefix
+	// at a time
+	GetIPv6FromSource(*ASNRequest, grpc.ServerStreamingServer[PrefixReply]) error
+	// GetInvalids sends the RPKI invalid prefixes of each origin ASN, an ASN at
+	// a time in ascending order
+	GetInvalids(*Empty, grpc.ServerStreamingServer[InvalidReply]) error
+	mustEmbedUnimplementedDecoderServer()
+}
+
+// UnimplementedDecoderServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDecoderServer struct{}
+
+func (UnimplementedDecoderServer) GetBGPTotal(context.Context, *Empty) (*Totals, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBGPTotal not implemented")
+}
+func (UnimplementedDecoderServer) GetPeers(context.Context, *Empty) (*Peers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeers not implemented")
+}
+func (UnimplementedDecoderServer) GetTotalSourceASNs(context.Context, *Empty) (*ASNs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTotalSourceASNs not implemented")
+}
+func (UnimplementedDecoderServer) GetMasks(context.Context, *Empty) (*Masks, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMasks not implemented")
+}
+func (UnimplementedDecoderServer) GetROAs(context.Context, *Empty) (*Roas, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetROAs not implemented")
+}
+func (UnimplementedDecoderServer) GetLargeCommunities(context.Context, *Empty) (*Large, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLargeCommunities not implemented")
+}
+func (UnimplementedDecoderServer) GetOriginFromIP(context.Context, *IPRequest) (*OriginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOriginFromIP not implemented")
+}
+func (UnimplementedDecoderServer) GetASPathFromIP(context.Context, *IPRequest) (*PathReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetASPathFromIP not implemented")
+}
+func (UnimplementedDecoderServer) GetRoute(context.Context, *IPRequest) (*RouteReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoute not implemented")
+}
+func (UnimplementedDecoderServer) GetROA(context.Context, *ROARequest) (*ROAReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetROA not implemented")
+}
+func (UnimplementedDecoderServer) GetVRPs(context.Context, *ASNRequest) (*VRPsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVRPs not implemented")
+}
+func (UnimplementedDecoderServer) GetIPv4FromSource(*ASNRequest, grpc.ServerStreamingServer[PrefixReply]) error {
+	return status.Errorf(codes.Unimplemented, "method GetIPv4FromSource not implemented")
+}
+func (UnimplementedDecoderServer) GetIPv6FromSource(*ASNRequest, grpc.ServerStreamingServer[PrefixReply]) error {
+	return status.Errorf(codes.Unimplemented, "method GetIPv6FromSource not implemented")
+}
+func (UnimplementedDecoderServer) GetInvalids(*Empty, grpc.ServerStreamingServer[InvalidReply]) error {
+	return status.Errorf(codes.Unimplemented, "method GetInvalids not implemented")
+}
+func (UnimplementedDecoderServer) mustEmbedUnimplementedDecoderServer() {}
+func (UnimplementedDecoderServer) testEmbeddedByValue()                 {}
+
+// UnsafeDecoderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DecoderServer will
+// result in compilation errors.
+type UnsafeDecoderServer interface {
+	mustEmbedUnimplementedDecoderServer()
+}
+
+func RegisterDecoderServer(s grpc.ServiceRegistrar, srv DecoderServer) {
+	// If the following call pancis, it indicates UnimplementedDecoderServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Decoder_ServiceDesc, srv)
+}
+
+func _Decoder_GetBGPTotal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetBGPTotal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetBGPTotal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetBGPTotal(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetPeers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetPeers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetTotalSourceASNs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetTotalSourceASNs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetTotalSourceASNs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetTotalSourceASNs(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetMasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetMasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetMasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetMasks(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetROAs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetROAs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetROAs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetROAs(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetLargeCommunities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetLargeCommunities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetLargeCommunities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetLargeCommunities(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetOriginFromIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetOriginFromIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetOriginFromIP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetOriginFromIP(ctx, req.(*IPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetASPathFromIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetASPathFromIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetASPathFromIP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetASPathFromIP(ctx, req.(*IPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetRoute(ctx, req.(*IPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetROA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ROARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetROA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetROA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetROA(ctx, req.(*ROARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetVRPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ASNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoderServer).GetVRPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Decoder_GetVRPs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoderServer).GetVRPs(ctx, req.(*ASNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Decoder_GetIPv4FromSource_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ASNRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DecoderServer).GetIPv4FromSource(m, &grpc.GenericServerStream[ASNRequest, PrefixReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Decoder_GetIPv4FromSourceServer = grpc.ServerStreamingServer[PrefixReply]
+
+func _Decoder_GetIPv6FromSource_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ASNRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DecoderServer).GetIPv6FromSource(m, &grpc.GenericServerStream[ASNRequest, PrefixReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Decoder_GetIPv6FromSourceServer = grpc.ServerStreamingServer[PrefixReply]
+
+func _Decoder_GetInvalids_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DecoderServer).GetInvalids(m, &grpc.GenericServerStream[Empty, InvalidReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Decoder_GetInvalidsServer = grpc.ServerStreamingServer[InvalidReply]
+
+// Decoder_ServiceDesc is the grpc.ServiceDesc for Decoder service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Decoder_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "clidecode.Decoder",
+	HandlerType: (*DecoderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBGPTotal",
+			Handler:    _Decoder_GetBGPTotal_Handler,
+		},
+		{
+			MethodName: "GetPeers",
+			Handler:    _Decoder_GetPeers_Handler,
+		},
+		{
+			MethodName: "GetTotalSourceASNs",
+			Handler:    _Decoder_GetTotalSourceASNs_Handler,
+		},
+		{
+			MethodName: "GetMasks",
+			Handler:    _Decoder_GetMasks_Handler,
+		},
+		{
+			MethodName: "GetROAs",
+			Handler:    _Decoder_GetROAs_Handler,
+		},
+		{
+			MethodName: "GetLargeCommunities",
+			Handler:    _Decoder_GetLargeCommunities_Handler,
+		},
+		{
+			MethodName: "GetOriginFromIP",
+			Handler:    _Decoder_GetOriginFromIP_Handler,
+		},
+		{
+			MethodName: "GetASPathFromIP",
+			Handler:    _Decoder_GetASPathFromIP_Handler,
+		},
+		{
+			MethodName: "GetRoute",
+			Handler:    _Decoder_GetRoute_Handler,
+		},
+		{
+			MethodName: "GetROA",
+			Handler:    _Decoder_GetROA_Handler,
+		},
+		{
+			MethodName: "GetVRPs",
+			Handler:    _Decoder_GetVRPs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetIPv4FromSource",
+			Handler:       _Decoder_GetIPv4FromSource_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetIPv6FromSource",
+			Handler:       _Decoder_GetIPv6FromSource_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetInvalids",
+			Handler:       _Decoder_GetInvalids_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "decoder.proto",
+}
@@ -0,0 +1,6 @@
+// Package remotepb holds the messages and gRPC stubs of the clidecode.Decoder
+// service, generated from decoder.proto. Clients in other languages should
+// generate theirs from the same file.
+package remotepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative decoder.proto
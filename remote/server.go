@@ -0,0 +1,238 @@
+package remote
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/mellowdrifter/clidecode"
+	"github.com/mellowdrifter/clidecode/remote/remotepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Register adds the service answering from d to s.
+// Each call runs with the deadline set by the client. A Decoder that does not
+// implement DecoderContext is left to finish a query the client gave up on.
+func Register(s grpc.ServiceRegistrar, d clidecode.Decoder) {
+	remotepb.RegisterDecoderServer(s, &server{d: clidecode.WithContext(d)})
+}
+
+// NewServer creates a grpc.Server with opts serving d, and the reflection
+// service describing it
+func NewServer(d clidecode.Decoder, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	Register(s, d)
+	reflection.Register(s)
+	return s
+}
+
+// server answers the calls of the service from d
+type server struct {
+	remotepb.UnimplementedDecoderServer
+	d clidecode.DecoderContext
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid IP address %q", s)
+	}
+	return ip, nil
+}
+
+func (s *server) GetBGPTotal(ctx context.Context, _ *remotepb.Empty) (*remotepb.Totals, error) {
+	t, err := s.d.GetBGPTotalContext(ctx)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.Totals{V4Rib: t.V4Rib, V4Fib: t.V4Fib, V6Rib: t.V6Rib, V6Fib: t.V6Fib}, nil
+}
+
+func (s *server) GetPeers(ctx context.Context, _ *remotepb.Empty) (*remotepb.Peers, error) {
+	p, err := s.d.GetPeersContext(ctx)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.Peers{
+		V4Configured:  p.V4c,
+		V4Established: p.V4e,
+		V6Configured:  p.V6c,
+		V6Established: p.V6e,
+	}, nil
+}
+
+func (s *server) GetTotalSourceASNs(ctx context.Context, _ *remotepb.Empty) (*remotepb.ASNs, error) {
+	a, err := s.d.GetTotalSourceASNsContext(ctx)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.ASNs{
+		As4:     a.As4,
+		As6:     a.As6,
+		As10:    a.As10,
+		As4Only: a.As4Only,
+		As6Only: a.As6Only,
+		AsBoth:  a.AsBoth,
+	}, nil
+}
+
+func (s *server) GetMasks(ctx context.Context, _ *remotepb.Empty) (*remotepb.Masks, error) {
+	masks, err := s.d.GetMasksContext(ctx)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	reply := &remotepb.Masks{}
+	for _, m := range masks {
+		reply.Masks = append(reply.Masks, &remotepb.Masks_Table{Counts: m})
+	}
+	return reply, nil
+}
+
+func (s *server) GetROAs(ctx context.Context, _ *remotepb.Empty) (*remotepb.Roas, error) {
+	r, err := s.d.GetROAsContext(ctx)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.Roas{
+		V4Valid:   r.V4v,
+		V4Invalid: r.V4i,
+		V4Unknown: r.V4u,
+		V6Valid:   r.V6v,
+		V6Invalid: r.V6i,
+		V6Unknown: r.V6u,
+	}, nil
+}
+
+func (s *server) GetLargeCommunities(ctx context.Context, _ *remotepb.Empty) (*remotepb.Large, error) {
+	l, err := s.d.GetLargeCommunitiesContext(ctx)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.Large{V4: l.V4, V6: l.V6}, nil
+}
+
+func (s *server) GetOriginFromIP(ctx context.Context, req *remotepb.IPRequest) (*remotepb.OriginReply, error) {
+	ip, err := parseIP(req.GetIp())
+	if err != nil {
+		return nil, err
+	}
+	asn, ok, err := s.d.GetOriginFromIPContext(ctx, ip)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.OriginReply{Asn: asn, Found: ok}, nil
+}
+
+func (s *server) GetASPathFromIP(ctx context.Context, req *remotepb.IPRequest) (*remotepb.PathReply, error) {
+	ip, err := parseIP(req.GetIp())
+	if err != nil {
+		return nil, err
+	}
+	path, ok, err := s.d.GetASPathFromIPContext(ctx, ip)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.PathReply{Path: &remotepb.ASPath{Path: path.Path, Set: path.Set}, Found: ok}, nil
+}
+
+func (s *server) GetRoute(ctx context.Context, req *remotepb.IPRequest) (*remotepb.RouteReply, error) {
+	ip, err := parseIP(req.GetIp())
+	if err != nil {
+		return nil, err
+	}
+	prefix, ok, err := s.d.GetRouteContext(ctx, ip)
+	if err != nil {
+		return nil, serverError(err)
+	}
+	if !ok || prefix == nil {
+		return &remotepb.RouteReply{}, nil
+	}
+	return &remotepb.RouteReply{Prefix: prefix.String(), Found: true}, nil
+}
+
+func (s *server) GetROA(ctx context.Context, req *remotepb.ROARequest) (*remotepb.ROAReply, error) {
+	_, prefix, err := net.ParseCIDR(req.GetPrefix())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid prefix %q", req.GetPrefix())
+	}
+	state, ok, err := s.d.GetROAContext(ctx, prefix, req.GetAsn())
+	if err != nil {
+		return nil, serverError(err)
+	}
+	return &remotepb.ROAReply{State: remotepb.ROAState(state), Found: ok}, nil
+}
+
+func (s *server) GetVRPs(ctx context.Context, req *remotepb.ASNRequest) (*remotepb.VRPsReply, error) {
+	vrps, err := s.d.GetVRPsContext(ctx, req.GetAsn())
+	if err != nil {
+		return nil, serverError(err)
+	}
+	reply := &remotepb.VRPsReply{}
+	for _, v := range vrps {
+		vrp := &remotepb.VRP{MaxLength: uint32(v.Max)}
+		if v.Prefix != nil {
+			vrp.Prefix = v.Prefix.String()
+		}
+		reply.Vrps = append(reply.Vrps, vrp)
+	}
+	return reply, nil
+}
+
+// GetIPv4FromSource sends the prefixes once the Decoder has returned them all
+func (s *server) GetIPv4FromSource(req *remotepb.ASNRequest, stream grpc.ServerStreamingServer[remotepb.PrefixReply]) error {
+	prefixes, err := s.d.GetIPv4FromSourceContext(stream.Context(), req.GetAsn())
+	if err != nil {
+		return serverError(err)
+	}
+	return sendPrefixes(prefixes, stream)
+}
+
+// GetIPv6FromSource sends the prefixes once the Decoder has returned them all
+func (s *server) GetIPv6FromSource(req *remotepb.ASNRequest, stream grpc.ServerStreamingServer[remotepb.PrefixReply]) error {
+	prefixes, err := s.d.GetIPv6FromSourceContext(stream.Context(), req.GetAsn())
+	if err != nil {
+		return serverError(err)
+	}
+	return sendPrefixes(prefixes, stream)
+}
+
+func sendPrefixes(prefixes []*net.IPNet, stream grpc.ServerStreamingServer[remotepb.PrefixReply]) error {
+	for _, p := range prefixes {
+		if err := stream.Send(&remotepb.PrefixReply{Prefix: p.String()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetInvalids sends an ASN at a time, in order, once the Decoder has returned
+// them all
+func (s *server) GetInvalids(_ *remotepb.Empty, stream grpc.ServerStreamingServer[remotepb.InvalidReply]) error {
+	invalids, err := s.d.GetInvalidsContext(stream.Context())
+	if err != nil {
+		return serverError(err)
+	}
+	type origin struct {
+		asn uint32
+		key string
+	}
+	origins := make([]origin, 0, len(invalids))
+	for key := range invalids {
+		asn, err := strconv.ParseUint(key, 10, 32)
+		if err != nil {
+			return status.Errorf(codes.Internal, "invalid origin ASN %q", key)
+		}
+		origins = append(origins, origin{uint32(asn), key})
+	}
+	sort.Slice(origins, func(i, j int) bool { return origins[i].asn < origins[j].asn })
+	for _, o := range origins {
+		if err := stream.Send(&remotepb.InvalidReply{Asn: o.asn, Prefixes: invalids[o.key]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
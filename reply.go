@@ -0,0 +1,166 @@
+package clidecode
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Reply codes sent by BIRD at the start of each line.
+// 0xxx codes end a reply, 1xxx-2xxx carry data and headers,
+// 8xxx are runtime errors and 9xxx are syntax errors.
+const (
+	CodeOK             = 0
+	CodeWelcome        = 1
+	CodeStatus         = 13
+	CodeRouteCount     = 14
+	CodeVersion        = 1000
+	CodeProtocol       = 1002
+	CodeProtocolDetail = 1006
+	CodeRoute          = 1007
+	CodeRouteDetail    = 1008
+	CodeSymbol         = 1010
+	CodeUptime         = 1011
+	CodeRouteAttribute = 1012
+	CodeProtocolHeader = 2002
+	CodeNotFound       = 8001
+	CodeEvalError      = 8008
+	CodeParseError     = 9001
+
+	// CodeUnknown marks lines whose code is not known, such as those
+	// returned by a Querier, which hands back text with the codes stripped.
+	CodeUnknown = -1
+)
+
+// ReplyLine is a single line of a BIRD reply.
+// Continuation lines carry the code of the line they continue.
+type ReplyLine struct {
+	Code         int
+	Continuation bool
+	Text         string
+}
+
+// ReplyError is an error reply from BIRD, either a runtime error (8xxx) or a syntax error (9xxx).
+type ReplyError struct {
+	Code    int
+	Message string
+}
+
+func (e *ReplyError) Error() string {
+	return fmt.Sprintf("BIRD error: %04d %s", e.Code, e.Message)
+}
+
+// Runtime reports whether BIRD understood the command but failed to run it.
+func (e *ReplyError) Runtime() bool {
+	return e.Code >= 8000 && e.Code < 9000
+}
+
+// Syntax reports whether BIRD failed to parse the command.
+func (e *ReplyError) Syntax() bool {
+	return e.Code >= 9000 && e.Code < 10000
+}
+
+// isReplyError reports whether err is an error reply from BIRD, after which the connection is still usable.
+func isReplyError(err error) bool {
+	var re *ReplyError
+	return errors.As(err, &re)
+}
+
+// isNotFound reports whether err is BIRD saying the network is not in the table.
+func isNotFound(err error) bool {
+	var re *ReplyError
+	return errors.As(err, &re) && re.Code == CodeNotFound
+}
+
+// Replies sends a command and yields every line of the reply, with its code,
+// as it arrives. An error reply from BIRD is yielded as a *ReplyError.
+// With a Querier set, lines are yielded with CodeUnknown.
+func (b *BirdClient) Replies(ctx context.Context, command string) iter.Seq2[ReplyLine, error] {
+	return func(yield func(ReplyLine, error) bool) {
+		err := b.replies(ctx, command, func(rl ReplyLine) error {
+			if !yield(rl, nil) {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopIteration) {
+			yield(ReplyLine{}, err)
+		}
+	}
+}
+
+// ParseReply reads a single reply from r, such as the raw bytes BIRD sent in
+// answer to one command, and yields each of its lines.
+func ParseReply(r io.Reader) iter.Seq2[ReplyLine, error] {
+	return func(yield func(ReplyLine, error) bool) {
+		err := readReplyLines(bufio.NewReader(r), func(rl ReplyLine) error {
+			if !yield(rl, nil) {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopIteration) {
+			yield(ReplyLine{}, err)
+		}
+	}
+}
+
+// readReplyLines reads reply lines until a final status code is received,
+// handing each one to emit as soon as it arrives.
+// If emit returns an error, reading stops and the error is returned.
+func readReplyLines(reader *bufio.Reader, emit func(ReplyLine) error) error {
+	last := CodeUnknown
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		// Remove trailing newline
+		line = strings.TrimRight(line, "\r\n")
+
+		code, text, ok := splitReplyCode(line)
+		if !ok {
+			// Continuation line (starts with space) belongs to the previous code
+			rl := ReplyLine{Code: last, Continuation: true, Text: strings.TrimPrefix(line, " ")}
+			if err := emit(rl); err != nil {
+				return err
+			}
+			continue
+		}
+		last = code
+
+		if code >= 8000 {
+			return &ReplyError{Code: code, Message: text}
+		}
+		if err := emit(ReplyLine{Code: code, Text: text}); err != nil {
+			return err
+		}
+		if code < 1000 {
+			// 0xxx codes complete the reply
+			return nil
+		}
+	}
+}
+
+// splitReplyCode separates a "CODE-text" or "CODE text" line into its parts.
+func splitReplyCode(line string) (int, string, bool) {
+	if len(line) < 4 || (len(line) > 4 && line[4] != '-' && line[4] != ' ') {
+		return 0, "", false
+	}
+	code := 0
+	for _, c := range line[:4] {
+		if c < '0' || c > '9' {
+			return 0, "", false
+		}
+		code = code*10 + int(c-'0')
+	}
+	if len(line) == 4 {
+		return code, "", true
+	}
+	return code, line[5:], true
+}
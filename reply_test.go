@@ -0,0 +1,101 @@
+package clidecode
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const routeReply = "1007-Table master4:\n" +
+	" 1.0.0.0/24           unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS13335i]\n" +
+	" \tvia 192.0.2.1 on eth0\n" +
+	"1008-\tType: BGP univ\n" +
+	"1012-\tBGP.origin: IGP\n" +
+	" \tBGP.as_path: 64496 13335\n" +
+	"1007-8.8.8.0/24           unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS15169i]\n" +
+	"0000 \n"
+
+func TestParseReply(t *testing.T) {
+	var got []ReplyLine
+	for rl, err := range ParseReply(strings.NewReader(routeReply)) {
+		if err != nil {
+			t.Fatalf("ParseReply failed: %v", err)
+		}
+		got = append(got, rl)
+	}
+
+	expected := []ReplyLine{
+		{Code: CodeRoute, Text: "Table master4:"},
+		{Code: CodeRoute, Continuation: true, Text: "1.0.0.0/24           unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS13335i]"},
+		{Code: CodeRoute, Continuation: true, Text: "\tvia 192.0.2.1 on eth0"},
+		{Code: CodeRouteDetail, Text: "\tType: BGP univ"},
+		{Code: CodeRouteAttribute, Text: "\tBGP.origin: IGP"},
+		{Code: CodeRouteAttribute, Continuation: true, Text: "\tBGP.as_path: 64496 13335"},
+		{Code: CodeRoute, Text: "8.8.8.0/24           unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS15169i]"},
+		{Code: CodeOK},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseReply mismatch.\nGot  %+v\nWant %+v", got, expected)
+	}
+}
+
+func TestParseReplyError(t *testing.T) {
+	tests := []struct {
+		reply   string
+		code    int
+		runtime bool
+	}{
+		{"8001 Network not in table\n", CodeNotFound, true},
+		{"9001 syntax error, unexpected CF_SYM_UNDEFINED\n", CodeParseError, false},
+	}
+
+	for _, tc := range tests {
+		var err error
+		for _, err = range ParseReply(strings.NewReader(tc.reply)) {
+		}
+
+		var re *ReplyError
+		if !errors.As(err, &re) {
+			t.Fatalf("Expected *ReplyError, got %v", err)
+		}
+		if re.Code != tc.code {
+			t.Errorf("Expected code %d, got %d", tc.code, re.Code)
+		}
+		if re.Runtime() != tc.runtime || re.Syntax() == tc.runtime {
+			t.Errorf("Wrong error class for %d", re.Code)
+		}
+	}
+}
+
+func TestRouteParserCodes(t *testing.T) {
+	var p routeParser
+	var prefixes []string
+	for rl := range ParseReply(strings.NewReader(routeReply)) {
+		if r, ok := p.feed(rl); ok {
+			prefixes = append(prefixes, r.Prefix.String())
+		}
+	}
+	if r, ok := p.flush(); ok {
+		prefixes = append(prefixes, r.Prefix.String())
+	}
+
+	expected := []string{"1.0.0.0/24", "8.8.8.0/24"}
+	if !reflect.DeepEqual(prefixes, expected) {
+		t.Errorf("Got %v, want %v", prefixes, expected)
+	}
+}
+
+func TestGetRouteNotInTable(t *testing.T) {
+	path, _ := serveBird(t, 0)
+
+	client := NewBird2ConnWithSocket(path)
+	_, found, err := client.GetRoute(net.ParseIP("1.2.3.4"))
+	if err != nil {
+		t.Fatalf("GetRoute failed: %v", err)
+	}
+	if found {
+		t.Error("Expected route to not be found")
+	}
+}
@@ -0,0 +1,271 @@
+package clidecode
+
+import (
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// rib is a routing table reduced to what the aggregate queries need: the
+// primary route of each prefix, and how many paths were seen per family.
+// Backends that cannot ask their daemon for counts directly add their routes
+// to a rib, so that every backend computes Totals, ASNs, Roas and the rest
+// the same way. Primary routes are indexed in a binary trie per address
+// family, for longest prefix matches.
+type rib struct {
+	routes    []ribRoute // primary routes, in table order
+	paths     [2]uint32  // paths added, IPv4 then IPv6
+	tries     [2]*ribNode
+	validator *Validator
+}
+
+// ribNode is a trie node. The path from the root spells out the prefix bits.
+type ribNode struct {
+	child [2]*ribNode
+	route int32 // index in routes plus one of the route ending here, 0 if none
+}
+
+// ribRoute is a single path. The first route added for a prefix is the primary one.
+type ribRoute struct {
+	prefix netip.Prefix
+	asPath []uint32
+	asSet  []uint32
+	large  bool
+
+	// roa holds the origin validation state reported by the daemon, if hasROA is
+	// set. Otherwise it is worked out by the rib validator.
+	roa    int
+	hasROA bool
+}
+
+// origin returns the origin ASN of a route, or 0 if it ends in an AS set.
+func (r ribRoute) origin() uint32 {
+	if len(r.asSet) > 0 || len(r.asPath) == 0 {
+		return 0
+	}
+	return r.asPath[len(r.asPath)-1]
+}
+
+// AS_PATH segment types, from RFC 4271 and RFC 5065
+const (
+	segmentASSet          = 1
+	segmentASSequence     = 2
+	segmentConfedSequence = 3
+	segmentConfedSet      = 4
+)
+
+// addSegment adds the ASNs of an AS_PATH segment to the path or set of r.
+// Confederation segments only trace the route through the local
+// confederation, so they are dropped and never make up the origin.
+func (r *ribRoute) addSegment(typ uint32, asns []uint32) {
+	switch typ {
+	case segmentASSet:
+		r.asSet = append(r.asSet, asns...)
+	case segmentConfedSequence, segmentConfedSet:
+	default:
+		r.asPath = append(r.asPath, asns...)
+	}
+}
+
+// newRIB creates a rib holding routes
+func newRIB(routes []ribRoute) *rib {
+	t := &rib{}
+	for _, r := range routes {
+		t.add(r)
+	}
+	return t
+}
+
+// ribFamily returns the index of the family of addr in paths and tries
+func ribFamily(addr netip.Addr) int {
+	if addr.Is4() {
+		return 0
+	}
+	return 1
+}
+
+// add counts a path, keeping it if it is the first for its prefix.
+func (t *rib) add(r ribRoute) {
+	r.prefix = r.prefix.Masked()
+	addr := r.prefix.Addr()
+	f := ribFamily(addr)
+	t.paths[f]++
+
+	if t.tries[f] == nil {
+		t.tries[f] = &ribNode{}
+	}
+	n := t.tries[f]
+	b := addr.AsSlice()
+	for i := range r.prefix.Bits() {
+		bit := b[i/8] >> (7 - i%8) & 1
+		if n.child[bit] == nil {
+			n.child[bit] = &ribNode{}
+		}
+		n = n.child[bit]
+	}
+	if n.route != 0 {
+		return
+	}
+	t.routes = append(t.routes, r)
+	n.route = int32(len(t.routes))
+}
+
+// lookup returns the primary route with the longest prefix covering ip.
+func (t *rib) lookup(ip net.IP) (ribRoute, bool) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return ribRoute{}, false
+	}
+	addr = addr.Unmap()
+
+	n := t.tries[ribFamily(addr)]
+	var best int32
+	b := addr.AsSlice()
+	for i := 0; n != nil; i++ {
+		if n.route != 0 {
+			best = n.route
+		}
+		if i == addr.BitLen() {
+			break
+		}
+		n = n.child[b[i/8]>>(7-i%8)&1]
+	}
+	if best == 0 {
+		return ribRoute{}, false
+	}
+	return t.routes[best-1], true
+}
+
+// totals counts paths as the rib, and networks as the fib.
+func (t *rib) totals() Totals {
+	tot := Totals{V4Rib: t.paths[0], V6Rib: t.paths[1]}
+	for _, r := range t.routes {
+		if r.prefix.Addr().Is4() {
+			tot.V4Fib++
+		} else {
+			tot.V6Fib++
+		}
+	}
+	return tot
+}
+
+// sourceASNs counts the origin ASNs of the primary routes.
+func (t *rib) sourceASNs() ASNs {
+	as4Set := make(map[uint32]struct{})
+	as6Set := make(map[uint32]struct{})
+	for _, r := range t.routes {
+		asn := r.origin()
+		if asn == 0 {
+			continue
+		}
+		if r.prefix.Addr().Is4() {
+			as4Set[asn] = struct{}{}
+		} else {
+			as6Set[asn] = struct{}{}
+		}
+	}
+	return countASNs(as4Set, as6Set)
+}
+
+// masks counts the primary routes by prefix length, IPv4 first.
+func (t *rib) masks() []map[string]uint32 {
+	v4 := make(map[string]uint32)
+	v6 := make(map[string]uint32)
+	for _, r := range t.routes {
+		if r.prefix.Addr().Is4() {
+			v4[strconv.Itoa(r.prefix.Bits())]++
+		} else {
+			v6[strconv.Itoa(r.prefix.Bits())]++
+		}
+	}
+	return []map[string]uint32{v4, v6}
+}
+
+// roaState returns the origin validation state of a route.
+func (t *rib) roaState(r ribRoute) int {
+	if r.hasROA {
+		return r.roa
+	}
+	return t.validator.Validate(r.prefix, r.origin())
+}
+
+// roas counts the primary routes by origin validation state.
+func (t *rib) roas() Roas {
+	var rs Roas
+	for _, r := range t.routes {
+		v4 := r.prefix.Addr().Is4()
+		switch t.roaState(r) {
+		case RValid:
+			if v4 {
+				rs.V4v++
+			} else {
+				rs.V6v++
+			}
+		case RInvalid:
+			if v4 {
+				rs.V4i++
+			} else {
+				rs.V6i++
+			}
+		default:
+			if v4 {
+				rs.V4u++
+			} else {
+				rs.V6u++
+			}
+		}
+	}
+	return rs
+}
+
+// large counts the primary routes carrying large communities.
+func (t *rib) large() Large {
+	var l Large
+	for _, r := range t.routes {
+		if !r.large {
+			continue
+		}
+		if r.prefix.Addr().Is4() {
+			l.V4++
+		} else {
+			l.V6++
+		}
+	}
+	return l
+}
+
+// fromSource returns the primary networks of one address family originated by asn.
+func (t *rib) fromSource(asn uint32, v4 bool) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, r := range t.routes {
+		if r.prefix.Addr().Is4() == v4 && r.origin() == asn {
+			nets = append(nets, IPNetFromPrefix(r.prefix))
+		}
+	}
+	return nets
+}
+
+// invalids returns the primary networks with an invalid origin, keyed by that origin.
+// Routes ending in an AS set have no origin to list them under, so they are left out, as
+// BIRD's GetInvalids does.
+func (t *rib) invalids() map[string][]string {
+	inv := make(map[string][]string)
+	for _, r := range t.routes {
+		asn := r.origin()
+		if asn == 0 || t.roaState(r) != RInvalid {
+			continue
+		}
+		key := strconv.FormatUint(uint64(asn), 10)
+		inv[key] = append(inv[key], r.prefix.String())
+	}
+	return inv
+}
+
+// vrpsFor returns the VRPs of v authorising asn.
+func vrpsFor(v *Validator, asn uint32) []VRP {
+	var vrps []VRP
+	for _, roa := range v.VRPs(asn) {
+		vrps = append(vrps, VRP{Prefix: IPNetFromPrefix(roa.Prefix), Max: roa.MaxLength})
+	}
+	return vrps
+}
@@ -0,0 +1,50 @@
+package clidecode
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestRIB(t *testing.T) {
+	route := func(prefix string, path ...uint32) ribRoute {
+		return ribRoute{prefix: netip.MustParsePrefix(prefix), asPath: path}
+	}
+	table := newRIB([]ribRoute{
+		route("8.0.0.0/9", 64496, 3356),
+		route("8.8.8.0/24", 64496, 15169),
+		route("8.8.8.0/24", 64497, 15169, 15169),
+		route("0.0.0.0/0", 64496),
+		route("2001:db8::/32", 64496, 64511),
+		route("2001:db8:1::/48", 64496, 64500),
+	})
+
+	if got, want := table.totals(), (Totals{V4Rib: 4, V4Fib: 3, V6Rib: 2, V6Fib: 2}); got != want {
+		t.Errorf("totals() = %+v, want %+v", got, want)
+	}
+
+	for _, tt := range []struct {
+		ip     string
+		prefix string
+		origin uint32
+	}{
+		{"8.8.8.8", "8.8.8.0/24", 15169},
+		{"8.8.4.4", "8.0.0.0/9", 3356},
+		{"::ffff:8.8.8.8", "8.8.8.0/24", 15169},
+		{"192.0.2.1", "0.0.0.0/0", 64496},
+		{"2001:db8:1::1", "2001:db8:1::/48", 64500},
+		{"2001:db8:2::1", "2001:db8::/32", 64511},
+		{"2001:db9::1", "", 0},
+	} {
+		r, found := table.lookup(net.ParseIP(tt.ip))
+		if tt.prefix == "" {
+			if found {
+				t.Errorf("lookup(%s) found %s", tt.ip, r.prefix)
+			}
+			continue
+		}
+		if !found || r.prefix.String() != tt.prefix || r.origin() != tt.origin {
+			t.Errorf("lookup(%s) = %s from AS%d, %v; want %s from AS%d", tt.ip, r.prefix, r.origin(), found, tt.prefix, tt.origin)
+		}
+	}
+}
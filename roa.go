@@ -0,0 +1,181 @@
+package clidecode
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ROAResult is the origin validation state of a prefix and origin ASN, along
+// with the VRPs that decided it.
+type ROAResult struct {
+	Prefix netip.Prefix `json:"prefix" yaml:"prefix"`
+	Origin uint32       `json:"origin" yaml:"origin"`
+	State  int          `json:"state" yaml:"state"`
+
+	Covering    []ROA `json:"covering" yaml:"covering"`         // every VRP covering the prefix
+	Matched     []ROA `json:"matched" yaml:"matched"`           // covering VRPs for the origin that allow the prefix length
+	TooLong     []ROA `json:"too_long" yaml:"too_long"`         // covering VRPs for the origin whose max length is too short
+	OtherOrigin []ROA `json:"other_origin" yaml:"other_origin"` // covering VRPs for other ASNs
+}
+
+// newROAResult sorts covering VRPs by why they did or did not match.
+// The state is worked out from them as described in RFC 6811.
+func newROAResult(prefix netip.Prefix, origin uint32, covering []ROA) ROAResult {
+	r := ROAResult{Prefix: prefix, Origin: origin, Covering: covering, State: RUnknown}
+	for _, v := range covering {
+		switch {
+		case v.ASN != origin || origin == 0:
+			r.OtherOrigin = append(r.OtherOrigin, v)
+		case prefix.Bits() > v.MaxLength:
+			r.TooLong = append(r.TooLong, v)
+		default:
+			r.Matched = append(r.Matched, v)
+		}
+	}
+	switch {
+	case len(r.Matched) > 0:
+		r.State = RValid
+	case len(covering) > 0:
+		r.State = RInvalid
+	}
+	return r
+}
+
+// String explains the result, as an operator would to a customer.
+func (r ROAResult) String() string {
+	switch r.State {
+	case RValid:
+		v := r.Matched[0]
+		return fmt.Sprintf("%s from AS%d is valid: authorised by VRP %s-%d AS%d", r.Prefix, r.Origin, v.Prefix, v.MaxLength, v.ASN)
+	case RInvalid:
+		var reasons []string
+		for _, v := range r.TooLong {
+			reasons = append(reasons, fmt.Sprintf("VRP %s-%d AS%d matches the origin but allows at most /%d", v.Prefix, v.MaxLength, v.ASN, v.MaxLength))
+		}
+		if len(r.OtherOrigin) > 0 {
+			asns := make([]string, 0, len(r.OtherOrigin))
+			for _, v := range r.OtherOrigin {
+				asns = append(asns, fmt.Sprintf("%s-%d AS%d", v.Prefix, v.MaxLength, v.ASN))
+			}
+			reasons = append(reasons, "covered by VRPs for other origins: "+strings.Join(asns, ", "))
+		}
+		if len(reasons) == 0 {
+			return fmt.Sprintf("%s from AS%d is invalid", r.Prefix, r.Origin)
+		}
+		return fmt.Sprintf("%s from AS%d is invalid: %s", r.Prefix, r.Origin, strings.Join(reasons, "; "))
+	}
+	return fmt.Sprintf("%s from AS%d is unknown: no VRP covers it", r.Prefix, r.Origin)
+}
+
+// Explain validates prefix announced by origin, returning the VRPs involved.
+func (v *Validator) Explain(prefix netip.Prefix, origin uint32) ROAResult {
+	return newROAResult(prefix.Masked(), origin, v.Covering(prefix))
+}
+
+// roaCheckRe matches the result of eval roa_check(...), which BIRD prints as
+// "(enum 35)1", and newer versions also as the enum name.
+var roaCheckRe = regexp.MustCompile(`^(?:\(enum [[:xdigit:]x]+\)\s*)?(\w+)$`)
+
+// parseROACheck parses the output of eval roa_check(...).
+func parseROACheck(out string) (int, error) {
+	// Only the last line carries the value
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	m := roaCheckRe.FindStringSubmatch(strings.TrimSpace(lines[len(lines)-1]))
+	if m == nil {
+		return 0, fmt.Errorf("unexpected roa_check result %q", out)
+	}
+	switch m[1] {
+	case "ROA_UNKNOWN":
+		return RUnknown, nil
+	case "ROA_VALID":
+		return RValid, nil
+	case "ROA_INVALID":
+		return RInvalid, nil
+	}
+	switch n, err := strconv.Atoi(m[1]); {
+	case err != nil:
+		return 0, fmt.Errorf("unexpected roa_check result %q", out)
+	case n == 0:
+		return RUnknown, nil
+	case n == 1:
+		return RValid, nil
+	case n == 2:
+		return RInvalid, nil
+	}
+	return 0, fmt.Errorf("unexpected roa_check result %q", out)
+}
+
+// parseROAs parses the networks of a ROA table, like "192.0.2.0/24-24 AS64496 [rpki1 ...]"
+func parseROAs(output string) ([]ROA, error) {
+	var roas []ROA
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "AS") {
+			continue
+		}
+		network, maxLen, ok := strings.Cut(fields[0], "-")
+		if !ok {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, err
+		}
+		maxLength, err := strconv.Atoi(maxLen)
+		if err != nil {
+			return nil, err
+		}
+		asn, err := strconv.ParseUint(fields[1][2:], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		roas = append(roas, ROA{Prefix: prefix, MaxLength: maxLength, ASN: uint32(asn)})
+	}
+	return roas, nil
+}
+
+// GetROAResult returns the ROA status of a prefix announced by an ASN, along with
+// the VRPs covering the prefix and why each did or did not match.
+// The state is the one BIRD reports, unless b.Validator is set.
+func (b *BirdClient) GetROAResult(prefix *net.IPNet, asn uint32) (ROAResult, bool, error) {
+	return b.GetROAResultContext(context.Background(), prefix, asn)
+}
+
+// GetROAResultContext is like GetROAResult but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetROAResultContext(ctx context.Context, prefix *net.IPNet, asn uint32) (ROAResult, bool, error) {
+	p, ok := PrefixFromIPNet(prefix)
+	if !ok {
+		return ROAResult{}, false, nil
+	}
+	if b.Validator != nil {
+		if err := ctx.Err(); err != nil {
+			return ROAResult{}, false, err
+		}
+		return b.Validator.Explain(p, asn), true, nil
+	}
+
+	state, found, err := b.GetROAContext(ctx, prefix, asn)
+	if err != nil || !found {
+		return ROAResult{}, found, err
+	}
+
+	// A prefix set with a trailing - matches the prefix and everything less specific
+	cmd := fmt.Sprintf("show route table %s where net ~ [ %s- ]", b.tables().roaFor(prefix.IP), p.Masked())
+	out, err := b.query(ctx, cmd)
+	if err != nil && !isNotFound(err) {
+		return ROAResult{}, false, err
+	}
+	covering, err := parseROAs(out)
+	if err != nil {
+		return ROAResult{}, false, err
+	}
+
+	r := newROAResult(p.Masked(), asn, covering)
+	r.State = state
+	return r, true, nil
+}
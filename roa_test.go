@@ -0,0 +1,100 @@
+package clidecode
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseROACheck(t *testing.T) {
+	tests := []struct {
+		out  string
+		want int
+		ok   bool
+	}{
+		{"(enum 35)0", RUnknown, true},
+		{"(enum 35)1", RValid, true},
+		{"(enum 35)2", RInvalid, true},
+		{"(enum 35) 2\n", RInvalid, true},
+		{"(enum 0x23)1", RValid, true},
+		{"ROA_VALID", RValid, true},
+		{"(enum 35)ROA_INVALID", RInvalid, true},
+		{"ROA_UNKNOWN", RUnknown, true},
+		{"(enum 35)12", 0, false}, // used to be read as 2
+		{"(enum 35)", 0, false},
+		{"", 0, false},
+		{"syntax error, unexpected CF_SYM_UNDEFINED", 0, false},
+	}
+	for _, test := range tests {
+		got, err := parseROACheck(test.out)
+		if (err == nil) != test.ok || got != test.want {
+			t.Errorf("parseROACheck(%q) = %d, %v", test.out, got, err)
+		}
+	}
+}
+
+func TestGetROAResult(t *testing.T) {
+	client := &BirdClient{
+		Querier: mockQuerier(map[string]string{
+			"eval roa_check(roa_v4, 192.0.2.0/25, 64500)": "(enum 35)2",
+			"show route table roa_v4 where net ~ [ 192.0.2.0/25- ]": `Table roa_v4:
+192.0.2.0/23-24 AS64500  [rpki1 2025-11-19] * (100)
+192.0.2.0/24-24 AS64496  [rpki1 2025-11-19] * (100)`,
+		}),
+	}
+
+	_, prefix, _ := net.ParseCIDR("192.0.2.0/25")
+	r, found, err := client.GetROAResult(prefix, 64500)
+	if err != nil || !found {
+		t.Fatalf("GetROAResult returned %v, %v", found, err)
+	}
+	want := ROAResult{
+		Prefix: netip.MustParsePrefix("192.0.2.0/25"),
+		Origin: 64500,
+		State:  RInvalid,
+		Covering: []ROA{
+			{Prefix: netip.MustParsePrefix("192.0.2.0/23"), MaxLength: 24, ASN: 64500},
+			{Prefix: netip.MustParsePrefix("192.0.2.0/24"), MaxLength: 24, ASN: 64496},
+		},
+		TooLong:     []ROA{{Prefix: netip.MustParsePrefix("192.0.2.0/23"), MaxLength: 24, ASN: 64500}},
+		OtherOrigin: []ROA{{Prefix: netip.MustParsePrefix("192.0.2.0/24"), MaxLength: 24, ASN: 64496}},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("Expected %+v, got %+v", want, r)
+	}
+	if s := r.String(); !strings.Contains(s, "allows at most /24") || !strings.Contains(s, "AS64496") {
+		t.Errorf("Unexpected explanation %q", s)
+	}
+}
+
+func TestValidatorExplain(t *testing.T) {
+	v, err := LoadValidator("testdata/vrps/rpki-client.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := v.Explain(netip.MustParsePrefix("8.8.8.0/24"), 15169)
+	if r.State != RValid || len(r.Matched) != 1 || r.Matched[0].ASN != 15169 {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if s := r.String(); s != "8.8.8.0/24 from AS15169 is valid: authorised by VRP 8.8.8.0/24-24 AS15169" {
+		t.Errorf("Unexpected explanation %q", s)
+	}
+
+	r = v.Explain(netip.MustParsePrefix("203.0.113.0/24"), 64496)
+	if r.State != RUnknown || len(r.Covering) != 0 {
+		t.Errorf("Unexpected result %+v", r)
+	}
+
+	// Every explanation agrees with Validate
+	for _, p := range []string{"8.8.8.0/25", "192.0.3.0/24", "198.51.100.0/24", "2001:4860:1::/48"} {
+		prefix := netip.MustParsePrefix(p)
+		for _, asn := range []uint32{0, 15169, 64500} {
+			if got, want := v.Explain(prefix, asn).State, v.Validate(prefix, asn); got != want {
+				t.Errorf("Explain(%s, %d) is %d, Validate is %d", p, asn, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package clidecode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Route is a single route as listed by "show route".
+// Gateway onwards are only filled in from "show route all" output.
+type Route struct {
+	Prefix     *net.IPNet
+	Table      string
+	Type       string // unicast, unreachable, blackhole, ...
+	Protocol   string
+	Since      string // when the route was learned, which gives its age
+	From       string // neighbor the route was learned from, if any
+	Primary    bool
+	Preference int
+	SourceASN  uint32
+	Origin     string // BGP origin code: i, e or ?
+
+	Gateway   net.IP
+	Interface string
+	BGP       *BGPAttributes
+
+	// Attributes holds any other attribute BIRD listed, such as Type or OSPF.metric1
+	Attributes map[string]string
+}
+
+// BGPAttributes holds the BGP.* attributes of a route.
+type BGPAttributes struct {
+	Origin           string // IGP, EGP or Incomplete
+	ASPath           ASPath
+	NextHop          []net.IP // global, then link-local if present
+	MED              *uint32
+	LocalPref        *uint32
+	Communities      []Community
+	ExtCommunities   []ExtCommunity
+	LargeCommunities []LargeCommunity
+	OriginatorID     net.IP
+	ClusterList      []net.IP
+	OTC              uint32 // Only To Customer (RFC9234)
+}
+
+// Community is a standard BGP community (RFC1997).
+type Community struct {
+	ASN, Value uint16
+}
+
+// ExtCommunity is an extended BGP community (RFC4360) as printed by BIRD,
+// e.g. (rt, 64496, 1) or (generic, 0x43000000, 0x1).
+type ExtCommunity struct {
+	Type, Global, Local string
+}
+
+// LargeCommunity is a large BGP community (RFC8092).
+type LargeCommunity struct {
+	Global, Local1, Local2 uint32
+}
+
+// RouteFilter selects the routes returned by GetRoutes. Empty fields match everything.
+type RouteFilter struct {
+	For      string // network or address to look up, longest match for an address
+	Table    string
+	Protocol string
+	Primary  bool
+	Where    string // BIRD filter expression
+}
+
+// command builds the "show route ... all" command for the filter.
+func (f RouteFilter) command() string {
+	cmd := "show route"
+	if f.For != "" {
+		cmd += " for " + f.For
+	}
+	if f.Table != "" {
+		cmd += " table " + f.Table
+	}
+	if f.Protocol != "" {
+		cmd += " protocol " + f.Protocol
+	}
+	if f.Primary {
+		cmd += " primary"
+	}
+	cmd += " all"
+	if f.Where != "" {
+		cmd += " where " + f.Where
+	}
+	return cmd
+}
+
+// errStopIteration unwinds the socket reader when the consumer stops ranging early.
+var errStopIteration = errors.New("iteration stopped")
+
+// Routes streams the primary routes of a table, parsing each one as it arrives off
+// the socket so memory use doesn't grow with the size of the table.
+// An optional filter is a BIRD filter expression, as used after "where".
+func (b *BirdClient) Routes(ctx context.Context, table, filter string) iter.Seq2[Route, error] {
+	cmd := fmt.Sprintf("show route primary table %s", table)
+	if filter != "" {
+		cmd = fmt.Sprintf("%s where %s", cmd, filter)
+	}
+	return b.routes(ctx, cmd)
+}
+
+// GetRoutes returns every route matching the filter, with all attributes
+func (b *BirdClient) GetRoutes(filter RouteFilter) ([]Route, error) {
+	return b.GetRoutesContext(context.Background(), filter)
+}
+
+// GetRoutesContext is like GetRoutes but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetRoutesContext(ctx context.Context, filter RouteFilter) ([]Route, error) {
+	var routes []Route
+	for r, err := range b.routes(ctx, filter.command()) {
+		if err != nil {
+			if isNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+// GetRouteDetail returns the primary route, with all attributes, used to reach an IP
+func (b *BirdClient) GetRouteDetail(ip net.IP) (Route, bool, error) {
+	return b.GetRouteDetailContext(context.Background(), ip)
+}
+
+// GetRouteDetailContext is like GetRouteDetail but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetRouteDetailContext(ctx context.Context, ip net.IP) (Route, bool, error) {
+	routes, err := b.GetRoutesContext(ctx, RouteFilter{For: ip.String(), Table: b.tables().ribFor(ip), Primary: true})
+	if err != nil || len(routes) == 0 {
+		return Route{}, false, err
+	}
+	return routes[0], true, nil
+}
+
+// routes runs a "show route" command and yields each route parsed from the output.
+func (b *BirdClient) routes(ctx context.Context, command string) iter.Seq2[Route, error] {
+	return func(yield func(Route, error) bool) {
+		var p routeParser
+		err := b.replies(ctx, command, func(rl ReplyLine) error {
+			if r, ok := p.feed(rl); ok && !yield(r, nil) {
+				return errStopIteration
+			}
+			return nil
+		})
+		if errors.Is(err, errStopIteration) {
+			return
+		}
+		if err != nil {
+			yield(Route{}, err)
+			return
+		}
+		if r, ok := p.flush(); ok {
+			yield(r, nil)
+		}
+	}
+}
+
+// routeParser turns "show route" output into routes, one line at a time.
+// A route is only complete once the next one starts, as detail lines follow it.
+type routeParser struct {
+	table   string
+	current Route
+	pending bool
+	attr    string // key of the last attribute of the current route, for wrapped lines
+}
+
+// feed parses a single line, returning the previous route if this line starts a new one.
+// Routes and their next hops are listed under code 1007, with BIRD replacing the
+// code by a space on every line after the first. Lines of unknown code are judged
+// by their layout alone.
+func (p *routeParser) feed(rl ReplyLine) (Route, bool) {
+	line := rl.Text
+	if strings.HasPrefix(line, "Table ") && strings.HasSuffix(line, ":") {
+		p.table = strings.TrimSuffix(strings.TrimPrefix(line, "Table "), ":")
+		return p.flush()
+	}
+
+	var prev *net.IPNet
+	if p.pending {
+		prev = p.current.Prefix
+	}
+	var r Route
+	ok := false
+	if rl.Code == CodeUnknown || rl.Code == CodeRoute {
+		r, ok = parseRouteLine(line, prev)
+	}
+	if !ok {
+		// Next hops (1007) and attributes (1008, 1012) of the current route
+		if p.pending {
+			p.attr = p.current.addDetail(line, p.attr)
+		}
+		return Route{}, false
+	}
+	r.Table = p.table
+
+	done, had := p.flush()
+	p.current = r
+	p.pending = true
+	p.attr = ""
+	return done, had
+}
+
+// flush returns the route currently being built, if any.
+func (p *routeParser) flush() (Route, bool) {
+	if !p.pending {
+		return Route{}, false
+	}
+	p.pending = false
+	return p.current, true
+}
+
+// parseRouteLine parses a route summary line such as:
+// 1.0.0.0/24           unicast [bgp1 2024-01-01 from 192.0.2.1] * (100) [AS13335i]
+// Further routes for the same network leave the prefix column blank, in which
+// case prev is used.
+func parseRouteLine(line string, prev *net.IPNet) (Route, bool) {
+	var r Route
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return r, false
+	}
+
+	rest := strings.TrimSpace(line)
+	switch {
+	case line[0] != ' ' && line[0] != '\t':
+		_, ipNet, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			return r, false
+		}
+		r.Prefix = ipNet
+		rest = strings.TrimSpace(rest[len(fields[0]):])
+	case line[0] == ' ' && prev != nil && strings.Contains(line, "["):
+		r.Prefix = prev
+	default:
+		return r, false
+	}
+
+	open := strings.IndexByte(rest, '[')
+	head := rest
+	if open != -1 {
+		head = rest[:open]
+	}
+	if f := strings.Fields(head); len(f) > 0 && f[0] != "via" {
+		r.Type = f[0]
+	}
+	if open == -1 {
+		return r, true
+	}
+
+	end := strings.IndexByte(rest[open:], ']')
+	if end == -1 {
+		return r, true
+	}
+	end += open
+
+	// Protocol name, then the time it was learned and possibly "from <neighbor>"
+	proto := strings.Fields(rest[open+1 : end])
+	if len(proto) > 0 {
+		r.Protocol = proto[0]
+		since := proto[1:]
+		for i, f := range since {
+			if f == "from" {
+				if i+1 < len(since) {
+					r.From = since[i+1]
+				}
+				since = since[:i]
+				break
+			}
+		}
+		r.Since = strings.Join(since, " ")
+	}
+
+	for _, f := range strings.Fields(rest[end+1:]) {
+		switch {
+		case f == "*":
+			r.Primary = true
+		case strings.HasPrefix(f, "("):
+			// (100) or (100/20) when a metric is shown
+			pref, _, _ := strings.Cut(strings.Trim(f, "()"), "/")
+			r.Preference, _ = strconv.Atoi(pref)
+		case strings.HasPrefix(f, "["):
+			r.SourceASN, r.Origin = parseRouteSource(strings.Trim(f, "[]"))
+		}
+	}
+
+	return r, true
+}
+
+// parseRouteSource decodes the route source such as AS13335i, or just i for a
+// path without ASNs.
+func parseRouteSource(src string) (uint32, string) {
+	var origin string
+	if n := len(src); n > 0 && (src[n-1] < '0' || src[n-1] > '9') {
+		origin = src[n-1:]
+		src = src[:n-1]
+	}
+	return stringToUint32(strings.TrimPrefix(src, "AS")), origin
+}
+
+// addDetail parses a line listed below the route, such as a next hop or an attribute.
+// BIRD 2 and BIRD 3 share the layout, BIRD 3 adding a few internal attributes
+// which end up in Attributes.
+// BIRD wraps long community lists onto further lines with no key, which carry
+// on the attribute last, the key of the line before. The key of this line is returned.
+func (r *Route) addDetail(line, last string) string {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return ""
+	}
+
+	// Next hops: "via 192.0.2.1 on eth0", possibly followed by "weight 1" or
+	// MPLS labels, or "dev eth0" for directly connected networks
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "via":
+		if len(fields) > 1 && r.Gateway == nil {
+			r.Gateway = net.ParseIP(fields[1])
+		}
+		for i := 2; i+1 < len(fields); i++ {
+			if fields[i] == "on" && r.Interface == "" {
+				r.Interface = fields[i+1]
+			}
+		}
+		return ""
+	case "dev":
+		if len(fields) > 1 && r.Interface == "" {
+			r.Interface = fields[1]
+		}
+		return ""
+	}
+
+	key, value, ok := strings.Cut(line, ":")
+	wrapped := !ok || strings.HasPrefix(line, "(")
+	if wrapped {
+		if last == "" {
+			return ""
+		}
+		key, value = last, line
+	}
+	value = strings.TrimSpace(value)
+
+	if name, ok := strings.CutPrefix(key, "BGP."); ok {
+		if r.BGP == nil {
+			r.BGP = &BGPAttributes{}
+		}
+		if r.BGP.set(name, value) {
+			return key
+		}
+	}
+
+	if r.Attributes == nil {
+		r.Attributes = make(map[string]string)
+	}
+	if wrapped {
+		r.Attributes[key] += " " + value
+	} else {
+		r.Attributes[key] = value
+	}
+	return key
+}
+
+// set decodes a single BGP attribute, reporting false if it isn't one it knows.
+// Lists such as communities are added to, as BIRD may split them over several lines.
+func (a *BGPAttributes) set(name, value string) bool {
+	switch name {
+	case "origin":
+		a.Origin = value
+	case "as_path":
+		a.ASPath.Path, a.ASPath.Set = decodeASPaths(value)
+	case "next_hop":
+		a.NextHop = parseIPs(value)
+	case "med":
+		med := stringToUint32(value)
+		a.MED = &med
+	case "local_pref":
+		pref := stringToUint32(value)
+		a.LocalPref = &pref
+	case "community":
+		for _, c := range parseTuples(value) {
+			if len(c) == 2 {
+				a.Communities = append(a.Communities, Community{
+					ASN:   uint16(stringToUint32(c[0])),
+					Value: uint16(stringToUint32(c[1])),
+				})
+			}
+		}
+	case "ext_community":
+		for _, c := range parseTuples(value) {
+			if len(c) == 3 {
+				a.ExtCommunities = append(a.ExtCommunities, ExtCommunity{Type: c[0], Global: c[1], Local: c[2]})
+			}
+		}
+	case "large_community":
+		for _, c := range parseTuples(value) {
+			if len(c) == 3 {
+				a.LargeCommunities = append(a.LargeCommunities, LargeCommunity{
+					Global: stringToUint32(c[0]),
+					Local1: stringToUint32(c[1]),
+					Local2: stringToUint32(c[2]),
+				})
+			}
+		}
+	case "originator_id":
+		a.OriginatorID = net.ParseIP(value)
+	case "cluster_list":
+		a.ClusterList = append(a.ClusterList, parseIPs(value)...)
+	case "otc":
+		a.OTC = stringToUint32(value)
+	default:
+		return false
+	}
+	return true
+}
+
+// parseTuples splits BIRD's community notation, e.g. "(64496,1) (64496, 2)",
+// into the fields of each tuple.
+func parseTuples(value string) [][]string {
+	var tuples [][]string
+	for {
+		open := strings.IndexByte(value, '(')
+		if open == -1 {
+			return tuples
+		}
+		end := strings.IndexByte(value[open:], ')')
+		if end == -1 {
+			return tuples
+		}
+		parts := strings.Split(value[open+1:open+end], ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		tuples = append(tuples, parts)
+		value = value[open+end+1:]
+	}
+}
+
+// parseIPs parses a space separated list of addresses, skipping anything else.
+func parseIPs(value string) []net.IP {
+	var ips []net.IP
+	for _, f := range strings.Fields(value) {
+		if ip := net.ParseIP(f); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
@@ -0,0 +1,611 @@
+package clidecode
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RTR PDU types, from RFC 8210 and, for ASPA, draft-ietf-sidrops-8210bis
+const (
+	rtrSerialNotify  = 0
+	rtrSerialQuery   = 1
+	rtrResetQuery    = 2
+	rtrCacheResponse = 3
+	rtrIPv4Prefix    = 4
+	rtrIPv6Prefix    = 6
+	rtrEndOfData     = 7
+	rtrCacheReset    = 8
+	rtrRouterKey     = 9
+	rtrErrorReport   = 10
+	rtrASPA          = 11
+
+	// rtrMaxPDU bounds the length of a PDU, which is only large for error reports
+	rtrMaxPDU = 1 << 16
+)
+
+// RTR error codes, from RFC 8210 section 12
+const (
+	RTRCorruptData               = 0
+	RTRInternalError             = 1
+	RTRNoDataAvailable           = 2
+	RTRInvalidRequest            = 3
+	RTRUnsupportedVersion        = 4
+	RTRUnsupportedPDUType        = 5
+	RTRWithdrawalOfUnknownRecord = 6
+	RTRDuplicateAnnouncement     = 7
+	RTRUnexpectedVersion         = 8
+)
+
+// Default timers, used until a version 1 cache sends its own in End of Data
+const (
+	RTRDefaultRefresh = 3600 * time.Second
+	RTRDefaultRetry   = 600 * time.Second
+	RTRDefaultExpire  = 7200 * time.Second
+)
+
+// RTRError is an Error Report sent by the cache.
+type RTRError struct {
+	Code int
+	Text string
+}
+
+func (e *RTRError) Error() string {
+	if e.Text == "" {
+		return fmt.Sprintf("rtr: cache reported error %d", e.Code)
+	}
+	return fmt.Sprintf("rtr: cache reported error %d: %s", e.Code, e.Text)
+}
+
+// RouterKey is a BGPsec router key.
+type RouterKey struct {
+	SKI  [20]byte
+	ASN  uint32
+	SPKI []byte
+}
+
+// ASPA is an Autonomous System Provider Authorization: the set of ASNs a
+// customer ASN has authorised as its upstream providers.
+type ASPA struct {
+	Customer  uint32
+	Providers []uint32
+}
+
+// RTRClient is an RPKI-to-Router client, as described in RFC 8210, keeping a
+// live copy of the VRPs, router keys and ASPAs held by a validator cache.
+//
+// Sync fetches the cache state once, incrementally after the first time. Run
+// keeps it up to date, following Serial Notify and the cache's timers.
+// Version is the highest protocol version offered, 1 by default. If the cache
+// reports it as unsupported, the client falls back to the cache's version.
+// ASPA needs version 2. Sync must not be called while Run is running.
+type RTRClient struct {
+	Address string
+	Version uint8
+	Timeout time.Duration
+	Dial    func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// OnSync, if set, is called after each successful sync
+	OnSync func()
+
+	// OnError, if set, is called with the error of each failed sync made by Run
+	OnError func(error)
+
+	mu          sync.Mutex
+	conn        net.Conn
+	reader      *bufio.Reader
+	version     uint8 // version in use, which may have fallen back from Version
+	started     bool  // set once version has been chosen
+	versioned   bool  // set once the cache has answered in our version
+	lastVersion uint8 // version of the last PDU read
+	state       rtrState
+	validator   *Validator
+}
+
+// rtrState is the data received from a cache, as of a serial number.
+type rtrState struct {
+	valid   bool
+	session uint16
+	serial  uint32
+	refresh time.Duration
+	retry   time.Duration
+	expire  time.Duration
+	synced  time.Time
+
+	roas  map[ROA]struct{}
+	keys  map[string]RouterKey
+	aspas map[uint32][]uint32
+}
+
+// rtrPDU is a PDU as read off the wire. The session field also carries the
+// error code of an Error Report, and the flags of Router Key and ASPA PDUs.
+type rtrPDU struct {
+	version uint8
+	typ     uint8
+	session uint16
+	body    []byte
+}
+
+// NewRTRClient creates an RTRClient for a cache listening on address, as host:port.
+// The connection is opened lazily on the first sync.
+func NewRTRClient(address string) *RTRClient {
+	return &RTRClient{Address: address}
+}
+
+// Sync brings the local copy up to date with the cache.
+// The first sync, or one after the cache lost track of us, fetches everything.
+func (c *RTRClient) Sync(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	for {
+		err := c.syncLocked(ctx)
+		var rerr *RTRError
+		if errors.As(err, &rerr) && rerr.Code == RTRUnsupportedVersion && !c.versioned {
+			// Fall back to the version the cache answered with, if lower
+			if v := c.lastVersion; v < c.version {
+				c.version = v
+				c.closeLocked()
+				continue
+			}
+		}
+		if err != nil {
+			c.closeLocked()
+			return err
+		}
+		return nil
+	}
+}
+
+// syncLocked sends a Serial or Reset Query and applies the response.
+func (c *RTRClient) syncLocked(ctx context.Context) error {
+	if c.conn == nil {
+		if err := c.dialLocked(ctx); err != nil {
+			return err
+		}
+	}
+	defer watchContext(ctx, c.conn)()
+
+	reset := !c.state.valid
+	if reset {
+		if err := c.write(rtrResetQuery, 0, nil); err != nil {
+			return contextError(ctx, err)
+		}
+	} else {
+		if err := c.write(rtrSerialQuery, c.state.session, binary.BigEndian.AppendUint32(nil, c.state.serial)); err != nil {
+			return contextError(ctx, err)
+		}
+	}
+
+	var changes []rtrPDU
+	responded := false
+	for {
+		pdu, err := c.read()
+		if err != nil {
+			return contextError(ctx, err)
+		}
+		if pdu.typ == rtrErrorReport {
+			return rtrErrorFrom(pdu)
+		}
+		if pdu.version != c.version {
+			return fmt.Errorf("rtr: unexpected version %d PDU, speaking version %d", pdu.version, c.version)
+		}
+
+		switch pdu.typ {
+		case rtrSerialNotify:
+			// Covered by the query in flight
+		case rtrCacheResponse:
+			if !reset && pdu.session != c.state.session {
+				return fmt.Errorf("rtr: cache session changed from %d to %d", c.state.session, pdu.session)
+			}
+			responded = true
+			c.versioned = true
+		case rtrIPv4Prefix, rtrIPv6Prefix, rtrRouterKey, rtrASPA:
+			if !responded {
+				return fmt.Errorf("rtr: type %d PDU before Cache Response", pdu.typ)
+			}
+			changes = append(changes, pdu)
+		case rtrEndOfData:
+			if !responded {
+				return errors.New("rtr: End of Data before Cache Response")
+			}
+			return c.apply(reset, pdu, changes)
+		case rtrCacheReset:
+			// The cache cannot answer incrementally, start over
+			c.state = rtrState{}
+			c.validator = nil
+			reset = true
+			changes = nil
+			if err := c.write(rtrResetQuery, 0, nil); err != nil {
+				return contextError(ctx, err)
+			}
+		default:
+			return fmt.Errorf("rtr: unexpected PDU type %d", pdu.typ)
+		}
+	}
+}
+
+// apply commits the changes of a response once its End of Data arrives.
+func (c *RTRClient) apply(reset bool, eod rtrPDU, changes []rtrPDU) error {
+	next := c.state
+	if reset {
+		next = rtrState{}
+	}
+	next.roas = make(map[ROA]struct{}, len(c.state.roas))
+	next.keys = make(map[string]RouterKey, len(c.state.keys))
+	next.aspas = make(map[uint32][]uint32, len(c.state.aspas))
+	if !reset {
+		for k := range c.state.roas {
+			next.roas[k] = struct{}{}
+		}
+		for k, v := range c.state.keys {
+			next.keys[k] = v
+		}
+		for k, v := range c.state.aspas {
+			next.aspas[k] = v
+		}
+	}
+
+	for _, pdu := range changes {
+		if err := next.change(pdu); err != nil {
+			// Our copy can no longer be trusted, so start over next time
+			c.state = rtrState{}
+			c.validator = nil
+			return err
+		}
+	}
+
+	b := &wireBuffer{b: eod.body}
+	next.valid = true
+	next.session = eod.session
+	next.serial = b.uint32()
+	next.refresh, next.retry, next.expire = RTRDefaultRefresh, RTRDefaultRetry, RTRDefaultExpire
+	if eod.version >= 1 {
+		next.refresh = time.Duration(b.uint32()) * time.Second
+		next.retry = time.Duration(b.uint32()) * time.Second
+		next.expire = time.Duration(b.uint32()) * time.Second
+	}
+	if b.bad {
+		return errors.New("rtr: short End of Data")
+	}
+	next.synced = time.Now()
+
+	c.state = next
+	c.validator = nil
+	return nil
+}
+
+// change applies one announcement or withdrawal.
+func (s *rtrState) change(pdu rtrPDU) error {
+	b := &wireBuffer{b: pdu.body}
+	switch pdu.typ {
+	case rtrIPv4Prefix, rtrIPv6Prefix:
+		flags := b.uint8()
+		bits := int(b.uint8())
+		maxLength := int(b.uint8())
+		b.uint8()
+		size := 4
+		if pdu.typ == rtrIPv6Prefix {
+			size = 16
+		}
+		addr, _ := netip.AddrFromSlice(b.next(size))
+		asn := b.uint32()
+		if b.bad || bits > size*8 || maxLength < bits || maxLength > size*8 {
+			return errors.New("rtr: corrupt prefix PDU")
+		}
+		roa := ROA{Prefix: netip.PrefixFrom(addr, bits).Masked(), MaxLength: maxLength, ASN: asn}
+		if flags&1 != 0 {
+			s.roas[roa] = struct{}{}
+			return nil
+		}
+		if _, ok := s.roas[roa]; !ok {
+			return fmt.Errorf("rtr: withdrawal of unknown VRP %s-%d AS%d", roa.Prefix, roa.MaxLength, roa.ASN)
+		}
+		delete(s.roas, roa)
+
+	case rtrRouterKey:
+		var key RouterKey
+		copy(key.SKI[:], b.next(20))
+		key.ASN = b.uint32()
+		key.SPKI = append([]byte(nil), b.b...)
+		if b.bad {
+			return errors.New("rtr: corrupt Router Key PDU")
+		}
+		id := string(key.SKI[:]) + string(binary.BigEndian.AppendUint32(nil, key.ASN)) + string(key.SPKI)
+		if pdu.session>>8&1 != 0 {
+			s.keys[id] = key
+			return nil
+		}
+		if _, ok := s.keys[id]; !ok {
+			return fmt.Errorf("rtr: withdrawal of unknown router key for AS%d", key.ASN)
+		}
+		delete(s.keys, id)
+
+	case rtrASPA:
+		customer := b.uint32()
+		var providers []uint32
+		for len(b.b) >= 4 {
+			providers = append(providers, b.uint32())
+		}
+		if b.bad || len(b.b) != 0 {
+			return errors.New("rtr: corrupt ASPA PDU")
+		}
+		if pdu.session>>8&1 != 0 {
+			s.aspas[customer] = providers
+			return nil
+		}
+		if _, ok := s.aspas[customer]; !ok {
+			return fmt.Errorf("rtr: withdrawal of unknown ASPA for AS%d", customer)
+		}
+		delete(s.aspas, customer)
+	}
+	return nil
+}
+
+// rtrErrorFrom decodes an Error Report.
+func rtrErrorFrom(pdu rtrPDU) error {
+	b := &wireBuffer{b: pdu.body}
+	b.next(int(b.uint32())) // the PDU in error
+	text := b.next(int(b.uint32()))
+	if b.bad {
+		text = nil
+	}
+	return &RTRError{Code: int(pdu.session), Text: string(text)}
+}
+
+func (c *RTRClient) dialLocked(ctx context.Context) error {
+	dial := c.Dial
+	if dial == nil {
+		var d net.Dialer
+		dial = d.DialContext
+	}
+	conn, err := dial(ctx, "tcp", c.Address)
+	if err != nil {
+		return err
+	}
+	if !c.started {
+		c.version = c.Version
+		if c.version == 0 {
+			c.version = 1
+		}
+		c.started = true
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+	return nil
+}
+
+func (c *RTRClient) closeLocked() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn, c.reader = nil, nil
+	}
+}
+
+// write sends a PDU in the current version.
+func (c *RTRClient) write(typ uint8, session uint16, body []byte) error {
+	pdu := []byte{c.version, typ}
+	pdu = binary.BigEndian.AppendUint16(pdu, session)
+	pdu = binary.BigEndian.AppendUint32(pdu, uint32(8+len(body)))
+	_, err := c.conn.Write(append(pdu, body...))
+	return err
+}
+
+// read reads the next PDU, remembering its version for fallback.
+func (c *RTRClient) read() (rtrPDU, error) {
+	pdu, err := readRTRPDU(c.reader)
+	if err == nil {
+		c.lastVersion = pdu.version
+	}
+	return pdu, err
+}
+
+func readRTRPDU(r io.Reader) (rtrPDU, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return rtrPDU{}, err
+	}
+	length := binary.BigEndian.Uint32(header[4:])
+	if length < 8 || length > rtrMaxPDU {
+		return rtrPDU{}, fmt.Errorf("rtr: bad PDU length %d", length)
+	}
+	pdu := rtrPDU{
+		version: header[0],
+		typ:     header[1],
+		session: binary.BigEndian.Uint16(header[2:]),
+		body:    make([]byte, length-8),
+	}
+	if _, err := io.ReadFull(r, pdu.body); err != nil {
+		return rtrPDU{}, err
+	}
+	return pdu, nil
+}
+
+// Run keeps the local copy in sync until ctx is cancelled. It syncs whenever
+// the cache sends a Serial Notify, or the refresh timer fires, and retries
+// failures after the retry interval, reporting them to OnError. Data older
+// than the expire interval is dropped, as RFC 8210 requires.
+func (c *RTRClient) Run(ctx context.Context) error {
+	for {
+		wait := RTRDefaultRetry
+		if err := c.Sync(ctx); err == nil {
+			if c.OnSync != nil {
+				c.OnSync()
+			}
+			wait = c.timer(func(s rtrState) time.Duration { return s.refresh })
+		} else {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if c.OnError != nil {
+				c.OnError(err)
+			}
+			wait = c.timer(func(s rtrState) time.Duration { return s.retry })
+			c.expire()
+		}
+		if err := c.waitNotify(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// timer returns one of the cache's timers, or the default before the first sync.
+func (c *RTRClient) timer(pick func(rtrState) time.Duration) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.state
+	if !s.valid {
+		s.refresh, s.retry = RTRDefaultRefresh, RTRDefaultRetry
+	}
+	if d := pick(s); d > 0 {
+		return d
+	}
+	return RTRDefaultRetry
+}
+
+// expire drops the data if the last successful sync is too old.
+func (c *RTRClient) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state.valid && time.Since(c.state.synced) > c.state.expire {
+		c.state = rtrState{}
+		c.validator = nil
+	}
+}
+
+// waitNotify waits for a Serial Notify for up to d, returning early with no
+// error once one arrives. It only returns an error when ctx is done.
+func (c *RTRClient) waitNotify(ctx context.Context, d time.Duration) error {
+	wctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	c.mu.Lock()
+	conn, reader := c.conn, c.reader
+	c.mu.Unlock()
+
+	if conn != nil {
+		// Only Run reads between syncs, so the connection is ours until we return
+		stop := watchContext(wctx, conn)
+		for {
+			pdu, err := readRTRPDU(reader)
+			if err != nil {
+				if !errors.Is(err, os.ErrDeadlineExceeded) {
+					// The cache went away, so reconnect after the retry interval
+					c.mu.Lock()
+					c.closeLocked()
+					c.mu.Unlock()
+					if retry := c.timer(func(s rtrState) time.Duration { return s.retry }); retry < d {
+						stop()
+						return c.waitNotify(ctx, retry)
+					}
+				}
+				break
+			}
+			if pdu.typ == rtrSerialNotify {
+				stop()
+				return nil
+			}
+		}
+		stop()
+	}
+
+	<-wctx.Done()
+	return ctx.Err()
+}
+
+// Close closes the connection to the cache. The data received is kept.
+func (c *RTRClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeLocked()
+	return nil
+}
+
+// Serial returns the session ID and serial number of the data held, if any.
+func (c *RTRClient) Serial() (session uint16, serial uint32, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.session, c.state.serial, c.state.valid
+}
+
+// ROAs returns the VRPs held, sorted by prefix.
+func (c *RTRClient) ROAs() []ROA {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.roasLocked()
+}
+
+func (c *RTRClient) roasLocked() []ROA {
+	roas := make([]ROA, 0, len(c.state.roas))
+	for r := range c.state.roas {
+		roas = append(roas, r)
+	}
+	slices.SortFunc(roas, func(a, b ROA) int {
+		if n := a.Prefix.Addr().Compare(b.Prefix.Addr()); n != 0 {
+			return n
+		}
+		if a.Prefix.Bits() != b.Prefix.Bits() {
+			return a.Prefix.Bits() - b.Prefix.Bits()
+		}
+		if a.MaxLength != b.MaxLength {
+			return a.MaxLength - b.MaxLength
+		}
+		return int(int64(a.ASN) - int64(b.ASN))
+	})
+	return roas
+}
+
+// RouterKeys returns the BGPsec router keys held.
+func (c *RTRClient) RouterKeys() []RouterKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]RouterKey, 0, len(c.state.keys))
+	for _, k := range c.state.keys {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b RouterKey) int { return int(int64(a.ASN) - int64(b.ASN)) })
+	return keys
+}
+
+// ASPAs returns the ASPAs held, sorted by customer ASN.
+func (c *RTRClient) ASPAs() []ASPA {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	aspas := make([]ASPA, 0, len(c.state.aspas))
+	for customer, providers := range c.state.aspas {
+		aspas = append(aspas, ASPA{Customer: customer, Providers: slices.Clone(providers)})
+	}
+	slices.SortFunc(aspas, func(a, b ASPA) int { return int(int64(a.Customer) - int64(b.Customer)) })
+	return aspas
+}
+
+// Validator returns a Validator over the VRPs held, such as for BirdClient.Validator.
+// It is a snapshot, so later syncs do not change it.
+func (c *RTRClient) Validator() *Validator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.validator == nil {
+		c.validator = NewValidator(c.roasLocked())
+	}
+	return c.validator
+}
+
+// GetVRPs will return all Validated ROA Payloads for an ASN.
+func (c *RTRClient) GetVRPs(asn uint32) ([]VRP, error) {
+	return vrpsFor(c.Validator(), asn), nil
+}
@@ -0,0 +1,426 @@
+package clidecode
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/netip"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCache is an in-process RTR cache, reached through RTRClient.Dial over
+// in-memory pipes. It keeps every serial's changes so it can answer Serial
+// Queries incrementally, and answers Cache Reset for serials it has forgotten.
+type testCache struct {
+	version uint8 // highest version spoken
+	session uint16
+	noData  bool
+
+	mu      sync.Mutex
+	serial  uint32
+	roas    map[ROA]bool
+	aspas   map[uint32][]uint32
+	keys    []RouterKey
+	history map[uint32][]testChange // changes made to reach each serial
+	queries []uint8                 // query types received
+	conns   []chan []byte           // PDUs queued for each client
+	closed  bool
+}
+
+type testChange struct {
+	roa      ROA
+	announce bool
+}
+
+func newTestCache(t *testing.T, version uint8, roas ...ROA) *testCache {
+	c := &testCache{
+		version: version,
+		session: 4242,
+		serial:  1,
+		roas:    make(map[ROA]bool),
+		aspas:   make(map[uint32][]uint32),
+		history: make(map[uint32][]testChange),
+	}
+	for _, r := range roas {
+		c.roas[r] = true
+	}
+	t.Cleanup(c.close)
+	return c
+}
+
+// client returns an RTRClient connected to the cache.
+func (c *testCache) client() *RTRClient {
+	client := NewRTRClient("cache.example.net:323")
+	client.Dial = c.dial
+	return client
+}
+
+func (c *testCache) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	out := make(chan []byte, 16)
+	c.mu.Lock()
+	c.conns = append(c.conns, out)
+	c.mu.Unlock()
+
+	// Writes go through a queue, so a notify never blocks on an idle client
+	go func() {
+		defer server.Close()
+		for pdu := range out {
+			if _, err := server.Write(pdu); err != nil {
+				return
+			}
+		}
+	}()
+	go c.handle(server, out)
+	return client, nil
+}
+
+func (c *testCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, out := range c.conns {
+		close(out)
+	}
+	c.conns = nil
+}
+
+// update moves to the next serial and sends Serial Notify to every client.
+func (c *testCache) update(changes ...testChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serial++
+	c.history[c.serial] = changes
+	for _, ch := range changes {
+		if ch.announce {
+			c.roas[ch.roa] = true
+		} else {
+			delete(c.roas, ch.roa)
+		}
+	}
+	for _, out := range c.conns {
+		out <- testPDU(c.version, rtrSerialNotify, c.session, binary.BigEndian.AppendUint32(nil, c.serial))
+	}
+}
+
+// handle answers the queries of one client until it hangs up.
+func (c *testCache) handle(conn net.Conn, out chan []byte) {
+	for {
+		pdu, err := readRTRPDU(conn)
+		if err != nil {
+			return
+		}
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+		c.queries = append(c.queries, pdu.typ)
+		out <- c.answer(pdu)
+		if pdu.version > c.version {
+			// Unsupported version, so hang up after the error
+			c.conns = slices.DeleteFunc(c.conns, func(o chan []byte) bool { return o == out })
+			close(out)
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+	}
+}
+
+// answer builds the reply to a query.
+func (c *testCache) answer(q rtrPDU) []byte {
+	v := q.version
+	if v > c.version {
+		return testError(c.version, RTRUnsupportedVersion, "unsupported version")
+	}
+	if c.noData {
+		return testError(v, RTRNoDataAvailable, "no data yet")
+	}
+
+	var out []byte
+	switch q.typ {
+	case rtrResetQuery:
+		out = testPDU(v, rtrCacheResponse, c.session, nil)
+		for r := range c.roas {
+			out = append(out, testPrefix(v, r, true)...)
+		}
+		if v >= 1 {
+			for _, k := range c.keys {
+				body := append(append(k.SKI[:0:0], k.SKI[:]...), binary.BigEndian.AppendUint32(nil, k.ASN)...)
+				out = append(out, testPDU(v, rtrRouterKey, 1<<8, append(body, k.SPKI...))...)
+			}
+		}
+		if v >= 2 {
+			for customer, providers := range c.aspas {
+				body := binary.BigEndian.AppendUint32(nil, customer)
+				for _, p := range providers {
+					body = binary.BigEndian.AppendUint32(body, p)
+				}
+				out = append(out, testPDU(v, rtrASPA, 1<<8, body)...)
+			}
+		}
+	case rtrSerialQuery:
+		from := binary.BigEndian.Uint32(q.body)
+		if _, ok := c.history[from+1]; !ok && from != c.serial {
+			return testPDU(v, rtrCacheReset, 0, nil)
+		}
+		out = testPDU(v, rtrCacheResponse, c.session, nil)
+		for s := from + 1; s <= c.serial; s++ {
+			for _, ch := range c.history[s] {
+				out = append(out, testPrefix(v, ch.roa, ch.announce)...)
+			}
+		}
+	default:
+		return testError(v, RTRInvalidRequest, "")
+	}
+
+	eod := binary.BigEndian.AppendUint32(nil, c.serial)
+	if v >= 1 {
+		for _, timer := range []uint32{60, 30, 600} {
+			eod = binary.BigEndian.AppendUint32(eod, timer)
+		}
+	}
+	return append(out, testPDU(v, rtrEndOfData, c.session, eod)...)
+}
+
+func testPDU(version, typ uint8, session uint16, body []byte) []byte {
+	pdu := []byte{version, typ}
+	pdu = binary.BigEndian.AppendUint16(pdu, session)
+	pdu = binary.BigEndian.AppendUint32(pdu, uint32(8+len(body)))
+	return append(pdu, body...)
+}
+
+func testPrefix(version uint8, r ROA, announce bool) []byte {
+	var flags byte
+	if announce {
+		flags = 1
+	}
+	body := []byte{flags, byte(r.Prefix.Bits()), byte(r.MaxLength), 0}
+	body = append(body, r.Prefix.Addr().AsSlice()...)
+	body = binary.BigEndian.AppendUint32(body, r.ASN)
+	typ := uint8(rtrIPv6Prefix)
+	if r.Prefix.Addr().Is4() {
+		typ = rtrIPv4Prefix
+	}
+	return testPDU(version, typ, 0, body)
+}
+
+func testError(version uint8, code uint16, text string) []byte {
+	body := binary.BigEndian.AppendUint32(nil, 0)
+	body = binary.BigEndian.AppendUint32(body, uint32(len(text)))
+	return testPDU(version, rtrErrorReport, code, append(body, text...))
+}
+
+var (
+	roaGoogle  = ROA{Prefix: netip.MustParsePrefix("8.8.8.0/24"), MaxLength: 24, ASN: 15169}
+	roaTest    = ROA{Prefix: netip.MustParsePrefix("192.0.2.0/23"), MaxLength: 24, ASN: 64500}
+	roaGoogle6 = ROA{Prefix: netip.MustParsePrefix("2001:4860::/32"), MaxLength: 48, ASN: 15169}
+)
+
+func TestRTRClientSync(t *testing.T) {
+	cache := newTestCache(t, 1, roaGoogle, roaTest)
+	cache.keys = []RouterKey{{SKI: [20]byte{1, 2, 3}, ASN: 64500, SPKI: []byte{0x30, 0x59}}}
+	client := cache.client()
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !reflect.DeepEqual(client.ROAs(), []ROA{roaGoogle, roaTest}) {
+		t.Errorf("Unexpected ROAs %v", client.ROAs())
+	}
+	if keys := client.RouterKeys(); len(keys) != 1 || !reflect.DeepEqual(keys[0], cache.keys[0]) {
+		t.Errorf("Unexpected router keys %v", keys)
+	}
+	if session, serial, ok := client.Serial(); !ok || session != 4242 || serial != 1 {
+		t.Errorf("Unexpected serial %d/%d/%v", session, serial, ok)
+	}
+
+	v := client.Validator()
+	if got := v.Validate(netip.MustParsePrefix("192.0.3.0/24"), 64500); got != RValid {
+		t.Errorf("Expected valid, got %d", got)
+	}
+
+	// Incremental update on the same connection
+	cache.update(testChange{roa: roaTest}, testChange{roa: roaGoogle6, announce: true})
+	if err := client.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !reflect.DeepEqual(client.ROAs(), []ROA{roaGoogle, roaGoogle6}) {
+		t.Errorf("Unexpected ROAs %v", client.ROAs())
+	}
+	if _, serial, _ := client.Serial(); serial != 2 {
+		t.Errorf("Expected serial 2, got %d", serial)
+	}
+	vrps, _ := client.GetVRPs(15169)
+	if len(vrps) != 2 || vrps[1].Prefix.String() != "2001:4860::/32" {
+		t.Errorf("Unexpected VRPs %v", vrps)
+	}
+
+	// The earlier snapshot is unchanged
+	if got := v.Validate(netip.MustParsePrefix("192.0.3.0/24"), 64500); got != RValid {
+		t.Errorf("Expected the old snapshot to be unchanged, got %d", got)
+	}
+
+	cache.mu.Lock()
+	queries := cache.queries
+	cache.mu.Unlock()
+	if !reflect.DeepEqual(queries, []uint8{rtrResetQuery, rtrSerialQuery}) {
+		t.Errorf("Unexpected queries %v", queries)
+	}
+}
+
+func TestRTRClientVersionFallback(t *testing.T) {
+	cache := newTestCache(t, 0, roaGoogle)
+	client := cache.client()
+	defer client.Close()
+
+	if err := client.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if client.version != 0 {
+		t.Errorf("Expected to fall back to version 0, got %d", client.version)
+	}
+	if !reflect.DeepEqual(client.ROAs(), []ROA{roaGoogle}) {
+		t.Errorf("Unexpected ROAs %v", client.ROAs())
+	}
+}
+
+func TestRTRClientCacheReset(t *testing.T) {
+	cache := newTestCache(t, 1, roaGoogle)
+	client := cache.client()
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// The cache forgets how it got to the new serial
+	cache.update(testChange{roa: roaTest, announce: true})
+	cache.mu.Lock()
+	delete(cache.history, 2)
+	cache.mu.Unlock()
+
+	if err := client.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !reflect.DeepEqual(client.ROAs(), []ROA{roaGoogle, roaTest}) {
+		t.Errorf("Unexpected ROAs %v", client.ROAs())
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if !reflect.DeepEqual(cache.queries, []uint8{rtrResetQuery, rtrSerialQuery, rtrResetQuery}) {
+		t.Errorf("Unexpected queries %v", cache.queries)
+	}
+}
+
+func TestRTRClientASPA(t *testing.T) {
+	cache := newTestCache(t, 2, roaGoogle)
+	cache.aspas[64500] = []uint32{64501, 64502}
+	client := cache.client()
+	client.Version = 2
+	defer client.Close()
+
+	if err := client.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !reflect.DeepEqual(client.ASPAs(), []ASPA{{Customer: 64500, Providers: []uint32{64501, 64502}}}) {
+		t.Errorf("Unexpected ASPAs %v", client.ASPAs())
+	}
+}
+
+func TestRTRClientErrorReport(t *testing.T) {
+	cache := newTestCache(t, 1)
+	cache.noData = true
+	client := cache.client()
+	defer client.Close()
+
+	var rerr *RTRError
+	err := client.Sync(context.Background())
+	if !errors.As(err, &rerr) || rerr.Code != RTRNoDataAvailable || rerr.Text != "no data yet" {
+		t.Errorf("Expected No Data Available, got %v", err)
+	}
+	if _, _, ok := client.Serial(); ok {
+		t.Error("Expected no data after an error")
+	}
+}
+
+func TestRTRErrorReportTruncated(t *testing.T) {
+	// The length of the PDU in error claims far more than the body holds
+	err := rtrErrorFrom(rtrPDU{session: RTRNoDataAvailable, body: []byte{0xff, 0xff, 0xff, 0xf0}})
+	var rerr *RTRError
+	if !errors.As(err, &rerr) || rerr.Code != RTRNoDataAvailable || rerr.Text != "" {
+		t.Errorf("Expected a bare No Data Available, got %v", err)
+	}
+}
+
+func TestRTRClientRunError(t *testing.T) {
+	cache := newTestCache(t, 1)
+	cache.noData = true
+	client := cache.client()
+	defer client.Close()
+
+	failed := make(chan error, 10)
+	client.OnError = func(err error) { failed <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go client.Run(ctx)
+
+	select {
+	case err := <-failed:
+		var rerr *RTRError
+		if !errors.As(err, &rerr) || rerr.Code != RTRNoDataAvailable {
+			t.Errorf("Expected No Data Available, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for OnError")
+	}
+}
+
+func TestRTRClientRun(t *testing.T) {
+	cache := newTestCache(t, 1, roaGoogle)
+	client := cache.client()
+	defer client.Close()
+
+	synced := make(chan struct{}, 10)
+	client.OnSync = func() { synced <- struct{}{} }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- client.Run(ctx) }()
+
+	wait := func() {
+		select {
+		case <-synced:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a sync")
+		}
+	}
+	wait()
+
+	// Serial Notify triggers a sync long before the 60s refresh
+	cache.update(testChange{roa: roaTest, announce: true})
+	wait()
+	if !reflect.DeepEqual(client.ROAs(), []ROA{roaGoogle, roaTest}) {
+		t.Errorf("Unexpected ROAs %v", client.ROAs())
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
@@ -0,0 +1,62 @@
+package clidecode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CommandRunner runs an external program, such as vtysh or bgpctl, and
+// returns what it wrote to standard output.
+// Backends driven through a CLI use ExecRunner unless another runner is set,
+// which lets tests answer from captured output instead.
+type CommandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// ExecRunner runs the program on the local host.
+// Anything written to standard error is included in the returned error.
+func ExecRunner(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return nil, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+// runJSON runs a command and decodes its JSON output into v.
+// If ctx has no deadline, timeout (or DefaultTimeout) bounds the command.
+func runJSON(ctx context.Context, runner CommandRunner, timeout time.Duration, v any, name string, args ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if runner == nil {
+		runner = ExecRunner
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		if timeout <= 0 {
+			timeout = DefaultTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	out, err := runner(ctx, name, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("failed to decode output of %s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package clidecode
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+)
+
+// Session is a long-lived connection to the BIRD control socket.
+// Commands are serialized on the one connection, so a Session can be shared
+// by several clients. If BIRD closes the connection between commands, or the
+// greeting fails, the session reconnects and retries transparently.
+type Session struct {
+	SocketPath string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewSession creates a new Session for the given socket path.
+// The connection is opened lazily on the first query.
+func NewSession(socketPath string) *Session {
+	return &Session{SocketPath: socketPath}
+}
+
+// Query sends a command over the session and returns the response
+func (s *Session) Query(command string) (string, error) {
+	return s.QueryContext(context.Background(), command)
+}
+
+// QueryContext sends a command over the session and returns the response.
+// Cancelling ctx aborts the command and drops the connection, as BIRD would
+// otherwise still be writing the rest of the reply.
+func (s *Session) QueryContext(ctx context.Context, command string) (string, error) {
+	var out string
+	err := s.do(ctx, func(conn net.Conn, reader *bufio.Reader) error {
+		var err error
+		out, err = sendCommand(ctx, conn, reader, command)
+		return err
+	})
+	return out, err
+}
+
+// StreamContext sends a command over the session and hands each reply line to
+// emit as it is read. If emit returns an error the rest of the reply is
+// abandoned along with the connection.
+func (s *Session) StreamContext(ctx context.Context, command string, emit func(ReplyLine) error) error {
+	var emitted bool
+	return s.do(ctx, func(conn net.Conn, reader *bufio.Reader) error {
+		if emitted {
+			// Lines already handed out cannot be taken back, so never retry
+			return errors.New("session: connection lost mid-reply")
+		}
+		return streamCommand(ctx, conn, reader, command, func(rl ReplyLine) error {
+			emitted = true
+			return emit(rl)
+		})
+	})
+}
+
+// do runs a single exchange on the session connection, reconnecting once if
+// the connection turns out to have been closed while idle.
+func (s *Session) do(ctx context.Context, exchange func(net.Conn, *bufio.Reader) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	reused := s.conn != nil
+	err := s.doLocked(ctx, exchange)
+	if err != nil && reused && ctx.Err() == nil && isStaleConn(err) {
+		// BIRD dropped the idle connection, try once more on a fresh one
+		err = s.doLocked(ctx, exchange)
+	}
+	return err
+}
+
+// doLocked runs a single exchange, connecting first if needed.
+// Any failure other than an error reply from BIRD leaves the connection in an
+// unknown state, so it is closed and the next query will reconnect.
+func (s *Session) doLocked(ctx context.Context, exchange func(net.Conn, *bufio.Reader) error) error {
+	if s.conn == nil {
+		if err := s.connectLocked(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := exchange(s.conn, s.reader)
+	if err != nil && !isReplyError(err) {
+		s.closeLocked()
+	}
+	return err
+}
+
+// connectLocked dials the socket, retrying once if the greeting fails.
+func (s *Session) connectLocked(ctx context.Context) error {
+	conn, reader, err := dialSocket(ctx, s.SocketPath)
+	if err != nil && ctx.Err() == nil {
+		conn, reader, err = dialSocket(ctx, s.SocketPath)
+	}
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.reader = reader
+	return nil
+}
+
+// Close closes the underlying connection, if open.
+// The session may still be used afterwards, in which case it reconnects.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeLocked()
+}
+
+func (s *Session) closeLocked() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	s.reader = nil
+	return err
+}
+
+// isStaleConn reports whether err means the peer closed the connection.
+func isStaleConn(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
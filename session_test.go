@@ -0,0 +1,177 @@
+package clidecode
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serveBird starts a minimal BIRD control socket that answers "show status".
+// If closeAfter is set, the server drops each connection after that many commands.
+func serveBird(t *testing.T, closeAfter int) (string, *int32) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bird.ctl")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var accepted int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&accepted, 1)
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Write([]byte("0001 BIRD 2.0.8 ready.\n"))
+				scanner := bufio.NewScanner(conn)
+				served := 0
+				for scanner.Scan() {
+					if strings.TrimSpace(scanner.Text()) == "show route" {
+						// Simulate a full table dump that takes forever
+						continue
+					}
+					if strings.HasPrefix(scanner.Text(), "show route primary for ") {
+						conn.Write([]byte("8001 Network not in table\n"))
+						continue
+					}
+					if strings.TrimSpace(scanner.Text()) != "show status" {
+						conn.Write([]byte("9001 syntax error\n"))
+						continue
+					}
+					conn.Write([]byte("1000-BIRD 2.0.8\n1011-Router ID is 192.0.2.1\n0013 Daemon is up and running\n"))
+					served++
+					if closeAfter > 0 && served >= closeAfter {
+						return
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return path, &accepted
+}
+
+func TestSessionReusesConnection(t *testing.T) {
+	path, accepted := serveBird(t, 0)
+
+	client := NewBird2ConnWithSession(NewSession(path))
+	defer client.Session.Close()
+
+	for i := 0; i < 3; i++ {
+		ver, err := client.GetVersion()
+		if err != nil {
+			t.Fatalf("GetVersion failed: %v", err)
+		}
+		if ver != "BIRD 2.0.8" {
+			t.Errorf("Expected 'BIRD 2.0.8', got '%s'", ver)
+		}
+	}
+
+	// A BIRD error must not tear down the connection
+	if _, err := client.RunCommand("show nonsense"); err == nil {
+		t.Error("Expected error for invalid command")
+	}
+	if _, err := client.GetVersion(); err != nil {
+		t.Fatalf("GetVersion after BIRD error failed: %v", err)
+	}
+
+	if got := atomic.LoadInt32(accepted); got != 1 {
+		t.Errorf("Expected 1 connection, got %d", got)
+	}
+}
+
+func TestSessionReconnects(t *testing.T) {
+	path, accepted := serveBird(t, 1)
+
+	session := NewSession(path)
+	defer session.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := session.Query("show status"); err != nil {
+			t.Fatalf("Query %d failed: %v", i, err)
+		}
+	}
+
+	if got := atomic.LoadInt32(accepted); got != 3 {
+		t.Errorf("Expected 3 connections, got %d", got)
+	}
+}
+
+func TestSessionContextDeadline(t *testing.T) {
+	path, accepted := serveBird(t, 0)
+
+	client := NewBird3ConnWithSession(NewSession(path))
+	defer client.Session.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.RunCommandContext(ctx, "show route")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Deadline not honoured, took %v", elapsed)
+	}
+
+	// The half-read reply is abandoned, so the next command needs a new connection
+	if _, err := client.GetVersion(); err != nil {
+		t.Fatalf("GetVersion after deadline failed: %v", err)
+	}
+	if got := atomic.LoadInt32(accepted); got != 2 {
+		t.Errorf("Expected 2 connections, got %d", got)
+	}
+}
+
+func TestQueryContextCancelled(t *testing.T) {
+	client := &BirdClient{
+		Querier: mockQuerier(map[string]string{"show status": "BIRD 2.0.8"}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.GetVersionContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+}
+
+func TestSessionStreamStopEarly(t *testing.T) {
+	path, accepted := serveBird(t, 0)
+
+	session := NewSession(path)
+	defer session.Close()
+
+	var lines []ReplyLine
+	err := session.StreamContext(context.Background(), "show status", func(rl ReplyLine) error {
+		lines = append(lines, rl)
+		return errStopIteration
+	})
+	if !errors.Is(err, errStopIteration) {
+		t.Fatalf("Expected errStopIteration, got %v", err)
+	}
+	if len(lines) != 1 || lines[0] != (ReplyLine{Code: CodeVersion, Text: "BIRD 2.0.8"}) {
+		t.Errorf("Unexpected lines %+v", lines)
+	}
+
+	// The rest of the reply was abandoned, so the next query reconnects
+	if _, err := session.Query("show status"); err != nil {
+		t.Fatalf("Query after early stop failed: %v", err)
+	}
+	if got := atomic.LoadInt32(accepted); got != 2 {
+		t.Errorf("Expected 2 connections, got %d", got)
+	}
+}
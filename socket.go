@@ -2,12 +2,18 @@ package clidecode
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"os"
 	"strings"
 	"time"
 )
 
+// DefaultTimeout bounds a socket operation when the context carries no deadline.
+const DefaultTimeout = 10 * time.Second
+
 // querySocket sends a command to the BIRD control socket and returns the response.
 // The BIRD control protocol works as follows:
 // 1. Connect to the socket
@@ -21,106 +27,144 @@ import (
 //
 // Lines starting with ' ' (space) are continuation lines (part of previous line's data)
 // Lines starting with '+' are data lines with code
-func querySocket(socketPath, command string) (string, error) {
-	// Connect to Unix socket
-	conn, err := net.Dial("unix", socketPath)
+// An error reply is returned as a *ReplyError.
+//
+// Cancelling ctx aborts the exchange. If ctx has no deadline, DefaultTimeout applies.
+func querySocket(ctx context.Context, socketPath, command string) (string, error) {
+	conn, reader, err := dialSocket(ctx, socketPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to connect to socket %s: %w", socketPath, err)
+		return "", err
 	}
 	defer conn.Close()
 
-	// Set a deadline for all operations
-	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	return sendCommand(ctx, conn, reader, command)
+}
+
+// streamSocket is like querySocket, but hands each reply line to emit as it
+// is read rather than buffering the whole reply.
+func streamSocket(ctx context.Context, socketPath, command string, emit func(ReplyLine) error) error {
+	conn, reader, err := dialSocket(ctx, socketPath)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return streamCommand(ctx, conn, reader, command, emit)
+}
+
+// dialSocket connects to the BIRD control socket and consumes the greeting.
+func dialSocket(ctx context.Context, socketPath string) (net.Conn, *bufio.Reader, error) {
+	// Connect to Unix socket
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to socket %s: %w", socketPath, err)
+	}
+
+	// Set a deadline for the greeting
+	stop := watchContext(ctx, conn)
+	defer stop()
 
 	reader := bufio.NewReader(conn)
 
 	// Read the greeting
 	greeting, err := reader.ReadString('\n')
 	if err != nil {
-		return "", fmt.Errorf("failed to read greeting: %w", err)
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to read greeting: %w", contextError(ctx, err))
 	}
 
 	// Check if greeting indicates ready state (code 0001)
 	if !strings.HasPrefix(greeting, "0001") {
-		return "", fmt.Errorf("unexpected greeting: %s", greeting)
+		conn.Close()
+		return nil, nil, fmt.Errorf("unexpected greeting: %s", greeting)
 	}
 
+	return conn, reader, nil
+}
+
+// sendCommand writes a single command to an already greeted connection and reads back its reply.
+func sendCommand(ctx context.Context, conn net.Conn, reader *bufio.Reader, command string) (string, error) {
+	var out string
+	err := exchange(ctx, conn, command, func() error {
+		var err error
+		out, err = readReply(reader)
+		return err
+	})
+	return out, err
+}
+
+// streamCommand writes a single command to an already greeted connection and
+// streams its reply line by line to emit.
+func streamCommand(ctx context.Context, conn net.Conn, reader *bufio.Reader, command string, emit func(ReplyLine) error) error {
+	return exchange(ctx, conn, command, func() error {
+		return readReplyLines(reader, emit)
+	})
+}
+
+// exchange sends command and runs read to consume the reply, all bound by ctx.
+func exchange(ctx context.Context, conn net.Conn, command string, read func() error) error {
+	// Set a deadline for all operations
+	stop := watchContext(ctx, conn)
+	defer stop()
+
 	// Send the command
 	// Try sending with \r\n as some servers might be strict
-	_, err = fmt.Fprintf(conn, "%s\r\n", command)
+	_, err := fmt.Fprintf(conn, "%s\r\n", command)
 	if err != nil {
-		return "", fmt.Errorf("failed to send command: %w", err)
+		return fmt.Errorf("failed to send command: %w", contextError(ctx, err))
 	}
 
-	// Read the response
-	var output strings.Builder
-	for {
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			return "", fmt.Errorf("failed to read response: %w", err)
-		}
+	err = read()
+	if err != nil && !isReplyError(err) {
+		return contextError(ctx, err)
+	}
+	return err
+}
 
-		// Remove trailing newline
-		line = strings.TrimRight(line, "\n")
+// watchContext applies the context deadline, or DefaultTimeout, to conn and
+// unblocks any pending read or write once ctx is cancelled.
+// The returned function must be called when the operation completes.
+func watchContext(ctx context.Context, conn net.Conn) func() {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultTimeout)
+	}
+	conn.SetDeadline(deadline)
 
-		// Check for continuation line (starts with space)
-		if len(line) > 0 && line[0] == ' ' {
-			output.WriteString(line[1:]) // Skip the leading space
-			output.WriteString("\n")
-			continue
-		}
+	stop := context.AfterFunc(ctx, func() {
+		// A deadline in the past fails the blocked call immediately
+		conn.SetDeadline(time.Unix(1, 0))
+	})
+	return func() { stop() }
+}
 
-		// Check if this is a status line (first 4 characters are digits)
-		if len(line) >= 4 {
-			code := line[0:4]
-			isNumeric := true
-			for _, c := range code {
-				if c < '0' || c > '9' {
-					isNumeric = false
-					break
-				}
-			}
-
-			if isNumeric {
-				// Check for final status codes
-				// 0000-0999 are success codes
-				// 8000-8999 are runtime errors
-				// 9000-9999 are parse errors
-				if code[0] == '0' {
-					// Success - command completed
-					// If it's a data line (has space after code), include it
-					if len(line) > 5 && (line[4] == ' ' || line[4] == '-') {
-						output.WriteString(line[5:])
-						output.WriteString("\n")
-					}
-					break
-				} else if code[0] == '8' || code[0] == '9' {
-					// Error code
-					return "", fmt.Errorf("BIRD error: %s", line)
-				} else if code[0] >= '1' && code[0] <= '9' {
-					// Other codes (shouldn't happen for final status if we check 0xxx)
-					// Data line - extract the message part (skip code and separator)
-					if len(line) > 5 {
-						// Lines are formatted as "CODE-message" or "CODE message"
-						// We skip the first 5 characters (code + separator)
-						if line[4] == '-' || line[4] == ' ' {
-							output.WriteString(line[5:])
-							output.WriteString("\n")
-						}
-					}
-				}
-			} else {
-				// Not a status code (e.g. route starting with 8.8.8.8)
-				// Treat as data line
-				output.WriteString(line)
-				output.WriteString("\n")
-			}
-		} else if len(line) > 0 {
-			// Short line, treat as data
-			output.WriteString(line)
+// contextError prefers the context's error over the I/O error it caused.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	// The socket deadline can fire a moment before the context notices
+	if _, ok := ctx.Deadline(); ok && errors.Is(err, os.ErrDeadlineExceeded) {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+	return err
+}
+
+// readReply reads response lines until a final status code is received,
+// returning the text of the reply with the codes stripped.
+func readReply(reader *bufio.Reader) (string, error) {
+	var output strings.Builder
+	err := readReplyLines(reader, func(rl ReplyLine) error {
+		// Skip empty lines which carry nothing but the code, like the final "0000"
+		if rl.Text != "" || rl.Continuation {
+			output.WriteString(rl.Text)
 			output.WriteString("\n")
 		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
-
 	return strings.TrimSpace(output.String()), nil
 }
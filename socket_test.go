@@ -0,0 +1,84 @@
+package clidecode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mellowdrifter/clidecode/fakebird"
+)
+
+func TestSocketEndToEnd(t *testing.T) {
+	s := fakebird.NewServer(fakebird.Canned(map[string]fakebird.Reply{
+		// Written a few bytes at a time, so lines arrive split across reads
+		"show route table master4 table master6 count": {
+			Lines: []fakebird.Line{
+				{Code: CodeRoute, Text: "2076414 of 2076414 routes for 1038207 networks in table master4"},
+				{Code: CodeRoute, Text: "471160 of 471160 routes for 235580 networks in table master6"},
+				{Code: CodeRouteCount, Text: "Total: 2547574 of 2547574 routes for 1273787 networks in 2 tables"},
+			},
+			Chunk: 7,
+			Delay: time.Millisecond,
+		},
+		"show route primary for 192.0.2.1 table master4": fakebird.Error(CodeNotFound, "Network not in table"),
+	}))
+	defer s.Close()
+
+	session := NewSession(s.Path)
+	defer session.Close()
+
+	for _, client := range []*BirdClient{
+		&NewBird2ConnWithSocket(s.Path).BirdClient,
+		&NewBird3ConnWithSession(session).BirdClient,
+	} {
+		totals, err := client.GetBGPTotal()
+		if err != nil {
+			t.Fatalf("GetBGPTotal failed: %v", err)
+		}
+		if totals != (Totals{V4Rib: 2076414, V4Fib: 1038207, V6Rib: 471160, V6Fib: 235580}) {
+			t.Errorf("Unexpected totals %+v", totals)
+		}
+
+		if _, found, err := client.GetRoute(net.ParseIP("192.0.2.1")); err != nil || found {
+			t.Errorf("Expected not found, got %v, %v", found, err)
+		}
+
+		_, err = client.RunCommand("show nonsense")
+		var re *ReplyError
+		if !errors.As(err, &re) || !re.Syntax() {
+			t.Errorf("Expected syntax error, got %v", err)
+		}
+	}
+}
+
+func TestSocketBadGreeting(t *testing.T) {
+	s := fakebird.NewUnstartedServer(fakebird.Canned(nil))
+	s.Greeting = "8003 Access denied"
+	s.Start()
+	defer s.Close()
+
+	client := NewBird2ConnWithSocket(s.Path)
+	if _, err := client.GetVersion(); err == nil || !strings.Contains(err.Error(), "unexpected greeting") {
+		t.Errorf("Expected greeting error, got %v", err)
+	}
+}
+
+func TestSocketAbruptClose(t *testing.T) {
+	s := fakebird.NewServer(func(command string) fakebird.Reply {
+		// Half a reply, then the daemon goes away
+		return fakebird.Reply{Raw: "1007-1.0.0.0/24 unicast [bgp1 2025-11-19] * (100)\n", Close: true}
+	})
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client := NewBird2ConnWithSocket(s.Path)
+	_, err := client.RunCommandContext(ctx, "show route")
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || isReplyError(err) {
+		t.Errorf("Expected a read error, got %v", err)
+	}
+}
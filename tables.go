@@ -0,0 +1,140 @@
+package clidecode
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Tables names the BIRD tables queried by a BirdClient.
+// Empty fields fall back to the default BIRD 2 names.
+type Tables struct {
+	IPv4, IPv6   string // master4, master6
+	ROAv4, ROAv6 string // roa_v4, roa_v6
+
+	// Extra lists further tables, such as VRFs, to include in GetTableTotals
+	Extra []string
+}
+
+// TableTotal holds the route count of a single table.
+type TableTotal struct {
+	Table    string
+	Rib, Fib uint32
+}
+
+// routeCountRe matches a "show route count" line such as
+// 2076414 of 2076414 routes for 1038207 networks in table master4
+var routeCountRe = regexp.MustCompile(`(\d+)\s+of\s+\d+\s+routes\s+for\s+(\d+)\s+networks\s+in\s+table\s+(\S+)`)
+
+// tables returns the configured tables with defaults filled in.
+func (b *BirdClient) tables() Tables {
+	t := b.Tables
+	if t.IPv4 == "" {
+		t.IPv4 = "master4"
+	}
+	if t.IPv6 == "" {
+		t.IPv6 = "master6"
+	}
+	if t.ROAv4 == "" {
+		t.ROAv4 = "roa_v4"
+	}
+	if t.ROAv6 == "" {
+		t.ROAv6 = "roa_v6"
+	}
+	return t
+}
+
+// ribFor returns the routing table holding networks of the address family of ip.
+func (t Tables) ribFor(ip net.IP) string {
+	if ip.To4() != nil {
+		return t.IPv4
+	}
+	return t.IPv6
+}
+
+// roaFor returns the ROA table holding entries of the address family of ip.
+func (t Tables) roaFor(ip net.IP) string {
+	if ip.To4() != nil {
+		return t.ROAv4
+	}
+	return t.ROAv6
+}
+
+// GetTableTotals returns rib and fib counts for the IPv4 and IPv6 tables, then any extra tables
+func (b *BirdClient) GetTableTotals() ([]TableTotal, error) {
+	return b.GetTableTotalsContext(context.Background())
+}
+
+// GetTableTotalsContext is like GetTableTotals but honours the cancellation and deadline of ctx
+func (b *BirdClient) GetTableTotalsContext(ctx context.Context) ([]TableTotal, error) {
+	t := b.tables()
+	names := append([]string{t.IPv4, t.IPv6}, t.Extra...)
+
+	cmd := "show route"
+	for _, name := range names {
+		cmd += " table " + name
+	}
+	out, err := b.query(ctx, cmd+" count")
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]TableTotal)
+	for _, line := range strings.Split(out, "\n") {
+		if m := routeCountRe.FindStringSubmatch(line); m != nil {
+			counts[m[3]] = TableTotal{Table: m[3], Rib: stringToUint32(m[1]), Fib: stringToUint32(m[2])}
+		}
+	}
+
+	// Keep the requested order, reporting empty tables as zero
+	totals := make([]TableTotal, 0, len(names))
+	for _, name := range names {
+		total := counts[name]
+		total.Table = name
+		totals = append(totals, total)
+	}
+	return totals, nil
+}
+
+// DiscoverTables returns the names of all tables BIRD knows about, as listed by
+// "show symbols table", along with any only seen in "show route count"
+func (b *BirdClient) DiscoverTables() ([]string, error) {
+	return b.DiscoverTablesContext(context.Background())
+}
+
+// DiscoverTablesContext is like DiscoverTables but honours the cancellation and deadline of ctx
+func (b *BirdClient) DiscoverTablesContext(ctx context.Context) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	// Output format:
+	// master4          routing table
+	symbols, err := b.query(ctx, "show symbols table")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(symbols, "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && strings.Contains(line, "table") {
+			add(fields[0])
+		}
+	}
+
+	counts, err := b.query(ctx, "show route count")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(counts, "\n") {
+		if m := routeCountRe.FindStringSubmatch(line); m != nil {
+			add(m[3])
+		}
+	}
+
+	return names, nil
+}
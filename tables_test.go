@@ -0,0 +1,80 @@
+package clidecode
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestCustomTables(t *testing.T) {
+	responses := map[string]string{
+		"show route table t4 table t6 table vrf_blue count": `1007-10 of 12 routes for 5 networks in table t4
+ 4 of 4 routes for 2 networks in table t6
+ Total: 14 of 16 routes for 7 networks in 3 tables`,
+		"show route primary for 192.0.2.1 table t4": `Table t4:
+192.0.2.0/24         unicast [bgp1 2025-11-19 from 198.51.100.1] * (100) [AS64496i]`,
+		"eval roa_check(r6, 2001:db8::/32, 64496)": `(enum 35)1`,
+	}
+
+	client := &BirdClient{
+		Querier: mockQuerier(responses),
+		Tables:  Tables{IPv4: "t4", IPv6: "t6", ROAv6: "r6", Extra: []string{"vrf_blue"}},
+	}
+
+	totals, err := client.GetTableTotals()
+	if err != nil {
+		t.Fatalf("GetTableTotals failed: %v", err)
+	}
+	expected := []TableTotal{
+		{Table: "t4", Rib: 10, Fib: 5},
+		{Table: "t6", Rib: 4, Fib: 2},
+		{Table: "vrf_blue"},
+	}
+	if !reflect.DeepEqual(totals, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, totals)
+	}
+
+	bgp, err := client.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if bgp != (Totals{V4Rib: 10, V4Fib: 5, V6Rib: 4, V6Fib: 2}) {
+		t.Errorf("Unexpected totals %+v", bgp)
+	}
+
+	prefix, found, err := client.GetRoute(net.ParseIP("192.0.2.1"))
+	if err != nil || !found || prefix.String() != "192.0.2.0/24" {
+		t.Errorf("GetRoute returned %v, %v, %v", prefix, found, err)
+	}
+
+	_, ipnet, _ := net.ParseCIDR("2001:db8::/32")
+	status, found, err := client.GetROA(ipnet, 64496)
+	if err != nil || !found || status != RValid {
+		t.Errorf("GetROA returned %d, %v, %v", status, found, err)
+	}
+}
+
+func TestDiscoverTables(t *testing.T) {
+	responses := map[string]string{
+		"show symbols table": `master4          routing table
+master6          routing table
+vrf_blue         routing table
+roa_v4           routing table`,
+		"show route count": `1007-10 of 10 routes for 10 networks in table master4
+ 3 of 3 routes for 3 networks in table vrf_red
+ Total: 13 of 13 routes for 13 networks in 2 tables`,
+	}
+
+	client := &BirdClient{
+		Querier: mockQuerier(responses),
+	}
+
+	tables, err := client.DiscoverTables()
+	if err != nil {
+		t.Fatalf("DiscoverTables failed: %v", err)
+	}
+	expected := []string{"master4", "master6", "vrf_blue", "roa_v4", "vrf_red"}
+	if !reflect.DeepEqual(tables, expected) {
+		t.Errorf("Expected %v, got %v", expected, tables)
+	}
+}
@@ -0,0 +1,206 @@
+package clidecode
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Exchange is a command sent to BIRD and the raw reply it got back, codes included.
+// Err holds the failure, if the exchange did not complete.
+type Exchange struct {
+	Command string
+	Reply   string
+	Err     string
+}
+
+// Transcript is a recording of the exchanges with a BIRD daemon.
+//
+// On disk, each exchange is the command prefixed with "> ", followed by the
+// reply exactly as BIRD sent it. A failed exchange also carries a line
+// prefixed with "! " holding the error. Lines starting with "#" are comments.
+//
+//	> show status
+//	1000-BIRD 2.0.8
+//	1011-Router ID is 192.0.2.1
+//	0013 Daemon is up and running
+type Transcript struct {
+	Exchanges []Exchange
+}
+
+// ReadTranscript parses a transcript.
+func ReadTranscript(r io.Reader) (*Transcript, error) {
+	t := &Transcript{}
+	var current *Exchange
+	var reply strings.Builder
+
+	flush := func() {
+		if current != nil {
+			current.Reply = reply.String()
+			t.Exchanges = append(t.Exchanges, *current)
+		}
+		reply.Reset()
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "> "):
+			flush()
+			current = &Exchange{Command: line[2:]}
+		case current == nil:
+			return nil, fmt.Errorf("transcript line %d: reply before any command", n)
+		case strings.HasPrefix(line, "! "):
+			current.Err = line[2:]
+		default:
+			reply.WriteString(line)
+			reply.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return t, nil
+}
+
+// LoadTranscript reads a transcript file.
+func LoadTranscript(path string) (*Transcript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadTranscript(f)
+}
+
+// WriteTo writes the transcript in the format read by ReadTranscript.
+func (t *Transcript) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, e := range t.Exchanges {
+		n, err := io.WriteString(w, e.encode())
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// encode returns the exchange as written to a transcript.
+func (e Exchange) encode() string {
+	var b strings.Builder
+	b.WriteString("> " + e.Command + "\n")
+	b.WriteString(e.Reply)
+	if e.Reply != "" && !strings.HasSuffix(e.Reply, "\n") {
+		b.WriteString("\n")
+	}
+	if e.Err != "" {
+		b.WriteString("! " + e.Err + "\n")
+	}
+	return b.String()
+}
+
+// Replies returns the raw replies to each command, in the order they were recorded.
+// The result can be served with fakebird.Replay, which closes the connection
+// after the partial reply of a failed exchange.
+func (t *Transcript) Replies() map[string][]string {
+	replies := make(map[string][]string)
+	for _, e := range t.Exchanges {
+		replies[e.Command] = append(replies[e.Command], e.Reply)
+	}
+	return replies
+}
+
+// Querier returns a Querier answering from the transcript.
+// A command recorded several times is answered with each reply in turn, then the
+// last one again. Commands missing from the transcript fail.
+func (t *Transcript) Querier() func(socketPath, command string) (string, error) {
+	var mu sync.Mutex
+	served := make(map[string]int)
+	byCommand := make(map[string][]Exchange)
+	for _, e := range t.Exchanges {
+		byCommand[e.Command] = append(byCommand[e.Command], e)
+	}
+
+	return func(_, command string) (string, error) {
+		exchanges, ok := byCommand[command]
+		if !ok {
+			return "", fmt.Errorf("command not in transcript: %s", command)
+		}
+		mu.Lock()
+		i := min(served[command], len(exchanges)-1)
+		served[command]++
+		mu.Unlock()
+
+		e := exchanges[i]
+		if e.Err != "" {
+			return "", errors.New(e.Err)
+		}
+		return readReply(bufio.NewReader(strings.NewReader(e.Reply)))
+	}
+}
+
+// Recorder queries the BIRD socket and writes every exchange, with the raw reply, to a transcript.
+type Recorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewRecorder creates a Recorder writing to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Query sends a command to the BIRD socket at socketPath and records the exchange.
+// It can be used as the Querier of a BirdClient.
+func (r *Recorder) Query(socketPath, command string) (string, error) {
+	return r.QueryContext(context.Background(), socketPath, command)
+}
+
+// QueryContext is like Query but honours the cancellation and deadline of ctx
+func (r *Recorder) QueryContext(ctx context.Context, socketPath, command string) (string, error) {
+	out, raw, err := queryRaw(ctx, socketPath, command)
+
+	e := Exchange{Command: command, Reply: raw}
+	if err != nil && !isReplyError(err) {
+		// Error replies are part of the raw reply, anything else is recorded apart
+		e.Err = err.Error()
+	}
+
+	r.mu.Lock()
+	_, werr := io.WriteString(r.w, e.encode())
+	r.mu.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+	if werr != nil {
+		return "", fmt.Errorf("failed to write transcript: %w", werr)
+	}
+	return out, nil
+}
+
+// queryRaw is like querySocket, but also returns the reply bytes as read from the socket.
+func queryRaw(ctx context.Context, socketPath, command string) (string, string, error) {
+	conn, reader, err := dialSocket(ctx, socketPath)
+	if err != nil {
+		return "", "", err
+	}
+	defer conn.Close()
+
+	var raw bytes.Buffer
+	tee := bufio.NewReader(io.TeeReader(reader, &raw))
+	out, err := sendCommand(ctx, conn, tee, command)
+	return out, raw.String(), err
+}
@@ -0,0 +1,135 @@
+package clidecode
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mellowdrifter/clidecode/fakebird"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	s := fakebird.NewServer(fakebird.Canned(map[string]fakebird.Reply{
+		"show route table master4 table master6 count": {Lines: []fakebird.Line{
+			{Code: CodeRoute, Text: "10 of 12 routes for 5 networks in table master4"},
+			{Code: CodeRoute, Text: "4 of 4 routes for 2 networks in table master6"},
+			{Code: CodeRouteCount, Text: "Total: 14 of 16 routes for 7 networks in 2 tables"},
+		}},
+	}))
+	defer s.Close()
+
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf)
+	client := &BirdClient{SocketPath: s.Path, Querier: recorder.Query}
+
+	want, err := client.GetBGPTotal()
+	if err != nil {
+		t.Fatalf("GetBGPTotal failed: %v", err)
+	}
+	if _, err := client.RunCommand("show nonsense"); err == nil {
+		t.Fatal("Expected error for invalid command")
+	}
+
+	recorded := "> show route table master4 table master6 count\n" +
+		"1007-10 of 12 routes for 5 networks in table master4\n" +
+		" 4 of 4 routes for 2 networks in table master6\n" +
+		"0014 Total: 14 of 16 routes for 7 networks in 2 tables\n" +
+		"> show nonsense\n" +
+		"9001 syntax error, unexpected CF_SYM_UNDEFINED\n"
+	if buf.String() != recorded {
+		t.Errorf("Unexpected transcript:\n%s", buf.String())
+	}
+
+	transcript, err := ReadTranscript(&buf)
+	if err != nil {
+		t.Fatalf("ReadTranscript failed: %v", err)
+	}
+
+	// Replayed through a Querier, and through a fake socket with codes intact
+	replay := fakebird.NewServer(fakebird.Replay(transcript.Replies()))
+	defer replay.Close()
+
+	for name, client := range map[string]*BirdClient{
+		"querier": {Querier: transcript.Querier()},
+		"socket":  {SocketPath: replay.Path},
+	} {
+		got, err := client.GetBGPTotal()
+		if err != nil {
+			t.Fatalf("%s: GetBGPTotal failed: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s: Expected %+v, got %+v", name, want, got)
+		}
+		var re *ReplyError
+		if _, err := client.RunCommand("show nonsense"); !errors.As(err, &re) || !re.Syntax() {
+			t.Errorf("%s: Expected syntax error, got %v", name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	transcript.WriteTo(&out)
+	if out.String() != recorded {
+		t.Errorf("WriteTo did not round trip:\n%s", out.String())
+	}
+}
+
+func TestReplayTranscript(t *testing.T) {
+	transcript, err := LoadTranscript("testdata/bird2.transcript")
+	if err != nil {
+		t.Fatalf("LoadTranscript failed: %v", err)
+	}
+
+	s := fakebird.NewServer(fakebird.Replay(transcript.Replies()))
+	defer s.Close()
+
+	client := NewBird2ConnWithSocket(s.Path)
+
+	ver, err := client.GetVersion()
+	if err != nil || ver != "BIRD 2.0.8" {
+		t.Errorf("GetVersion returned %q, %v", ver, err)
+	}
+
+	sessions, err := client.GetBGPSessions()
+	if err != nil {
+		t.Fatalf("GetBGPSessions failed: %v", err)
+	}
+	var names []string
+	for _, s := range sessions {
+		names = append(names, s.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"bgp1", "bgp2"}) {
+		t.Errorf("Unexpected sessions %v", names)
+	}
+
+	peers, err := client.GetPeers()
+	if err != nil {
+		t.Fatalf("GetPeers failed: %v", err)
+	}
+	if peers != (Peers{V4c: 1, V4e: 1, V6c: 2, V6e: 1}) {
+		t.Errorf("Unexpected peers %+v", peers)
+	}
+
+	if _, found, err := client.GetRoute(net.ParseIP("203.0.113.1")); err != nil || found {
+		t.Errorf("Expected not found, got %v, %v", found, err)
+	}
+}
+
+func TestReplayFailedExchange(t *testing.T) {
+	transcript := &Transcript{Exchanges: []Exchange{
+		{Command: "show status", Reply: "1000-BIRD 2.0.8\n", Err: "read unix: i/o timeout"},
+	}}
+	s := fakebird.NewServer(fakebird.Replay(transcript.Replies()))
+	defer s.Close()
+
+	client := &BirdClient{SocketPath: s.Path, Timeout: 5 * time.Second}
+	start := time.Now()
+	if _, err := client.RunCommand("show status"); err == nil {
+		t.Error("Expected an error for a failed exchange")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Failed exchange took %v, expected the connection to be closed", elapsed)
+	}
+}
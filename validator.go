@@ -0,0 +1,327 @@
+package clidecode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ROA is a Validated ROA Payload authorising an ASN to originate a prefix.
+type ROA struct {
+	Prefix    netip.Prefix `json:"prefix" yaml:"prefix"`
+	MaxLength int          `json:"max_length" yaml:"max_length"`
+	ASN       uint32       `json:"asn" yaml:"asn"`
+}
+
+// Validator performs RPKI origin validation, as described in RFC 6811, against
+// a fixed set of VRPs held in a binary prefix trie per address family.
+// A nil Validator knows no VRPs, so it finds every route unknown.
+//
+// Set as BirdClient.Validator, or given to MRTDecoder.SetValidator, it takes
+// the place of the router's own ROA tables.
+type Validator struct {
+	v4, v6 *roaNode
+	byASN  map[uint32][]ROA
+	count  int
+}
+
+// roaNode is a trie node. The ROAs stored at a node are those whose prefix
+// ends at it, and the path from the root spells out the prefix bits.
+type roaNode struct {
+	children [2]*roaNode
+	roas     []ROA
+}
+
+// NewValidator builds a Validator from a set of VRPs.
+// A missing max length is taken to be the prefix length.
+func NewValidator(roas []ROA) *Validator {
+	v := &Validator{v4: &roaNode{}, v6: &roaNode{}, byASN: make(map[uint32][]ROA)}
+	for _, r := range roas {
+		if !r.Prefix.IsValid() {
+			continue
+		}
+		r.Prefix = r.Prefix.Masked()
+		if r.MaxLength < r.Prefix.Bits() {
+			r.MaxLength = r.Prefix.Bits()
+		}
+		n := v.root(r.Prefix.Addr())
+		for i := range r.Prefix.Bits() {
+			b := addrBit(r.Prefix.Addr(), i)
+			if n.children[b] == nil {
+				n.children[b] = &roaNode{}
+			}
+			n = n.children[b]
+		}
+		n.roas = append(n.roas, r)
+		v.byASN[r.ASN] = append(v.byASN[r.ASN], r)
+		v.count++
+	}
+	return v
+}
+
+// LoadValidator builds a Validator from a VRP export file
+func LoadValidator(path string) (*Validator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	roas, err := ReadVRPs(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return NewValidator(roas), nil
+}
+
+func (v *Validator) root(addr netip.Addr) *roaNode {
+	if addr.Is4() {
+		return v.v4
+	}
+	return v.v6
+}
+
+// addrBit returns bit i of addr, counting from the most significant.
+func addrBit(addr netip.Addr, i int) int {
+	b := addr.AsSlice()
+	return int(b[i/8]>>(7-i%8)) & 1
+}
+
+// Len returns the number of VRPs loaded.
+func (v *Validator) Len() int {
+	if v == nil {
+		return 0
+	}
+	return v.count
+}
+
+// Covering returns the VRPs covering prefix, least specific first.
+func (v *Validator) Covering(prefix netip.Prefix) []ROA {
+	if v == nil || !prefix.IsValid() {
+		return nil
+	}
+	prefix = prefix.Masked()
+	var roas []ROA
+	n := v.root(prefix.Addr())
+	for i := 0; n != nil; i++ {
+		roas = append(roas, n.roas...)
+		if i == prefix.Bits() {
+			break
+		}
+		n = n.children[addrBit(prefix.Addr(), i)]
+	}
+	return roas
+}
+
+// Validate returns the origin validation state of prefix announced by origin.
+// An origin of 0, as for routes ending in an AS set, never matches.
+func (v *Validator) Validate(prefix netip.Prefix, origin uint32) int {
+	covering := v.Covering(prefix)
+	if len(covering) == 0 {
+		return RUnknown
+	}
+	for _, r := range covering {
+		if origin != 0 && r.ASN == origin && prefix.Bits() <= r.MaxLength {
+			return RValid
+		}
+	}
+	return RInvalid
+}
+
+// VRPs returns the VRPs authorising asn, in the order they were loaded.
+func (v *Validator) VRPs(asn uint32) []ROA {
+	if v == nil {
+		return nil
+	}
+	return slices.Clone(v.byASN[asn])
+}
+
+// ValidateRoutes validates the origin of each route against v.
+// It returns the count of each state and the invalid networks keyed by origin,
+// as GetROAs and GetInvalids would. Routes without a source ASN are counted but
+// not listed as invalid.
+func (v *Validator) ValidateRoutes(routes iter.Seq2[Route, error]) (Roas, map[string][]string, error) {
+	var rs Roas
+	inv := make(map[string][]string)
+	for r, err := range routes {
+		if err != nil {
+			return rs, inv, err
+		}
+		prefix, ok := PrefixFromIPNet(r.Prefix)
+		if !ok {
+			continue
+		}
+		v4 := prefix.Addr().Is4()
+		switch v.Validate(prefix, r.SourceASN) {
+		case RValid:
+			if v4 {
+				rs.V4v++
+			} else {
+				rs.V6v++
+			}
+		case RInvalid:
+			if v4 {
+				rs.V4i++
+			} else {
+				rs.V6i++
+			}
+			if r.SourceASN != 0 {
+				asn := strconv.FormatUint(uint64(r.SourceASN), 10)
+				inv[asn] = append(inv[asn], prefix.String())
+			}
+		default:
+			if v4 {
+				rs.V4u++
+			} else {
+				rs.V6u++
+			}
+		}
+	}
+	return rs, inv, nil
+}
+
+// vrpExport is the JSON written by rpki-client, Routinator and OctoRPKI.
+// They differ in how they spell ASNs, which vrpASN accepts either way.
+type vrpExport struct {
+	ROAs []struct {
+		ASN        vrpASN `json:"asn"`
+		Prefix     string `json:"prefix"`
+		MaxLength  int    `json:"maxLength"`
+		MaxLength2 int    `json:"max_length"`
+	} `json:"roas"`
+}
+
+// vrpASN is an ASN written as a number, or as a string with or without an AS prefix.
+type vrpASN uint32
+
+func (a *vrpASN) UnmarshalJSON(data []byte) error {
+	asn, err := parseVRPASN(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*a = vrpASN(asn)
+	return nil
+}
+
+func parseVRPASN(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 2 && strings.EqualFold(s[:2], "AS") {
+		s = s[2:]
+	}
+	asn, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ASN %q", s)
+	}
+	return uint32(asn), nil
+}
+
+// ReadVRPs reads a VRP export, either the JSON written by rpki-client, Routinator
+// and OctoRPKI, or CSV with ASN, prefix and max length columns.
+// CSV columns are found from the header row, if there is one.
+func ReadVRPs(r io.Reader) ([]ROA, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			if b[0] == '{' {
+				return readVRPJSON(br)
+			}
+			return readVRPCSV(br)
+		}
+		br.ReadByte()
+	}
+}
+
+func readVRPJSON(r io.Reader) ([]ROA, error) {
+	var export vrpExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid VRP JSON: %w", err)
+	}
+	roas := make([]ROA, 0, len(export.ROAs))
+	for _, e := range export.ROAs {
+		prefix, err := netip.ParsePrefix(e.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VRP prefix: %w", err)
+		}
+		maxLength := e.MaxLength
+		if maxLength == 0 {
+			maxLength = e.MaxLength2
+		}
+		roas = append(roas, ROA{Prefix: prefix, MaxLength: maxLength, ASN: uint32(e.ASN)})
+	}
+	return roas, nil
+}
+
+func readVRPCSV(r io.Reader) ([]ROA, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	// Without a header, columns are ASN, prefix, max length
+	asnCol, prefixCol, maxCol := 0, 1, 2
+	var roas []ROA
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return roas, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid VRP CSV: %w", err)
+		}
+		if first {
+			if _, err := parseVRPASN(rec[0]); err != nil {
+				asnCol, prefixCol, maxCol = -1, -1, -1
+				for i, name := range rec {
+					switch strings.ToLower(strings.TrimSpace(name)) {
+					case "asn", "as", "origin":
+						asnCol = i
+					case "ip prefix", "prefix":
+						prefixCol = i
+					case "max length", "maxlength", "max_length", "max-length":
+						maxCol = i
+					}
+				}
+				if asnCol < 0 || prefixCol < 0 {
+					return nil, fmt.Errorf("invalid VRP CSV: no ASN or prefix column in header %v", rec)
+				}
+				continue
+			}
+		}
+
+		if asnCol >= len(rec) || prefixCol >= len(rec) {
+			return nil, fmt.Errorf("invalid VRP CSV: short record %v", rec)
+		}
+		asn, err := parseVRPASN(rec[asnCol])
+		if err != nil {
+			return nil, fmt.Errorf("invalid VRP CSV: %w", err)
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(rec[prefixCol]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid VRP CSV: %w", err)
+		}
+		var maxLength int
+		if maxCol >= 0 && maxCol < len(rec) && strings.TrimSpace(rec[maxCol]) != "" {
+			maxLength, err = strconv.Atoi(strings.TrimSpace(rec[maxCol]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid VRP CSV: max length %q", rec[maxCol])
+			}
+		}
+		roas = append(roas, ROA{Prefix: prefix, MaxLength: maxLength, ASN: asn})
+	}
+}
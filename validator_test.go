@@ -0,0 +1,149 @@
+package clidecode
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadValidator(t *testing.T) {
+	want := []ROA{
+		{Prefix: netip.MustParsePrefix("8.8.8.0/24"), MaxLength: 24, ASN: 15169},
+		{Prefix: netip.MustParsePrefix("192.0.2.0/23"), MaxLength: 24, ASN: 64500},
+		{Prefix: netip.MustParsePrefix("198.51.100.0/24"), MaxLength: 24, ASN: 0},
+		{Prefix: netip.MustParsePrefix("2001:4860::/32"), MaxLength: 48, ASN: 15169},
+	}
+	for _, path := range []string{
+		"testdata/vrps/rpki-client.json",
+		"testdata/vrps/routinator.json",
+		"testdata/vrps/octorpki.json",
+		"testdata/vrps/routinator.csv",
+	} {
+		v, err := LoadValidator(path)
+		if err != nil {
+			t.Errorf("LoadValidator(%s) failed: %v", path, err)
+			continue
+		}
+		if v.Len() != len(want) {
+			t.Errorf("Expected %d VRPs from %s, got %d", len(want), path, v.Len())
+		}
+		var got []ROA
+		for _, asn := range []uint32{15169, 64500, 0} {
+			got = append(got, v.VRPs(asn)...)
+		}
+		if !reflect.DeepEqual(got, []ROA{want[0], want[3], want[1], want[2]}) {
+			t.Errorf("Unexpected VRPs from %s: %v", path, got)
+		}
+	}
+}
+
+func TestReadVRPsCSV(t *testing.T) {
+	// No header, and no max length
+	roas, err := ReadVRPs(strings.NewReader("64500,192.0.2.0/24\n64501,2001:db8::/32,48\n"))
+	if err != nil {
+		t.Fatalf("ReadVRPs failed: %v", err)
+	}
+	want := []ROA{
+		{Prefix: netip.MustParsePrefix("192.0.2.0/24"), ASN: 64500},
+		{Prefix: netip.MustParsePrefix("2001:db8::/32"), MaxLength: 48, ASN: 64501},
+	}
+	if !reflect.DeepEqual(roas, want) {
+		t.Errorf("Expected %v, got %v", want, roas)
+	}
+
+	if _, err := ReadVRPs(strings.NewReader("Origin,Network\nAS64500,not-a-prefix\n")); err == nil {
+		t.Error("Expected an error for a bad prefix")
+	}
+	if _, err := ReadVRPs(strings.NewReader("Name,Value\nfoo,bar\n")); err == nil {
+		t.Error("Expected an error for a header without ASN and prefix columns")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	v, err := LoadValidator("testdata/vrps/rpki-client.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		prefix string
+		origin uint32
+		want   int
+	}{
+		{"8.8.8.0/24", 15169, RValid},
+		{"8.8.8.0/24", 64496, RInvalid},
+		{"8.8.8.0/25", 15169, RInvalid}, // longer than the max length
+		{"8.8.0.0/16", 15169, RUnknown}, // less specific than any VRP
+		{"192.0.2.0/24", 64500, RValid},
+		{"192.0.3.0/24", 64500, RValid},
+		{"192.0.2.0/25", 64500, RInvalid},
+		{"198.51.100.0/24", 0, RInvalid}, // AS0 never validates
+		{"198.51.100.0/24", 64496, RInvalid},
+		{"203.0.113.0/24", 64496, RUnknown},
+		{"2001:4860:4860::/48", 15169, RValid},
+		{"2001:4860:4860::/64", 15169, RInvalid},
+		{"2001:db8::/32", 15169, RUnknown},
+		{"::/0", 15169, RUnknown},
+	}
+	for _, test := range tests {
+		if got := v.Validate(netip.MustParsePrefix(test.prefix), test.origin); got != test.want {
+			t.Errorf("Validate(%s, %d) = %d, want %d", test.prefix, test.origin, got, test.want)
+		}
+	}
+
+	covering := v.Covering(netip.MustParsePrefix("192.0.3.128/25"))
+	if len(covering) != 1 || covering[0].ASN != 64500 {
+		t.Errorf("Unexpected covering VRPs %v", covering)
+	}
+
+	var nilValidator *Validator
+	if got := nilValidator.Validate(netip.MustParsePrefix("8.8.8.0/24"), 15169); got != RUnknown {
+		t.Errorf("Expected a nil Validator to find routes unknown, got %d", got)
+	}
+}
+
+func TestBirdClientValidator(t *testing.T) {
+	v, err := LoadValidator("testdata/vrps/rpki-client.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &BirdClient{
+		Querier: mockQuerier(map[string]string{
+			"show route primary table master4": `Table master4:
+8.8.8.0/24           unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS15169i]
+192.0.2.0/24         unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS64511i]
+203.0.113.0/24       unicast [bgp1 2025-11-19 from 192.0.2.1] * (100) [AS64496i]`,
+			"show route primary table master6": `Table master6:
+2001:4860::/32       unicast [bgp1 2025-11-19 from 2001:db8::1] * (100) [AS15169i]`,
+		}),
+		Validator: v,
+	}
+
+	roas, err := client.GetROAs()
+	if err != nil {
+		t.Fatalf("GetROAs failed: %v", err)
+	}
+	if roas != (Roas{V4v: 1, V4i: 1, V4u: 1, V6v: 1}) {
+		t.Errorf("Unexpected ROAs %+v", roas)
+	}
+
+	invalids, err := client.GetInvalids()
+	if err != nil {
+		t.Fatalf("GetInvalids failed: %v", err)
+	}
+	if !reflect.DeepEqual(invalids, map[string][]string{"64511": {"192.0.2.0/24"}}) {
+		t.Errorf("Unexpected invalids %v", invalids)
+	}
+
+	// Answered without asking BIRD
+	_, prefix, _ := net.ParseCIDR("192.0.2.0/24")
+	if status, found, err := client.GetROA(prefix, 64500); err != nil || !found || status != RValid {
+		t.Errorf("GetROA returned %d, %v, %v", status, found, err)
+	}
+	vrps, err := client.GetVRPs(15169)
+	if err != nil || len(vrps) != 2 || vrps[1].Prefix.String() != "2001:4860::/32" || vrps[1].Max != 48 {
+		t.Errorf("GetVRPs returned %+v, %v", vrps, err)
+	}
+}
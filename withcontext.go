@@ -0,0 +1,116 @@
+package clidecode
+
+import (
+	"context"
+	"net"
+)
+
+// WithContext returns d as a DecoderContext. A Decoder already implementing it
+// is returned as is. Otherwise each call runs the plain method in its own
+// goroutine and stops waiting for it once ctx is done, though the query itself
+// carries on until d returns.
+func WithContext(d Decoder) DecoderContext {
+	if dc, ok := d.(DecoderContext); ok {
+		return dc
+	}
+	return plainDecoder{d}
+}
+
+// plainDecoder adapts a Decoder without context support
+type plainDecoder struct {
+	d Decoder
+}
+
+// await runs f, giving up when ctx is done
+func await[T any](ctx context.Context, f func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	type answer struct {
+		v   T
+		err error
+	}
+	done := make(chan answer, 1)
+	go func() {
+		v, err := f()
+		done <- answer{v, err}
+	}()
+	select {
+	case a := <-done:
+		return a.v, a.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// found is the answer of a lookup returning whether it found anything
+type found[T any] struct {
+	v  T
+	ok bool
+}
+
+// awaitFound is like await for lookups
+func awaitFound[T any](ctx context.Context, f func() (T, bool, error)) (T, bool, error) {
+	a, err := await(ctx, func() (found[T], error) {
+		v, ok, err := f()
+		return found[T]{v, ok}, err
+	})
+	return a.v, a.ok, err
+}
+
+func (p plainDecoder) GetBGPTotalContext(ctx context.Context) (Totals, error) {
+	return await(ctx, p.d.GetBGPTotal)
+}
+
+func (p plainDecoder) GetPeersContext(ctx context.Context) (Peers, error) {
+	return await(ctx, p.d.GetPeers)
+}
+
+func (p plainDecoder) GetTotalSourceASNsContext(ctx context.Context) (ASNs, error) {
+	return await(ctx, p.d.GetTotalSourceASNs)
+}
+
+func (p plainDecoder) GetMasksContext(ctx context.Context) ([]map[string]uint32, error) {
+	return await(ctx, p.d.GetMasks)
+}
+
+func (p plainDecoder) GetROAsContext(ctx context.Context) (Roas, error) {
+	return await(ctx, p.d.GetROAs)
+}
+
+func (p plainDecoder) GetLargeCommunitiesContext(ctx context.Context) (Large, error) {
+	return await(ctx, p.d.GetLargeCommunities)
+}
+
+func (p plainDecoder) GetIPv4FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	return await(ctx, func() ([]*net.IPNet, error) { return p.d.GetIPv4FromSource(asn) })
+}
+
+func (p plainDecoder) GetIPv6FromSourceContext(ctx context.Context, asn uint32) ([]*net.IPNet, error) {
+	return await(ctx, func() ([]*net.IPNet, error) { return p.d.GetIPv6FromSource(asn) })
+}
+
+func (p plainDecoder) GetOriginFromIPContext(ctx context.Context, ip net.IP) (uint32, bool, error) {
+	return awaitFound(ctx, func() (uint32, bool, error) { return p.d.GetOriginFromIP(ip) })
+}
+
+func (p plainDecoder) GetASPathFromIPContext(ctx context.Context, ip net.IP) (ASPath, bool, error) {
+	return awaitFound(ctx, func() (ASPath, bool, error) { return p.d.GetASPathFromIP(ip) })
+}
+
+func (p plainDecoder) GetRouteContext(ctx context.Context, ip net.IP) (*net.IPNet, bool, error) {
+	return awaitFound(ctx, func() (*net.IPNet, bool, error) { return p.d.GetRoute(ip) })
+}
+
+func (p plainDecoder) GetROAContext(ctx context.Context, prefix *net.IPNet, asn uint32) (int, bool, error) {
+	return awaitFound(ctx, func() (int, bool, error) { return p.d.GetROA(prefix, asn) })
+}
+
+func (p plainDecoder) GetVRPsContext(ctx context.Context, asn uint32) ([]VRP, error) {
+	return await(ctx, func() ([]VRP, error) { return p.d.GetVRPs(asn) })
+}
+
+func (p plainDecoder) GetInvalidsContext(ctx context.Context) (map[string][]string, error) {
+	return await(ctx, p.d.GetInvalids)
+}
@@ -0,0 +1,48 @@
+package clidecode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// slowDecoder answers GetOriginFromIP at once and GetBGPTotal after delay.
+// Other methods are left to the nil embedded Decoder.
+type slowDecoder struct {
+	Decoder
+	delay time.Duration
+}
+
+func (s slowDecoder) GetBGPTotal() (Totals, error) {
+	time.Sleep(s.delay)
+	return Totals{}, nil
+}
+
+func (s slowDecoder) GetOriginFromIP(net.IP) (uint32, bool, error) {
+	return 15169, true, nil
+}
+
+func TestWithContext(t *testing.T) {
+	b := &BirdClient{}
+	if WithContext(b) != DecoderContext(b) {
+		t.Error("Expected a DecoderContext to be returned as is")
+	}
+
+	d := WithContext(slowDecoder{delay: time.Second})
+	asn, found, err := d.GetOriginFromIPContext(context.Background(), net.ParseIP("8.8.8.8"))
+	if err != nil || !found || asn != 15169 {
+		t.Errorf("GetOriginFromIPContext returned %d, %v, %v", asn, found, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := d.GetBGPTotalContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Expected the call to be abandoned at the deadline")
+	}
+}